@@ -0,0 +1,90 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"sort"
+
+	"github.com/gin-gonic/gin"
+	"github.com/tinkubot/wa-gateway/internal/webhook"
+)
+
+// ABTestManager starts and stops the A/B tests of the AI service webhook;
+// see webhook.WebhookClient.
+type ABTestManager interface {
+	ABTests() map[string]webhook.ABTest
+	SetABTest(accountID string, test webhook.ABTest) (webhook.ABTest, error)
+	DeleteABTest(accountID string) error
+}
+
+// ABTestRequest is the body of PUT /api/ab-tests/:accountId.
+type ABTestRequest struct {
+	VariantURL string  `json:"variant_url" binding:"required"`
+	Percentage float64 `json:"percentage" binding:"required"`
+}
+
+// ABTestInfo is an account's A/B test as returned by the API.
+type ABTestInfo struct {
+	AccountID  string  `json:"account_id"`
+	VariantURL string  `json:"variant_url"`
+	Percentage float64 `json:"percentage"`
+}
+
+// GetABTests lists the active A/B tests.
+func (h *Handlers) GetABTests(c *gin.Context) {
+	if h.abTests == nil {
+		RespondError(c, http.StatusServiceUnavailable, "WEBHOOK_UNAVAILABLE", "no AI service webhook is configured")
+		return
+	}
+	tests := h.abTests.ABTests()
+	infos := make([]ABTestInfo, 0, len(tests))
+	for accountID, test := range tests {
+		infos = append(infos, ABTestInfo{AccountID: accountID, VariantURL: test.VariantURL, Percentage: test.Percentage})
+	}
+	sort.Slice(infos, func(i, j int) bool { return infos[i].AccountID < infos[j].AccountID })
+	Respond(c, http.StatusOK, gin.H{"ab_tests": infos})
+}
+
+// PutABTest starts or replaces the A/B test of an account.
+func (h *Handlers) PutABTest(c *gin.Context) {
+	if h.abTests == nil {
+		RespondError(c, http.StatusServiceUnavailable, "WEBHOOK_UNAVAILABLE", "no AI service webhook is configured")
+		return
+	}
+	var req ABTestRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		RespondError(c, http.StatusBadRequest, "INVALID_REQUEST", err.Error())
+		return
+	}
+	accountID := c.Param("accountId")
+	test, err := h.abTests.SetABTest(accountID, webhook.ABTest{VariantURL: req.VariantURL, Percentage: req.Percentage})
+	if err != nil {
+		writeABTestError(c, err)
+		return
+	}
+	Respond(c, http.StatusOK, ABTestInfo{AccountID: accountID, VariantURL: test.VariantURL, Percentage: test.Percentage})
+}
+
+// DeleteABTest stops the A/B test of an account.
+func (h *Handlers) DeleteABTest(c *gin.Context) {
+	if h.abTests == nil {
+		RespondError(c, http.StatusServiceUnavailable, "WEBHOOK_UNAVAILABLE", "no AI service webhook is configured")
+		return
+	}
+	if err := h.abTests.DeleteABTest(c.Param("accountId")); err != nil {
+		writeABTestError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+func writeABTestError(c *gin.Context, err error) {
+	status, code := http.StatusInternalServerError, "INTERNAL_ERROR"
+	switch {
+	case errors.Is(err, webhook.ErrABTestNotFound):
+		status, code = http.StatusNotFound, "AB_TEST_NOT_FOUND"
+	case errors.Is(err, webhook.ErrInvalidABTest):
+		status, code = http.StatusBadRequest, "INVALID_AB_TEST"
+	}
+	RespondError(c, status, code, err.Error())
+}
@@ -0,0 +1,126 @@
+package api
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/tinkubot/wa-gateway/internal/outbound"
+)
+
+// AccountInfo describes an account routed via the Meta Cloud API.
+type AccountInfo struct {
+	AccountID     string `json:"account_id"`
+	PhoneNumberID string `json:"phone_number_id"`
+	Status        string `json:"status"`
+	LastSeenAt    string `json:"last_seen_at,omitempty"`
+	FailingSince  string `json:"failing_since,omitempty"`
+}
+
+func accountInfo(status outbound.AccountStatus) AccountInfo {
+	info := AccountInfo{
+		AccountID:     status.AccountID,
+		PhoneNumberID: status.PhoneNumberID,
+		Status:        status.Status,
+	}
+	if !status.LastSeenAt.IsZero() {
+		info.LastSeenAt = status.LastSeenAt.UTC().Format(time.RFC3339)
+	}
+	if !status.FailingSince.IsZero() {
+		info.FailingSince = status.FailingSince.UTC().Format(time.RFC3339)
+	}
+	return info
+}
+
+// Page sizes for GetAccounts.
+const (
+	defaultAccountsLimit = 20
+	maxAccountsLimit     = 100
+)
+
+// GetAccounts lists the accounts routed via Meta and their reachability,
+// ordered by account ID. It pages with ?limit=N&cursor=<last account_id>;
+// next_cursor is the cursor for the following page.
+func (h *Handlers) GetAccounts(c *gin.Context) {
+	limit := defaultAccountsLimit
+	if raw := c.Query("limit"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed <= 0 || parsed > maxAccountsLimit {
+			RespondError(c, http.StatusBadRequest, "INVALID_REQUEST", "limit must be between 1 and "+strconv.Itoa(maxAccountsLimit))
+			return
+		}
+		limit = parsed
+	}
+	cursor := c.Query("cursor")
+
+	accounts := []AccountInfo{}
+	hasMore := false
+	for _, status := range h.outbound.AccountStatuses() {
+		if cursor != "" && status.AccountID <= cursor {
+			continue
+		}
+		if len(accounts) == limit {
+			hasMore = true
+			break
+		}
+		accounts = append(accounts, accountInfo(status))
+	}
+	nextCursor := ""
+	if hasMore {
+		nextCursor = accounts[len(accounts)-1].AccountID
+	}
+	respondWithETag(c, gin.H{
+		"accounts":    accounts,
+		"next_cursor": nextCursor,
+		"has_more":    hasMore,
+	})
+}
+
+// GetAccount returns a single account routed via Meta.
+func (h *Handlers) GetAccount(c *gin.Context) {
+	accountID := c.Param("accountId")
+	for _, status := range h.outbound.AccountStatuses() {
+		if status.AccountID == accountID {
+			respondWithETag(c, accountInfo(status))
+			return
+		}
+	}
+	RespondError(c, http.StatusNotFound, "ACCOUNT_NOT_FOUND", "account is not routed via Meta: "+accountID)
+}
+
+// respondWithETag responds with data and an ETag derived from it, or 304
+// Not Modified when the request's If-None-Match already matches. The ETag
+// covers data and the negotiated format, not the envelope's request ID and
+// timestamp, so JSON and XML representations get different ETags.
+func respondWithETag(c *gin.Context, body any) {
+	data, err := json.Marshal(body)
+	if err != nil {
+		RespondError(c, http.StatusInternalServerError, "ENCODING_FAILED", err.Error())
+		return
+	}
+	sum := sha256.Sum256(append([]byte(c.GetString(responseFormatKey)+"\n"), data...))
+	etag := `"` + hex.EncodeToString(sum[:16]) + `"`
+
+	c.Header("ETag", etag)
+	if etagMatches(c.GetHeader("If-None-Match"), etag) {
+		c.Status(http.StatusNotModified)
+		return
+	}
+	Respond(c, http.StatusOK, json.RawMessage(data))
+}
+
+// etagMatches implements If-None-Match's weak comparison.
+func etagMatches(ifNoneMatch, etag string) bool {
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,147 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/tinkubot/wa-gateway/internal/metaoutbound"
+	"github.com/tinkubot/wa-gateway/internal/outbound"
+	"github.com/tinkubot/wa-gateway/internal/ratelimit"
+)
+
+func TestGetAccountsETag(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	metaSender := &fakeMetaSender{
+		statuses: map[string]metaoutbound.PhoneNumberStatus{
+			"12345": {PhoneNumberID: "12345", Status: metaoutbound.PhoneNumberConnected, LastSeenAt: time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)},
+		},
+	}
+	router := outbound.NewRouter(metaSender, outbound.RouterConfig{
+		MetaOutboundEnabled: true,
+		AccountPhoneNumber: map[string]string{
+			"bot-clientes":    "12345",
+			"bot-proveedores": "67890",
+		},
+	})
+	engine := gin.New()
+	RegisterRoutes(engine, NewHandlers(ratelimit.NewLimiter(ratelimit.Config{}), nil, router, HandlerConfig{}))
+	getAs := func(path, ifNoneMatch, accept string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		if accept != "" {
+			req.Header.Set("Accept", accept)
+		}
+		if ifNoneMatch != "" {
+			req.Header.Set("If-None-Match", ifNoneMatch)
+		}
+		rec := httptest.NewRecorder()
+		engine.ServeHTTP(rec, req)
+		return rec
+	}
+	get := func(path, ifNoneMatch string) *httptest.ResponseRecorder { return getAs(path, ifNoneMatch, "") }
+
+	rec := get("/api/accounts", "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rec.Code)
+	}
+	var payload struct {
+		Data struct {
+			Accounts []AccountInfo `json:"accounts"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &payload); err != nil {
+		t.Fatalf("unmarshal accounts: %v", err)
+	}
+	accounts := payload.Data.Accounts
+	if len(accounts) != 2 || accounts[0].AccountID != "bot-clientes" || accounts[0].LastSeenAt != "2026-01-02T03:04:05Z" {
+		t.Fatalf("unexpected accounts %+v", accounts)
+	}
+	etag := rec.Header().Get("ETag")
+	if etag == "" {
+		t.Fatal("expected ETag header")
+	}
+
+	if rec := get("/api/accounts", etag); rec.Code != http.StatusNotModified || rec.Body.Len() != 0 {
+		t.Fatalf("expected empty 304 for matching ETag, got %d body=%q", rec.Code, rec.Body.String())
+	}
+	if rec := get("/api/accounts", `"other", W/`+etag); rec.Code != http.StatusNotModified {
+		t.Fatalf("expected 304 for weak ETag in list, got %d", rec.Code)
+	}
+	xmlRec := getAs("/api/accounts", etag, "application/xml")
+	if xmlRec.Code != http.StatusOK || xmlRec.Header().Get("ETag") == etag {
+		t.Fatalf("expected xml representation to get its own ETag, got %d etag=%s", xmlRec.Code, xmlRec.Header().Get("ETag"))
+	}
+	if rec := getAs("/api/accounts", xmlRec.Header().Get("ETag"), "application/xml"); rec.Code != http.StatusNotModified {
+		t.Fatalf("expected 304 for matching xml ETag, got %d", rec.Code)
+	}
+
+	metaSender.statuses["12345"] = metaoutbound.PhoneNumberStatus{PhoneNumberID: "12345", Status: metaoutbound.PhoneNumberFailing}
+	rec = get("/api/accounts", etag)
+	if rec.Code != http.StatusOK || rec.Header().Get("ETag") == etag {
+		t.Fatalf("expected new ETag after status change, got %d etag=%s", rec.Code, rec.Header().Get("ETag"))
+	}
+
+	rec = get("/api/accounts/bot-proveedores", "")
+	if rec.Code != http.StatusOK || rec.Header().Get("ETag") == "" {
+		t.Fatalf("expected 200 with ETag for single account, got %d", rec.Code)
+	}
+	if rec := get("/api/accounts/bot-proveedores", rec.Header().Get("ETag")); rec.Code != http.StatusNotModified {
+		t.Fatalf("expected 304 for unchanged account, got %d", rec.Code)
+	}
+	if rec := get("/api/accounts/bot-desconocido", ""); rec.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 for unknown account, got %d", rec.Code)
+	}
+}
+
+func TestGetAccountsPagination(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := outbound.NewRouter(&fakeMetaSender{}, outbound.RouterConfig{
+		MetaOutboundEnabled: true,
+		AccountPhoneNumber: map[string]string{
+			"bot-a": "1",
+			"bot-b": "2",
+			"bot-c": "3",
+		},
+	})
+	engine := gin.New()
+	RegisterRoutes(engine, NewHandlers(ratelimit.NewLimiter(ratelimit.Config{}), nil, router, HandlerConfig{}))
+
+	type page struct {
+		Accounts   []AccountInfo `json:"accounts"`
+		NextCursor string        `json:"next_cursor"`
+		HasMore    bool          `json:"has_more"`
+	}
+	get := func(query string) (int, page) {
+		rec := httptest.NewRecorder()
+		engine.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/accounts"+query, nil))
+		var envelope struct {
+			Data page `json:"data"`
+		}
+		if rec.Code == http.StatusOK {
+			if err := json.Unmarshal(rec.Body.Bytes(), &envelope); err != nil {
+				t.Fatalf("unmarshal page: %v", err)
+			}
+		}
+		return rec.Code, envelope.Data
+	}
+
+	code, first := get("?limit=2")
+	if code != http.StatusOK || len(first.Accounts) != 2 || !first.HasMore || first.NextCursor != "bot-b" {
+		t.Fatalf("unexpected first page code=%d %+v", code, first)
+	}
+	code, second := get("?limit=2&cursor=" + first.NextCursor)
+	if code != http.StatusOK || len(second.Accounts) != 1 || second.Accounts[0].AccountID != "bot-c" || second.HasMore || second.NextCursor != "" {
+		t.Fatalf("unexpected second page code=%d %+v", code, second)
+	}
+	if _, all := get(""); len(all.Accounts) != 3 || all.HasMore {
+		t.Fatalf("expected all accounts within default limit, got %+v", all)
+	}
+	for _, query := range []string{"?limit=0", "?limit=101", "?limit=abc"} {
+		if code, _ := get(query); code != http.StatusBadRequest {
+			t.Fatalf("expected 400 for %s, got %d", query, code)
+		}
+	}
+}
@@ -0,0 +1,313 @@
+package api
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/csv"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/tinkubot/wa-gateway/internal/outbound"
+)
+
+// Limits for POST /api/accounts/:accountId/send-bulk-csv.
+const (
+	maxBulkCSVBytes = 5 << 20
+	maxBulkCSVRows  = 10000
+	// bulkJobTTL is how long finished jobs stay available for polling.
+	bulkJobTTL = 24 * time.Hour
+)
+
+// Bulk job states.
+const (
+	BulkJobQueued  = "queued"
+	BulkJobRunning = "running"
+	BulkJobDone    = "done"
+)
+
+// BulkRowError describes a CSV row that was rejected or failed to send.
+// Row is the 1-based record number in the file, header included.
+type BulkRowError struct {
+	Row   int    `json:"row"`
+	To    string `json:"to,omitempty"`
+	Code  string `json:"code,omitempty"`
+	Error string `json:"error"`
+}
+
+// BulkJob is the progress of a CSV bulk send.
+type BulkJob struct {
+	JobID       string         `json:"job_id"`
+	AccountID   string         `json:"account_id"`
+	Status      string         `json:"status"`
+	TotalRows   int            `json:"total_rows"`
+	ValidRows   int            `json:"valid_rows"`
+	InvalidRows int            `json:"invalid_rows"`
+	Sent        int            `json:"sent"`
+	Failed      int            `json:"failed"`
+	Errors      []BulkRowError `json:"errors"`
+	CreatedAt   time.Time      `json:"created_at"`
+	FinishedAt  *time.Time     `json:"finished_at,omitempty"`
+}
+
+type bulkRow struct {
+	row     int
+	to      string
+	message string
+}
+
+// bulkJobStore keeps bulk jobs in memory; finished jobs are dropped after
+// bulkJobTTL.
+type bulkJobStore struct {
+	mu   sync.Mutex
+	jobs map[string]*BulkJob
+}
+
+func newBulkJobStore() *bulkJobStore {
+	return &bulkJobStore{jobs: make(map[string]*BulkJob)}
+}
+
+func (s *bulkJobStore) add(job *BulkJob, now time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for id, existing := range s.jobs {
+		if existing.FinishedAt != nil && now.Sub(*existing.FinishedAt) > bulkJobTTL {
+			delete(s.jobs, id)
+		}
+	}
+	s.jobs[job.JobID] = job
+}
+
+// get returns a copy of the job so callers can read it without the lock.
+func (s *bulkJobStore) get(jobID string) (BulkJob, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	job, ok := s.jobs[jobID]
+	if !ok {
+		return BulkJob{}, false
+	}
+	snapshot := *job
+	snapshot.Errors = append([]BulkRowError(nil), job.Errors...)
+	return snapshot, true
+}
+
+func (s *bulkJobStore) update(jobID string, fn func(job *BulkJob)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if job, ok := s.jobs[jobID]; ok {
+		fn(job)
+	}
+}
+
+// PostSendBulkCSV queues one text message per row of an uploaded CSV file
+// (multipart field "file") with to and message columns. A header row naming
+// the columns is optional; without one they are read in that order. Invalid
+// rows are reported and skipped, and the rest are sent in the background.
+// Progress is available from GetBulkJob.
+func (h *Handlers) PostSendBulkCSV(c *gin.Context) {
+	accountID := c.Param("accountId")
+	if !h.accountRouted(accountID) {
+		RespondError(c, http.StatusNotFound, "ACCOUNT_NOT_FOUND", "account is not routed via Meta: "+accountID)
+		return
+	}
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxBulkCSVBytes)
+	header, err := c.FormFile("file")
+	if err != nil {
+		RespondError(c, http.StatusBadRequest, "INVALID_REQUEST", "file is required: "+err.Error())
+		return
+	}
+	file, err := header.Open()
+	if err != nil {
+		RespondError(c, http.StatusBadRequest, "INVALID_REQUEST", err.Error())
+		return
+	}
+	defer file.Close()
+
+	rows, rowErrors, total, err := parseBulkCSV(file)
+	if err != nil {
+		RespondError(c, http.StatusBadRequest, "INVALID_REQUEST", err.Error())
+		return
+	}
+	now := time.Now()
+	job := &BulkJob{
+		JobID:       newBulkJobID(),
+		AccountID:   accountID,
+		Status:      BulkJobQueued,
+		TotalRows:   total,
+		ValidRows:   len(rows),
+		InvalidRows: len(rowErrors),
+		Errors:      rowErrors,
+		CreatedAt:   now.UTC(),
+	}
+	if len(rows) == 0 {
+		writeResponse(c, http.StatusBadRequest, APIResponse{
+			Error: "csv has no valid rows",
+			Code:  "INVALID_REQUEST",
+			Data:  job,
+		})
+		return
+	}
+	h.bulkJobs.add(job, now)
+	log.Printf("[PostSendBulkCSV] job_queued job_id=%s account=%s total=%d valid=%d invalid=%d", job.JobID, accountID, total, len(rows), len(rowErrors))
+
+	snapshot, _ := h.bulkJobs.get(job.JobID)
+	go h.runBulkJob(job.JobID, accountID, rows)
+	Respond(c, http.StatusAccepted, snapshot)
+}
+
+// GetBulkJob returns the progress of a CSV bulk send.
+func (h *Handlers) GetBulkJob(c *gin.Context) {
+	job, ok := h.bulkJobs.get(c.Param("jobId"))
+	if !ok || job.AccountID != c.Param("accountId") {
+		RespondError(c, http.StatusNotFound, "JOB_NOT_FOUND", "bulk job not found: "+c.Param("jobId"))
+		return
+	}
+	Respond(c, http.StatusOK, job)
+}
+
+// parseBulkCSV reads the to/message rows of a bulk CSV. total counts data
+// rows, valid or not.
+func parseBulkCSV(r io.Reader) (rows []bulkRow, rowErrors []BulkRowError, total int, err error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	toCol, messageCol := 0, 1
+	rowErrors = []BulkRowError{}
+	for record := 1; ; record++ {
+		fields, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			var parseErr *csv.ParseError
+			if !errors.As(err, &parseErr) {
+				return nil, nil, 0, fmt.Errorf("read csv: %w", err)
+			}
+			total++
+			rowErrors = append(rowErrors, BulkRowError{Row: record, Code: "INVALID_ROW", Error: parseErr.Err.Error()})
+			continue
+		}
+		if record == 1 {
+			if to, message, ok := bulkCSVHeader(fields); ok {
+				toCol, messageCol = to, message
+				continue
+			}
+		}
+		total++
+		if total > maxBulkCSVRows {
+			return nil, nil, 0, fmt.Errorf("csv has more than %d rows", maxBulkCSVRows)
+		}
+		row := bulkRow{row: record}
+		if toCol < len(fields) {
+			row.to = strings.TrimSpace(fields[toCol])
+		}
+		if messageCol < len(fields) {
+			row.message = strings.TrimSpace(fields[messageCol])
+		}
+		switch {
+		case !outbound.ValidDestination(row.to):
+			rowErrors = append(rowErrors, BulkRowError{Row: record, To: row.to, Code: "INVALID_PHONE", Error: "to is not a valid phone number"})
+		case row.message == "":
+			rowErrors = append(rowErrors, BulkRowError{Row: record, To: row.to, Code: "INVALID_ROW", Error: "message is empty"})
+		default:
+			rows = append(rows, row)
+		}
+	}
+	return rows, rowErrors, total, nil
+}
+
+// bulkCSVHeader returns the column positions when fields is a header row.
+func bulkCSVHeader(fields []string) (toCol, messageCol int, ok bool) {
+	toCol, messageCol = -1, -1
+	for i, field := range fields {
+		switch strings.ToLower(strings.TrimSpace(strings.TrimPrefix(field, "\ufeff"))) {
+		case "to":
+			toCol = i
+		case "message":
+			messageCol = i
+		}
+	}
+	return toCol, messageCol, toCol >= 0 && messageCol >= 0
+}
+
+// runBulkJob sends the rows of a bulk job with the same per-recipient rate
+// limits as a to_list send. It runs detached from the upload request.
+func (h *Handlers) runBulkJob(jobID, accountID string, rows []bulkRow) {
+	ctx := context.Background()
+	h.bulkJobs.update(jobID, func(job *BulkJob) { job.Status = BulkJobRunning })
+
+	jobs := make(chan bulkRow)
+	var wg sync.WaitGroup
+	for w := 0; w < min(sendWorkers, len(rows)); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for row := range jobs {
+				rowErr := h.sendBulkRow(ctx, accountID, row)
+				h.bulkJobs.update(jobID, func(job *BulkJob) {
+					if rowErr == nil {
+						job.Sent++
+						return
+					}
+					job.Failed++
+					job.Errors = append(job.Errors, *rowErr)
+				})
+			}
+		}()
+	}
+	for _, row := range rows {
+		jobs <- row
+	}
+	close(jobs)
+	wg.Wait()
+
+	finished := time.Now().UTC()
+	var sent, failed int
+	h.bulkJobs.update(jobID, func(job *BulkJob) {
+		job.Status = BulkJobDone
+		job.FinishedAt = &finished
+		sent, failed = job.Sent, job.Failed
+	})
+	log.Printf("[PostSendBulkCSV] job_done job_id=%s account=%s sent=%d failed=%d", jobID, accountID, sent, failed)
+}
+
+func (h *Handlers) sendBulkRow(ctx context.Context, accountID string, row bulkRow) *BulkRowError {
+	req := SendMessageRequest{AccountID: accountID, To: row.to, Message: row.message}
+	allowed, _, decision, err := h.rateLimiter.Check(ctx, accountID, row.to)
+	if !allowed {
+		h.recordRateLimitHit(ctx, req, decision)
+		return &BulkRowError{Row: row.row, To: row.to, Code: "RATE_LIMIT_EXCEEDED", Error: errorMessage(err)}
+	}
+	if _, err := h.sendOne(ctx, req, row.to, nil); err != nil {
+		_, code := sendErrorStatus(err)
+		return &BulkRowError{Row: row.row, To: row.to, Code: code, Error: err.Error()}
+	}
+	if err := h.rateLimiter.Increment(ctx, accountID, row.to); err != nil {
+		log.Printf("[PostSendBulkCSV] rate_limit_increment_failed account=%s to=%s err=%v", accountID, row.to, err)
+	}
+	return nil
+}
+
+// accountRouted reports whether accountID is routed via Meta.
+func (h *Handlers) accountRouted(accountID string) bool {
+	for _, status := range h.outbound.AccountStatuses() {
+		if status.AccountID == accountID {
+			return true
+		}
+	}
+	return false
+}
+
+func newBulkJobID() string {
+	var raw [8]byte
+	rand.Read(raw[:])
+	return "bulk_" + hex.EncodeToString(raw[:])
+}
@@ -0,0 +1,155 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/tinkubot/wa-gateway/internal/outbound"
+	"github.com/tinkubot/wa-gateway/internal/ratelimit"
+)
+
+func TestParseBulkCSV(t *testing.T) {
+	csvData := "message,to\n" +
+		"Hola,593999111222\n" +
+		"\"Aviso, importante\",+593 99 911 1333\n" +
+		"Sin numero,12\n" +
+		",593999111444\n"
+	rows, rowErrors, total, err := parseBulkCSV(strings.NewReader(csvData))
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	if total != 4 || len(rows) != 2 || len(rowErrors) != 2 {
+		t.Fatalf("expected 4 rows with 2 valid, got total=%d rows=%+v errors=%+v", total, rows, rowErrors)
+	}
+	if rows[1].to != "+593 99 911 1333" || rows[1].message != "Aviso, importante" || rows[1].row != 3 {
+		t.Fatalf("unexpected row %+v", rows[1])
+	}
+	if rowErrors[0].Row != 4 || rowErrors[0].Code != "INVALID_PHONE" || rowErrors[1].Row != 5 || rowErrors[1].Code != "INVALID_ROW" {
+		t.Fatalf("unexpected row errors %+v", rowErrors)
+	}
+
+	rows, _, total, err = parseBulkCSV(strings.NewReader("593999111222,Hola\n"))
+	if err != nil || total != 1 || len(rows) != 1 || rows[0].message != "Hola" {
+		t.Fatalf("expected headerless to,message row, got rows=%+v total=%d err=%v", rows, total, err)
+	}
+}
+
+func TestPostSendBulkCSV(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	metaSender := &fakeMetaSender{}
+	router := outbound.NewRouter(metaSender, outbound.RouterConfig{
+		MetaOutboundEnabled: true,
+		AccountPhoneNumber:  map[string]string{"bot-clientes": "12345"},
+	})
+	handlers := NewHandlers(ratelimit.NewLimiter(ratelimit.Config{MaxPerHour: 20, MaxPer24h: 100}), nil, router, HandlerConfig{})
+	engine := gin.New()
+	engine.POST("/accounts/:accountId/send-bulk-csv", handlers.PostSendBulkCSV)
+	engine.GET("/accounts/:accountId/bulk-jobs/:jobId", handlers.GetBulkJob)
+
+	upload := func(accountID, csvData string) *httptest.ResponseRecorder {
+		var body bytes.Buffer
+		form := multipart.NewWriter(&body)
+		part, err := form.CreateFormFile("file", "campaign.csv")
+		if err != nil {
+			t.Fatalf("create file part: %v", err)
+		}
+		part.Write([]byte(csvData))
+		form.Close()
+		req := httptest.NewRequest(http.MethodPost, "/accounts/"+accountID+"/send-bulk-csv", &body)
+		req.Header.Set("Content-Type", form.FormDataContentType())
+		rec := httptest.NewRecorder()
+		engine.ServeHTTP(rec, req)
+		return rec
+	}
+	decode := func(rec *httptest.ResponseRecorder) BulkJob {
+		var envelope struct {
+			Data BulkJob `json:"data"`
+		}
+		if err := json.Unmarshal(rec.Body.Bytes(), &envelope); err != nil {
+			t.Fatalf("unmarshal response: %v", err)
+		}
+		return envelope.Data
+	}
+
+	rec := upload("bot-clientes", "to,message\n593999111222,Hola\n593999111333,Promo\nabc,Hola\n")
+	if rec.Code != http.StatusAccepted {
+		t.Fatalf("expected 202, got %d body=%s", rec.Code, rec.Body.String())
+	}
+	job := decode(rec)
+	if job.JobID == "" || job.TotalRows != 3 || job.ValidRows != 2 || job.InvalidRows != 1 || len(job.Errors) != 1 {
+		t.Fatalf("unexpected job summary %+v", job)
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for job.Status != BulkJobDone {
+		if time.Now().After(deadline) {
+			t.Fatalf("job did not finish, last state %+v", job)
+		}
+		time.Sleep(10 * time.Millisecond)
+		rec = httptest.NewRecorder()
+		engine.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/accounts/bot-clientes/bulk-jobs/"+job.JobID, nil))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected 200 polling job, got %d", rec.Code)
+		}
+		job = decode(rec)
+	}
+	if job.Sent != 2 || job.Failed != 0 || job.FinishedAt == nil {
+		t.Fatalf("expected 2 sent, got %+v", job)
+	}
+	metaSender.mu.Lock()
+	textCalls := metaSender.textCalls
+	metaSender.mu.Unlock()
+	if textCalls != 2 {
+		t.Fatalf("expected 2 text sends, got %d", textCalls)
+	}
+
+	if rec := upload("bot-clientes", "to,message\nabc,Hola\n"); rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 without valid rows, got %d", rec.Code)
+	}
+	if rec := upload("bot-unknown", "593999111222,Hola\n"); rec.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 for unknown account, got %d", rec.Code)
+	}
+}
+
+func TestPostSendBulkCSVReplaysIdempotentUploads(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := outbound.NewRouter(&fakeMetaSender{}, outbound.RouterConfig{
+		MetaOutboundEnabled: true,
+		AccountPhoneNumber:  map[string]string{"bot-clientes": "12345"},
+	})
+	engine := gin.New()
+	RegisterRoutes(engine, NewHandlers(ratelimit.NewLimiter(ratelimit.Config{MaxPerHour: 20, MaxPer24h: 100}), nil, router, HandlerConfig{}))
+	upload := func() *httptest.ResponseRecorder {
+		var body bytes.Buffer
+		form := multipart.NewWriter(&body)
+		part, err := form.CreateFormFile("file", "campaign.csv")
+		if err != nil {
+			t.Fatalf("create file part: %v", err)
+		}
+		part.Write([]byte("593999111222,Hola\n"))
+		form.Close()
+		req := httptest.NewRequest(http.MethodPost, "/api/accounts/bot-clientes/send-bulk-csv", &body)
+		req.RemoteAddr = "10.0.0.5:1234"
+		req.Header.Set("Content-Type", form.FormDataContentType())
+		req.Header.Set(IdempotencyKeyHeader, "campaign-1")
+		rec := httptest.NewRecorder()
+		engine.ServeHTTP(rec, req)
+		return rec
+	}
+
+	first := upload()
+	second := upload()
+	if first.Code != http.StatusAccepted || second.Code != http.StatusAccepted {
+		t.Fatalf("expected 202 twice, got %d and %d", first.Code, second.Code)
+	}
+	if second.Header().Get("Idempotent-Replayed") != "true" || second.Body.String() != first.Body.String() {
+		t.Fatalf("expected the repeated upload to replay the first job, got %s", second.Body.String())
+	}
+}
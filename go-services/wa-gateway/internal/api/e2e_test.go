@@ -0,0 +1,131 @@
+package api
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/tinkubot/wa-gateway/internal/metawebhook"
+	"github.com/tinkubot/wa-gateway/internal/outbound"
+	"github.com/tinkubot/wa-gateway/internal/ratelimit"
+)
+
+func newE2EServer(t *testing.T) (*httptest.Server, *fakeMetaSender) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	metaSender := &fakeMetaSender{}
+	router := outbound.NewRouter(
+		metaSender,
+		outbound.RouterConfig{
+			MetaOutboundEnabled: true,
+			AccountPhoneNumber:  map[string]string{"bot-clientes": "12345"},
+		},
+	)
+	metaSvc := metawebhook.NewService(
+		metawebhook.Config{
+			Enabled:     true,
+			VerifyToken: "verify-1",
+			AppSecret:   "secret-1",
+		},
+		nil,
+		nil,
+		nil,
+	)
+	handlers := NewHandlers(
+		ratelimit.NewLimiter(ratelimit.Config{MaxPerHour: 20, MaxPer24h: 100}),
+		metaSvc,
+		router,
+		HandlerConfig{},
+	)
+
+	engine := gin.New()
+	RegisterRoutes(engine, handlers)
+	srv := httptest.NewServer(engine)
+	t.Cleanup(srv.Close)
+	return srv, metaSender
+}
+
+func TestE2ERoutes(t *testing.T) {
+	srv, metaSender := newE2EServer(t)
+	sendBody := `{"account_id":"bot-clientes","to":"593999111222","message":"Hola"}`
+
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		body       string
+		wantStatus int
+		wantKeys   []string
+		wantData   []string
+		wantBody   string
+	}{
+		{name: "liveness", method: http.MethodGet, path: "/healthz/live", wantStatus: http.StatusOK, wantKeys: []string{"status"}},
+		{name: "readiness", method: http.MethodGet, path: "/healthz/ready", wantStatus: http.StatusOK, wantKeys: []string{"status", "ready_accounts", "total_accounts"}},
+		{name: "health", method: http.MethodGet, path: "/health", wantStatus: http.StatusOK, wantKeys: []string{"status", "service", "version", "timestamp", "dependencies"}},
+		{name: "deep health", method: http.MethodGet, path: "/health/deep", wantStatus: http.StatusOK, wantKeys: []string{"ok", "timeout_ms", "dependencies"}},
+		{name: "send", method: http.MethodPost, path: "/send", body: sendBody, wantStatus: http.StatusOK, wantKeys: []string{"success", "data", "request_id", "timestamp"}, wantData: []string{"message_id", "to_phone"}},
+		{name: "api send", method: http.MethodPost, path: "/api/send", body: sendBody, wantStatus: http.StatusOK, wantKeys: []string{"success", "data", "request_id", "timestamp"}, wantData: []string{"message_id", "to_phone"}},
+		{name: "send stats", method: http.MethodGet, path: "/api/accounts/bot-clientes/send-stats", wantStatus: http.StatusOK, wantKeys: []string{"success", "data"}, wantData: []string{"account_id", "attempts", "success_rate", "failures_by_type", "latency_ms"}},
+		{name: "send stats unknown account", method: http.MethodGet, path: "/api/accounts/bot-desconocido/send-stats", wantStatus: http.StatusNotFound, wantKeys: []string{"error", "code"}},
+		{name: "send invalid", method: http.MethodPost, path: "/send", body: `{}`, wantStatus: http.StatusBadRequest, wantKeys: []string{"error", "code", "request_id"}},
+		{name: "meta verify", method: http.MethodGet, path: "/meta/webhook?hub.mode=subscribe&hub.verify_token=verify-1&hub.challenge=abc", wantStatus: http.StatusOK, wantBody: "abc"},
+		{name: "meta verify wrong token", method: http.MethodGet, path: "/meta/webhook?hub.mode=subscribe&hub.verify_token=wrong&hub.challenge=abc", wantStatus: http.StatusForbidden},
+		{name: "meta event unsigned", method: http.MethodPost, path: "/meta/webhook", body: `{"object":"whatsapp_business_account"}`, wantStatus: http.StatusUnauthorized, wantKeys: []string{"error"}},
+		{name: "unknown route", method: http.MethodGet, path: "/accounts", wantStatus: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := http.NewRequest(tt.method, srv.URL+tt.path, strings.NewReader(tt.body))
+			if err != nil {
+				t.Fatalf("new request: %v", err)
+			}
+			if tt.body != "" {
+				req.Header.Set("Content-Type", "application/json")
+			}
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatalf("request failed: %v", err)
+			}
+			defer resp.Body.Close()
+			raw, err := io.ReadAll(resp.Body)
+			if err != nil {
+				t.Fatalf("read body: %v", err)
+			}
+
+			if resp.StatusCode != tt.wantStatus {
+				t.Fatalf("expected status %d, got %d body=%s", tt.wantStatus, resp.StatusCode, raw)
+			}
+			if tt.wantBody != "" && string(raw) != tt.wantBody {
+				t.Fatalf("expected body %q, got %q", tt.wantBody, raw)
+			}
+			if len(tt.wantKeys) == 0 {
+				return
+			}
+			var payload map[string]any
+			if err := json.Unmarshal(raw, &payload); err != nil {
+				t.Fatalf("expected JSON body, got %q: %v", raw, err)
+			}
+			for _, key := range tt.wantKeys {
+				if _, ok := payload[key]; !ok {
+					t.Fatalf("expected key %q in response, got %+v", key, payload)
+				}
+			}
+			data, _ := payload["data"].(map[string]any)
+			for _, key := range tt.wantData {
+				if _, ok := data[key]; !ok {
+					t.Fatalf("expected key %q in response data, got %+v", key, payload)
+				}
+			}
+		})
+	}
+
+	if metaSender.textCalls != 2 {
+		t.Fatalf("expected 2 text sends via /send and /api/send, got %d", metaSender.textCalls)
+	}
+}
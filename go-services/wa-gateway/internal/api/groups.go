@@ -0,0 +1,113 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/tinkubot/wa-gateway/internal/outbound"
+)
+
+// CreateGroupRequest is the body of POST /api/groups.
+type CreateGroupRequest struct {
+	GroupID  string   `json:"group_id" binding:"required"`
+	Accounts []string `json:"accounts"`
+}
+
+// GroupMemberRequest is the body of POST /api/groups/:groupId/members.
+type GroupMemberRequest struct {
+	AccountID string `json:"account_id" binding:"required"`
+}
+
+// GroupSendRequest is the body of POST /api/groups/:groupId/send.
+type GroupSendRequest struct {
+	Recipients []string `json:"recipients" binding:"required,min=1"`
+	Message    string   `json:"message" binding:"required"`
+}
+
+// GetGroups lists all account groups.
+func (h *Handlers) GetGroups(c *gin.Context) {
+	Respond(c, http.StatusOK, gin.H{"groups": h.groups.List()})
+}
+
+// PostGroup creates an account group.
+func (h *Handlers) PostGroup(c *gin.Context) {
+	var req CreateGroupRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		RespondError(c, http.StatusBadRequest, "INVALID_REQUEST", err.Error())
+		return
+	}
+	group, err := h.groups.Create(req.GroupID, req.Accounts)
+	if err != nil {
+		writeGroupError(c, err)
+		return
+	}
+	Respond(c, http.StatusCreated, group)
+}
+
+// DeleteGroup removes an account group.
+func (h *Handlers) DeleteGroup(c *gin.Context) {
+	if err := h.groups.Delete(c.Param("groupId")); err != nil {
+		writeGroupError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// PostGroupMember adds an account to a group.
+func (h *Handlers) PostGroupMember(c *gin.Context) {
+	var req GroupMemberRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		RespondError(c, http.StatusBadRequest, "INVALID_REQUEST", err.Error())
+		return
+	}
+	group, err := h.groups.AddMember(c.Param("groupId"), req.AccountID)
+	if err != nil {
+		writeGroupError(c, err)
+		return
+	}
+	Respond(c, http.StatusOK, group)
+}
+
+// DeleteGroupMember removes an account from a group.
+func (h *Handlers) DeleteGroupMember(c *gin.Context) {
+	group, err := h.groups.RemoveMember(c.Param("groupId"), c.Param("accountId"))
+	if err != nil {
+		writeGroupError(c, err)
+		return
+	}
+	Respond(c, http.StatusOK, group)
+}
+
+// PostGroupSend sends the same text from every member account of a group to
+// every recipient, with the same batch semantics as PostBroadcast.
+func (h *Handlers) PostGroupSend(c *gin.Context) {
+	var req GroupSendRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		RespondError(c, http.StatusBadRequest, "INVALID_REQUEST", err.Error())
+		return
+	}
+	group, err := h.groups.Get(c.Param("groupId"))
+	if err != nil {
+		writeGroupError(c, err)
+		return
+	}
+	if len(group.Accounts) == 0 {
+		RespondError(c, http.StatusConflict, "GROUP_EMPTY", "account group "+group.GroupID+" has no accounts")
+		return
+	}
+	h.broadcast(c, "PostGroupSend", group.Accounts, req.Recipients, req.Message)
+}
+
+func writeGroupError(c *gin.Context, err error) {
+	status, code := http.StatusInternalServerError, "INTERNAL_ERROR"
+	switch {
+	case errors.Is(err, outbound.ErrGroupNotFound):
+		status, code = http.StatusNotFound, "GROUP_NOT_FOUND"
+	case errors.Is(err, outbound.ErrGroupExists):
+		status, code = http.StatusConflict, "GROUP_EXISTS"
+	case errors.Is(err, outbound.ErrInvalidGroup):
+		status, code = http.StatusBadRequest, "INVALID_GROUP"
+	}
+	RespondError(c, status, code, err.Error())
+}
@@ -4,11 +4,14 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"log"
 	"net/http"
+	"sync"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/tinkubot/wa-gateway/internal/metaoutbound"
 	"github.com/tinkubot/wa-gateway/internal/metawebhook"
 	"github.com/tinkubot/wa-gateway/internal/outbound"
 	"github.com/tinkubot/wa-gateway/internal/ratelimit"
@@ -17,48 +20,304 @@ import (
 
 // Handlers holds the dependencies for HTTP handlers
 type Handlers struct {
-	rateLimiter   *ratelimit.Limiter
+	rateLimiter   RateLimiter
 	eventRecorder ratelimit.EventRecorder
 	metaWebhook   *metawebhook.Service
 	outbound      *outbound.Router
+	circuits      CircuitReporter
+	aiHealth      AIHealthChecker
+	webhookTester WebhookTester
+	abTests       ABTestManager
+	regions       RegionManager
+	groups        *outbound.AccountGroups
+	adminIPs      []string
+	idempotency   *IdempotencyStore
+	bulkJobs      *bulkJobStore
+	// unhealthyDisconnect is how long an account may fail before health is degraded.
+	unhealthyDisconnect time.Duration
+	// webhookSimulator and testAPIKey back POST /api/test/simulate-incoming,
+	// which is only mounted when testAPIKey is set.
+	webhookSimulator WebhookSimulator
+	testAPIKey       string
+}
+
+// RateLimiter checks and counts sends per account and destination; see
+// ratelimit.Limiter.
+type RateLimiter interface {
+	Check(ctx context.Context, accountID, destinationPhone string) (bool, time.Duration, ratelimit.Decision, error)
+	Increment(ctx context.Context, accountID, destinationPhone string) error
+}
+
+// CircuitReporter exposes the webhook circuit breaker state for health checks.
+type CircuitReporter interface {
+	CircuitStates() []webhook.CircuitStatus
+}
+
+// AIHealthChecker probes the health endpoints of the AI services.
+type AIHealthChecker interface {
+	Healthcheck(ctx context.Context) map[string]error
+}
+
+// WebhookTester sends a dry-run payload to the AI service of an account.
+type WebhookTester interface {
+	TestWebhook(ctx context.Context, accountID string) (*webhook.TestWebhookResult, error)
 }
 
 type HandlerConfig struct {
-	EventRecorder       ratelimit.EventRecorder
+	EventRecorder   ratelimit.EventRecorder
+	CircuitReporter CircuitReporter
+	AIHealthChecker AIHealthChecker
+	WebhookTester   WebhookTester
+	// ABTestManager backs the /api/ab-tests routes, which answer 503 without it.
+	ABTestManager ABTestManager
+	// RegionManager backs the /api/webhook-regions routes, which answer 503
+	// without it.
+	RegionManager RegionManager
+	// UnhealthyDisconnectThreshold defaults to 10 minutes.
+	UnhealthyDisconnectThreshold time.Duration
+	// AccountGroups defaults to an empty registry.
+	AccountGroups *outbound.AccountGroups
+	// AdminIPAllowlist restricts the sensitive routes (broadcast, groups,
+	// bulk CSV sends, profile updates, A/B tests, webhook regions,
+	// test-webhook) to these CIDRs when set.
+	AdminIPAllowlist []string
+	// IdempotencyTTL is how long Idempotency-Key responses are replayed.
+	// Defaults to 24 hours.
+	IdempotencyTTL time.Duration
+	// WebhookSimulator forwards payloads for POST /api/test/simulate-incoming.
+	WebhookSimulator WebhookSimulator
+	// TestAPIKey is required as X-API-Key on /api/test routes; they are not
+	// registered when it is empty.
+	TestAPIKey string
 }
 
 // NewHandlers creates a new Handlers instance
 func NewHandlers(
-	rl *ratelimit.Limiter,
+	rl RateLimiter,
 	metaWebhook *metawebhook.Service,
 	outboundRouter *outbound.Router,
 	cfg HandlerConfig,
 ) *Handlers {
+	unhealthyDisconnect := cfg.UnhealthyDisconnectThreshold
+	if unhealthyDisconnect <= 0 {
+		unhealthyDisconnect = 10 * time.Minute
+	}
+	groups := cfg.AccountGroups
+	if groups == nil {
+		groups = outbound.NewAccountGroups(nil)
+	}
 	return &Handlers{
-		rateLimiter:   rl,
-		eventRecorder: cfg.EventRecorder,
-		metaWebhook:   metaWebhook,
-		outbound:      outboundRouter,
+		rateLimiter:         rl,
+		eventRecorder:       cfg.EventRecorder,
+		metaWebhook:         metaWebhook,
+		outbound:            outboundRouter,
+		circuits:            cfg.CircuitReporter,
+		aiHealth:            cfg.AIHealthChecker,
+		webhookTester:       cfg.WebhookTester,
+		abTests:             cfg.ABTestManager,
+		regions:             cfg.RegionManager,
+		webhookSimulator:    cfg.WebhookSimulator,
+		testAPIKey:          cfg.TestAPIKey,
+		groups:              groups,
+		adminIPs:            cfg.AdminIPAllowlist,
+		idempotency:         NewIdempotencyStore(cfg.IdempotencyTTL),
+		bulkJobs:            newBulkJobStore(),
+		unhealthyDisconnect: unhealthyDisconnect,
 	}
 }
 
-// GetHealth returns health check information
+// GetLive is the liveness probe; it succeeds whenever the process can serve HTTP.
+func (h *Handlers) GetLive(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"status": "alive"})
+}
+
+// GetReady is the readiness probe. The gateway is ready when at least one
+// account routed via Meta is not failing past the disconnect threshold.
+// Accounts with unknown status count as ready since no send has tested them yet.
+func (h *Handlers) GetReady(c *gin.Context) {
+	now := time.Now()
+	ready := 0
+	accounts := h.outbound.AccountStatuses()
+	for _, account := range accounts {
+		if account.Status == metaoutbound.PhoneNumberFailing &&
+			now.Sub(account.FailingSince) > h.unhealthyDisconnect {
+			continue
+		}
+		ready++
+	}
+
+	if ready == 0 {
+		c.JSON(http.StatusServiceUnavailable, gin.H{
+			"status":         "not_ready",
+			"ready_accounts": ready,
+			"total_accounts": len(accounts),
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"status":         "ready",
+		"ready_accounts": ready,
+		"total_accounts": len(accounts),
+	})
+}
+
+// GetHealth returns health check information from local state only, so it
+// stays cheap for container healthchecks; GetDeepHealth probes dependencies.
+//
+// Deprecated: use /healthz/live and /healthz/ready for probes. /health is
+// kept for existing callers and dashboards.
 func (h *Handlers) GetHealth(c *gin.Context) {
+	c.Header("Deprecation", "true")
+	c.Header("Link", `</healthz/ready>; rel="successor-version"`)
+
+	status := "healthy"
+	circuits := []webhook.CircuitStatus{}
+	if h.circuits != nil {
+		circuits = h.circuits.CircuitStates()
+	}
+	for _, circuit := range circuits {
+		if circuit.State != webhook.CircuitClosed {
+			status = "degraded"
+			break
+		}
+	}
+
+	accounts := []gin.H{}
+	now := time.Now()
+	for _, account := range h.outbound.AccountStatuses() {
+		entry := gin.H{
+			"account_id": account.AccountID,
+			"status":     account.Status,
+		}
+		if !account.LastSeenAt.IsZero() {
+			entry["last_seen_at"] = account.LastSeenAt.Format(time.RFC3339)
+		}
+		if account.Status == metaoutbound.PhoneNumberFailing &&
+			now.Sub(account.FailingSince) > h.unhealthyDisconnect {
+			status = "degraded"
+		}
+		accounts = append(accounts, entry)
+	}
+
 	c.JSON(http.StatusOK, gin.H{
-		"status":    "healthy",
+		"status":    status,
 		"service":   "wa-gateway",
 		"version":   "1.0.0",
 		"timestamp": time.Now().Format(time.RFC3339),
+		"dependencies": gin.H{
+			"webhook_circuits": circuits,
+			"whatsapp":         accounts,
+		},
+	})
+}
+
+const (
+	defaultDeepHealthTimeout = 5 * time.Second
+	maxDeepHealthTimeout     = 30 * time.Second
+)
+
+// DependencyCheck is the result of an active dependency probe.
+type DependencyCheck struct {
+	OK        bool   `json:"ok"`
+	LatencyMS int64  `json:"latency_ms"`
+	Error     string `json:"error,omitempty"`
+}
+
+// GetDeepHealth actively probes every dependency within ?timeout= (default
+// 5s, max 30s): the Graph API for each Meta account, the /health endpoint of
+// each AI service and the state of the webhook circuits.
+func (h *Handlers) GetDeepHealth(c *gin.Context) {
+	timeout := defaultDeepHealthTimeout
+	if raw := c.Query("timeout"); raw != "" {
+		parsed, err := time.ParseDuration(raw)
+		if err != nil || parsed <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Invalid timeout",
+				"message": "timeout must be a positive duration such as 5s",
+			})
+			return
+		}
+		timeout = min(parsed, maxDeepHealthTimeout)
+	}
+	ctx, cancel := context.WithTimeout(c.Request.Context(), timeout)
+	defer cancel()
+
+	var (
+		mu     sync.Mutex
+		wg     sync.WaitGroup
+		checks = map[string]DependencyCheck{}
+	)
+	for _, account := range h.outbound.AccountStatuses() {
+		wg.Add(1)
+		go func(accountID string) {
+			defer wg.Done()
+			start := time.Now()
+			err := h.outbound.CheckAccount(ctx, accountID)
+			check := DependencyCheck{OK: err == nil, LatencyMS: time.Since(start).Milliseconds()}
+			if err != nil {
+				check.Error = err.Error()
+			}
+			mu.Lock()
+			checks["whatsapp:"+accountID] = check
+			mu.Unlock()
+		}(account.AccountID)
+	}
+	if h.aiHealth != nil {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			// The services are probed together, so they share one latency.
+			start := time.Now()
+			results := h.aiHealth.Healthcheck(ctx)
+			latency := time.Since(start).Milliseconds()
+			mu.Lock()
+			defer mu.Unlock()
+			for service, err := range results {
+				check := DependencyCheck{OK: err == nil, LatencyMS: latency}
+				if err != nil {
+					check.Error = err.Error()
+				}
+				checks["ai:"+service] = check
+			}
+		}()
+	}
+	wg.Wait()
+
+	if h.circuits != nil {
+		for _, circuit := range h.circuits.CircuitStates() {
+			check := DependencyCheck{OK: circuit.State == webhook.CircuitClosed}
+			if !check.OK {
+				check.Error = "circuit " + string(circuit.State)
+			}
+			checks["webhook:"+circuit.Service] = check
+		}
+	}
+
+	ok := true
+	for _, check := range checks {
+		ok = ok && check.OK
+	}
+	code := http.StatusOK
+	if !ok {
+		code = http.StatusServiceUnavailable
+	}
+	c.JSON(code, gin.H{
+		"ok":           ok,
+		"timeout_ms":   timeout.Milliseconds(),
+		"dependencies": checks,
 	})
 }
 
 // SendMessageRequest represents the request body for sending a message
 type SendMessageRequest struct {
 	AccountID string            `json:"account_id" binding:"required"`
-	To        string            `json:"to" binding:"required"`
+	To        string            `json:"to" binding:"required_without=ToList"`
 	Message   string            `json:"message" binding:"required"`
 	UI        *webhook.UIConfig `json:"ui,omitempty"`
 	Metadata  *SendMetadata     `json:"metadata,omitempty"`
+	// ToList sends the same message to several recipients; see
+	// postSendMulti. When it is set To is ignored.
+	ToList []string `json:"to_list,omitempty"`
 }
 
 // SendMetadata identifies the source flow of an outbound send.
@@ -74,11 +333,23 @@ type SendMetadata struct {
 // PostSend sends a message
 func (h *Handlers) PostSend(c *gin.Context) {
 	var req SendMessageRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "Invalid request",
-			"message": err.Error(),
-		})
+	var upload *sendUpload
+	var bindErr error
+	if c.ContentType() == gin.MIMEMultipartPOSTForm {
+		req, upload, bindErr = bindMultipartSend(c)
+	} else {
+		bindErr = c.ShouldBindJSON(&req)
+	}
+	if bindErr != nil {
+		RespondError(c, http.StatusBadRequest, "INVALID_REQUEST", bindErr.Error())
+		return
+	}
+	if req.UI != nil && !supportedUITypes[req.UI.Type] {
+		RespondError(c, http.StatusBadRequest, "INVALID_REQUEST", errUnsupportedUIType.Error())
+		return
+	}
+	if len(req.ToList) > 0 {
+		h.postSendMulti(c, req, upload)
 		return
 	}
 
@@ -90,9 +361,12 @@ func (h *Handlers) PostSend(c *gin.Context) {
 		metadataForLog(req.Metadata),
 	)
 
+	// Sends honor the caller's deadline and stop when the client disconnects.
+	ctx := c.Request.Context()
+
 	// Check rate limit
 	allowed, retryAfter, decision, err := h.rateLimiter.Check(
-		context.Background(),
+		ctx,
 		req.AccountID,
 		req.To,
 	)
@@ -114,26 +388,24 @@ func (h *Handlers) PostSend(c *gin.Context) {
 				retryAt,
 				metadataForLog(req.Metadata),
 			)
-			c.JSON(http.StatusTooManyRequests, gin.H{
-				"error":              "Rate limit exceeded",
-				"message":            err.Error(),
-				"code":               "RATE_LIMIT_EXCEEDED",
-				"retry_after":        int(retryAfter.Seconds()),
-				"retry_at":           retryAt,
-				"account_id":         req.AccountID,
-				"destination":        req.To,
-				"window":             decision.Window,
-				"messages_last_hour": decision.MessagesLastHour,
-				"messages_last_24h":  decision.MessagesLast24H,
-				"limit_per_hour":     decision.LimitPerHour,
-				"limit_per_24h":      decision.LimitPer24H,
+			writeResponse(c, http.StatusTooManyRequests, APIResponse{
+				Error: err.Error(),
+				Code:  "RATE_LIMIT_EXCEEDED",
+				Data: gin.H{
+					"retry_after":        int(retryAfter.Seconds()),
+					"retry_at":           retryAt,
+					"account_id":         req.AccountID,
+					"destination":        req.To,
+					"window":             decision.Window,
+					"messages_last_hour": decision.MessagesLastHour,
+					"messages_last_24h":  decision.MessagesLast24H,
+					"limit_per_hour":     decision.LimitPerHour,
+					"limit_per_24h":      decision.LimitPer24H,
+				},
 			})
 			return
 		}
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error":   "Rate limit check failed",
-			"message": err.Error(),
-		})
+		RespondError(c, http.StatusInternalServerError, "RATE_LIMIT_CHECK_FAILED", err.Error())
 		return
 	}
 
@@ -152,52 +424,33 @@ func (h *Handlers) PostSend(c *gin.Context) {
 			retryAt,
 			metadataForLog(req.Metadata),
 		)
-		c.JSON(http.StatusTooManyRequests, gin.H{
-			"error":              "Rate limit exceeded",
-			"message":            "rate limit exceeded",
-			"code":               "RATE_LIMIT_EXCEEDED",
-			"retry_after":        int(retryAfter.Seconds()),
-			"retry_at":           retryAt,
-			"account_id":         req.AccountID,
-			"destination":        req.To,
-			"window":             decision.Window,
-			"messages_last_hour": decision.MessagesLastHour,
-			"messages_last_24h":  decision.MessagesLast24H,
-			"limit_per_hour":     decision.LimitPerHour,
-			"limit_per_24h":      decision.LimitPer24H,
+		writeResponse(c, http.StatusTooManyRequests, APIResponse{
+			Error: "rate limit exceeded",
+			Code:  "RATE_LIMIT_EXCEEDED",
+			Data: gin.H{
+				"retry_after":        int(retryAfter.Seconds()),
+				"retry_at":           retryAt,
+				"account_id":         req.AccountID,
+				"destination":        req.To,
+				"window":             decision.Window,
+				"messages_last_hour": decision.MessagesLastHour,
+				"messages_last_24h":  decision.MessagesLast24H,
+				"limit_per_hour":     decision.LimitPerHour,
+				"limit_per_24h":      decision.LimitPer24H,
+			},
 		})
 		return
 	}
 
 	// Send message through configured outbound transport.
-	var sendErr error
-	if req.UI == nil {
-		sendErr = h.outbound.SendText(context.Background(), req.AccountID, req.To, req.Message)
-	} else {
-		switch req.UI.Type {
-		case "buttons":
-			sendErr = h.outbound.SendButtons(context.Background(), req.AccountID, req.To, req.Message, *req.UI)
-		case "list":
-			sendErr = h.outbound.SendList(context.Background(), req.AccountID, req.To, req.Message, *req.UI)
-		case "location_request":
-			sendErr = h.outbound.SendLocationRequest(context.Background(), req.AccountID, req.To, req.Message)
-		case "flow":
-			sendErr = h.outbound.SendFlow(context.Background(), req.AccountID, req.To, req.Message, *req.UI)
-		case "template":
-			sendErr = h.outbound.SendTemplate(context.Background(), req.AccountID, req.To, req.Message, *req.UI)
-		default:
-			c.JSON(http.StatusBadRequest, gin.H{
-				"error":   "Invalid request",
-				"message": "unsupported ui.type",
-			})
-			return
-		}
+	messageID, sendErr := h.sendOne(ctx, req, req.To, upload)
+	if errors.Is(sendErr, outbound.ErrInvalidDestination) {
+		log.Printf("[PostSend] invalid_phone account=%s to=%s metadata=%s", req.AccountID, req.To, metadataForLog(req.Metadata))
+		RespondError(c, http.StatusBadRequest, "INVALID_PHONE", sendErr.Error())
+		return
 	}
 	if sendErr != nil {
-		status := http.StatusInternalServerError
-		if errors.Is(sendErr, outbound.ErrMetaNotConfigured) {
-			status = http.StatusServiceUnavailable
-		}
+		status, code := sendErrorStatus(sendErr)
 		log.Printf(
 			"[PostSend] send_failed account=%s to=%s ui_type=%s metadata=%s err=%v",
 			req.AccountID,
@@ -206,34 +459,198 @@ func (h *Handlers) PostSend(c *gin.Context) {
 			metadataForLog(req.Metadata),
 			sendErr,
 		)
-		c.JSON(status, gin.H{
-			"error":   "Failed to send message",
-			"message": sendErr.Error(),
-		})
+		RespondError(c, status, code, sendErr.Error())
 		return
 	}
 
 	// Increment rate limit counters
-	if err := h.rateLimiter.Increment(context.Background(), req.AccountID, req.To); err != nil {
-		// Log error but don't fail the request
-		// TODO: add proper logging
+	if err := h.rateLimiter.Increment(ctx, req.AccountID, req.To); err != nil {
+		log.Printf("[PostSend] rate_limit_increment_failed account=%s to=%s err=%v", req.AccountID, req.To, err)
 	}
 	log.Printf(
-		"[PostSend] send_ok account=%s to=%s ui_type=%s metadata=%s",
+		"[PostSend] send_ok account=%s to=%s message_id=%s ui_type=%s metadata=%s",
 		req.AccountID,
 		req.To,
+		messageID,
 		uiTypeForLog(req.UI),
 		metadataForLog(req.Metadata),
 	)
 
-	c.JSON(http.StatusOK, gin.H{
-		"success":    true,
-		"message_id": "",
-		"timestamp":  time.Now().Format(time.RFC3339),
+	Respond(c, http.StatusOK, gin.H{
+		"message_id": messageID,
 		"to_phone":   req.To,
 	})
 }
 
+var errUnsupportedUIType = errors.New("unsupported ui.type")
+
+var supportedUITypes = map[string]bool{
+	"buttons":          true,
+	"list":             true,
+	"location_request": true,
+	"flow":             true,
+	"template":         true,
+}
+
+// sendOne sends req's message, UI or upload to a single recipient and
+// returns the Meta message ID.
+func (h *Handlers) sendOne(ctx context.Context, req SendMessageRequest, to string, upload *sendUpload) (string, error) {
+	if upload != nil {
+		log.Printf("[PostSend] media_upload account=%s to=%s mime=%s bytes=%d", req.AccountID, to, upload.mimeType, len(upload.data))
+		return h.outbound.SendMedia(ctx, req.AccountID, to, upload.data, upload.mimeType, upload.filename, req.Message)
+	}
+	if req.UI == nil {
+		return h.outbound.SendText(ctx, req.AccountID, to, req.Message)
+	}
+	switch req.UI.Type {
+	case "buttons":
+		return h.outbound.SendButtons(ctx, req.AccountID, to, req.Message, *req.UI)
+	case "list":
+		return h.outbound.SendList(ctx, req.AccountID, to, req.Message, *req.UI)
+	case "location_request":
+		return h.outbound.SendLocationRequest(ctx, req.AccountID, to, req.Message)
+	case "flow":
+		return h.outbound.SendFlow(ctx, req.AccountID, to, req.Message, *req.UI)
+	case "template":
+		return h.outbound.SendTemplate(ctx, req.AccountID, to, req.Message, *req.UI)
+	}
+	return "", errUnsupportedUIType
+}
+
+// sendErrorStatus maps a send error to its HTTP status and error code.
+func sendErrorStatus(err error) (int, string) {
+	switch {
+	case errors.Is(err, outbound.ErrInvalidDestination):
+		return http.StatusBadRequest, "INVALID_PHONE"
+	case errors.Is(err, outbound.ErrMetaNotConfigured):
+		return http.StatusServiceUnavailable, "META_NOT_CONFIGURED"
+	case errors.Is(err, metaoutbound.ErrMessageTooLong):
+		return http.StatusBadRequest, "MESSAGE_TOO_LONG"
+	}
+	return http.StatusInternalServerError, "SEND_FAILED"
+}
+
+// GetSendStats returns the account's send telemetry for the current hour.
+func (h *Handlers) GetSendStats(c *gin.Context) {
+	accountID := c.Param("accountId")
+	stats, ok := h.outbound.SendStats(accountID)
+	if !ok {
+		RespondError(c, http.StatusNotFound, "ACCOUNT_NOT_FOUND", "account is not routed via Meta: "+accountID)
+		return
+	}
+	Respond(c, http.StatusOK, stats)
+}
+
+// maxBroadcastPairs caps accounts × recipients in a single broadcast.
+const maxBroadcastPairs = 500
+
+// BroadcastRequest is the body of POST /api/broadcast.
+type BroadcastRequest struct {
+	Accounts   []string `json:"accounts" binding:"required,min=1"`
+	Recipients []string `json:"recipients" binding:"required,min=1"`
+	Message    string   `json:"message" binding:"required"`
+}
+
+// PostBroadcast sends the same text from every account to every recipient.
+// Rate limits are enforced for the batch as a whole: if any
+// account/recipient pair is over its limit nothing is sent.
+func (h *Handlers) PostBroadcast(c *gin.Context) {
+	var req BroadcastRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		RespondError(c, http.StatusBadRequest, "INVALID_REQUEST", err.Error())
+		return
+	}
+	h.broadcast(c, "PostBroadcast", req.Accounts, req.Recipients, req.Message)
+}
+
+// broadcast rate-checks and sends message from every account to every
+// recipient, writing the response. logPrefix names the calling handler.
+func (h *Handlers) broadcast(c *gin.Context, logPrefix string, accounts, recipients []string, message string) {
+	if pairs := len(accounts) * len(recipients); pairs > maxBroadcastPairs {
+		RespondError(c, http.StatusBadRequest, "INVALID_REQUEST", fmt.Sprintf("broadcast has %d account/recipient pairs, max %d", pairs, maxBroadcastPairs))
+		return
+	}
+
+	ctx := c.Request.Context()
+	limited := []gin.H{}
+	for _, accountID := range accounts {
+		for _, to := range recipients {
+			allowed, retryAfter, decision, err := h.rateLimiter.Check(ctx, accountID, to)
+			if allowed {
+				continue
+			}
+			limited = append(limited, gin.H{
+				"account_id":  accountID,
+				"destination": to,
+				"window":      decision.Window,
+				"retry_after": int(retryAfter.Seconds()),
+				"message":     errorMessage(err),
+			})
+		}
+	}
+	if len(limited) > 0 {
+		log.Printf("[%s] rate_limited accounts=%d recipients=%d limited_pairs=%d", logPrefix, len(accounts), len(recipients), len(limited))
+		writeResponse(c, http.StatusTooManyRequests, APIResponse{
+			Error: "rate limit exceeded",
+			Code:  "RATE_LIMIT_EXCEEDED",
+			Data:  gin.H{"limited": limited},
+		})
+		return
+	}
+
+	results := h.outbound.BroadcastToAll(ctx, message, accounts, recipients)
+	sent := 0
+	for _, result := range results {
+		if !result.Success {
+			continue
+		}
+		sent++
+		if err := h.rateLimiter.Increment(ctx, result.AccountID, result.To); err != nil {
+			log.Printf("[%s] rate_limit_increment_failed account=%s to=%s err=%v", logPrefix, result.AccountID, result.To, err)
+		}
+	}
+	log.Printf("[%s] done accounts=%d recipients=%d sent=%d failed=%d", logPrefix, len(accounts), len(recipients), sent, len(results)-sent)
+
+	writeResponse(c, http.StatusOK, APIResponse{
+		Success: sent == len(results),
+		Data: gin.H{
+			"sent":    sent,
+			"failed":  len(results) - sent,
+			"results": results,
+		},
+	})
+}
+
+func errorMessage(err error) string {
+	if err == nil {
+		return ""
+	}
+	return err.Error()
+}
+
+// PostTestWebhook sends a synthetic ping for the account to its AI service
+// and returns the service's raw response.
+func (h *Handlers) PostTestWebhook(c *gin.Context) {
+	accountID := c.Param("accountId")
+	if h.webhookTester == nil {
+		RespondError(c, http.StatusServiceUnavailable, "WEBHOOK_UNAVAILABLE", "no AI service webhook is configured")
+		return
+	}
+
+	result, err := h.webhookTester.TestWebhook(c.Request.Context(), accountID)
+	if err != nil {
+		RespondError(c, http.StatusBadGateway, "WEBHOOK_TEST_FAILED", err.Error())
+		return
+	}
+	Respond(c, http.StatusOK, gin.H{
+		"account_id":  accountID,
+		"url":         result.URL,
+		"status_code": result.StatusCode,
+		"latency_ms":  result.Latency.Milliseconds(),
+		"response":    string(result.Body),
+	})
+}
+
 func (h *Handlers) recordRateLimitHit(
 	ctx context.Context,
 	req SendMessageRequest,
@@ -4,11 +4,18 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"mime/multipart"
 	"net/http"
 	"net/http/httptest"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/tinkubot/wa-gateway/internal/metaoutbound"
 	"github.com/tinkubot/wa-gateway/internal/outbound"
 	"github.com/tinkubot/wa-gateway/internal/ratelimit"
 	"github.com/tinkubot/wa-gateway/internal/webhook"
@@ -22,6 +29,17 @@ type fakeMetaSender struct {
 	locationCalls int
 	lastBody      string
 	lastUI        *webhook.UIConfig
+	statuses      map[string]metaoutbound.PhoneNumberStatus
+	checkErrs     map[string]error
+	sendErr       error
+	textCalls     int
+	mu            sync.Mutex
+	imageCalls    int
+	documentCalls int
+	lastMIME      string
+	lastData      []byte
+	lastAbout     string
+	lastPicture   []byte
 }
 
 type fakeEventRecorder struct {
@@ -33,8 +51,29 @@ func (f *fakeEventRecorder) Record(_ context.Context, event ratelimit.Event) err
 	return nil
 }
 
-func (f *fakeMetaSender) SendText(_ context.Context, _ string, _ string, _ string) error {
-	return nil
+func (f *fakeMetaSender) PhoneNumberStatus(phoneNumberID string) metaoutbound.PhoneNumberStatus {
+	if status, ok := f.statuses[phoneNumberID]; ok {
+		return status
+	}
+	return metaoutbound.PhoneNumberStatus{PhoneNumberID: phoneNumberID, Status: metaoutbound.PhoneNumberUnknown}
+}
+
+func (f *fakeMetaSender) CheckPhoneNumber(_ context.Context, phoneNumberID string) error {
+	return f.checkErrs[phoneNumberID]
+}
+
+func (f *fakeMetaSender) SendText(ctx context.Context, _ string, _ string, body string) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.textCalls++
+	f.lastBody = body
+	if f.sendErr != nil {
+		return "", f.sendErr
+	}
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("wamid.text-%d", f.textCalls), nil
 }
 
 func (f *fakeMetaSender) SendButtons(
@@ -43,12 +82,12 @@ func (f *fakeMetaSender) SendButtons(
 	_ string,
 	body string,
 	ui webhook.UIConfig,
-) error {
+) (string, error) {
 	f.buttonCalls++
 	f.lastBody = body
 	copyUI := ui
 	f.lastUI = &copyUI
-	return nil
+	return "", nil
 }
 
 func (f *fakeMetaSender) SendList(
@@ -57,12 +96,12 @@ func (f *fakeMetaSender) SendList(
 	_ string,
 	body string,
 	ui webhook.UIConfig,
-) error {
+) (string, error) {
 	f.listCalls++
 	f.lastBody = body
 	copyUI := ui
 	f.lastUI = &copyUI
-	return nil
+	return "", nil
 }
 
 func (f *fakeMetaSender) SendLocationRequest(
@@ -70,11 +109,11 @@ func (f *fakeMetaSender) SendLocationRequest(
 	_ string,
 	_ string,
 	body string,
-) error {
+) (string, error) {
 	f.locationCalls++
 	f.lastBody = body
 	f.lastUI = nil
-	return nil
+	return "", nil
 }
 
 func (f *fakeMetaSender) SendFlow(
@@ -83,12 +122,12 @@ func (f *fakeMetaSender) SendFlow(
 	_ string,
 	body string,
 	ui webhook.UIConfig,
-) error {
+) (string, error) {
 	f.flowCalls++
 	f.lastBody = body
 	copyUI := ui
 	f.lastUI = &copyUI
-	return nil
+	return "", nil
 }
 
 func (f *fakeMetaSender) SendTemplate(
@@ -96,11 +135,41 @@ func (f *fakeMetaSender) SendTemplate(
 	_ string,
 	_ string,
 	ui webhook.UIConfig,
-) error {
+) (string, error) {
 	f.templateCalls++
 	copyUI := ui
 	f.lastUI = &copyUI
-	return nil
+	return "", nil
+}
+
+func (f *fakeMetaSender) SendImageFromBytes(_ context.Context, _ string, _ string, data []byte, mimeType, caption string) (string, error) {
+	f.imageCalls++
+	f.lastData = data
+	f.lastMIME = mimeType
+	f.lastBody = caption
+	return "", nil
+}
+
+func (f *fakeMetaSender) SendDocumentFromBytes(_ context.Context, _ string, _ string, data []byte, mimeType, _ string, caption string) (string, error) {
+	f.documentCalls++
+	f.lastData = data
+	f.lastMIME = mimeType
+	f.lastBody = caption
+	return "", nil
+}
+
+func (f *fakeMetaSender) SetAbout(_ context.Context, _ string, about string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.lastAbout = about
+	return f.sendErr
+}
+
+func (f *fakeMetaSender) SetProfilePicture(_ context.Context, _ string, data []byte) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.lastPicture = data
+	return f.sendErr
 }
 
 func TestPostSendDispatchesButtonsWhenUIProvided(t *testing.T) {
@@ -321,10 +390,11 @@ func TestPostSendReturnsRateLimitDetailsAndRecordsEvent(t *testing.T) {
 	if err := json.Unmarshal(rec.Body.Bytes(), &payload); err != nil {
 		t.Fatalf("unmarshal response: %v", err)
 	}
-	if payload["window"] != "hourly" {
+	details, _ := payload["data"].(map[string]any)
+	if payload["code"] != "RATE_LIMIT_EXCEEDED" || details["window"] != "hourly" {
 		t.Fatalf("expected hourly window, got %+v", payload)
 	}
-	if payload["account_id"] != "bot-clientes" || payload["destination"] != "593999111222" {
+	if details["account_id"] != "bot-clientes" || details["destination"] != "593999111222" {
 		t.Fatalf("unexpected account/destination payload: %+v", payload)
 	}
 	if len(recorder.events) != 1 {
@@ -334,3 +404,801 @@ func TestPostSendReturnsRateLimitDetailsAndRecordsEvent(t *testing.T) {
 		t.Fatalf("expected metadata to be persisted, got empty")
 	}
 }
+
+func TestGetHealthDegradesWhenAccountFailsPastThreshold(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	metaSender := &fakeMetaSender{
+		statuses: map[string]metaoutbound.PhoneNumberStatus{
+			"12345": {
+				PhoneNumberID: "12345",
+				Status:        metaoutbound.PhoneNumberFailing,
+				FailingSince:  time.Now().Add(-15 * time.Minute),
+			},
+		},
+	}
+	router := outbound.NewRouter(
+		metaSender,
+		outbound.RouterConfig{
+			MetaOutboundEnabled: true,
+			AccountPhoneNumber: map[string]string{
+				"bot-clientes":    "12345",
+				"bot-proveedores": "67890",
+			},
+		},
+	)
+	handlers := NewHandlers(
+		ratelimit.NewLimiter(ratelimit.Config{MaxPerHour: 20, MaxPer24h: 100}),
+		nil,
+		router,
+		HandlerConfig{UnhealthyDisconnectThreshold: 10 * time.Minute},
+	)
+
+	rec := httptest.NewRecorder()
+	_, ginRouter := gin.CreateTestContext(rec)
+	ginRouter.GET("/health", handlers.GetHealth)
+	ginRouter.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/health", nil))
+
+	var payload struct {
+		Status       string `json:"status"`
+		Dependencies struct {
+			WhatsApp []map[string]any `json:"whatsapp"`
+		} `json:"dependencies"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &payload); err != nil {
+		t.Fatalf("unmarshal response: %v", err)
+	}
+	if payload.Status != "degraded" {
+		t.Fatalf("expected degraded status, got %q", payload.Status)
+	}
+	if len(payload.Dependencies.WhatsApp) != 2 {
+		t.Fatalf("expected 2 whatsapp accounts, got %+v", payload.Dependencies.WhatsApp)
+	}
+	if payload.Dependencies.WhatsApp[0]["account_id"] != "bot-clientes" ||
+		payload.Dependencies.WhatsApp[0]["status"] != metaoutbound.PhoneNumberFailing {
+		t.Fatalf("unexpected first account: %+v", payload.Dependencies.WhatsApp[0])
+	}
+	if payload.Dependencies.WhatsApp[1]["status"] != metaoutbound.PhoneNumberUnknown {
+		t.Fatalf("expected unknown status for idle account, got %+v", payload.Dependencies.WhatsApp[1])
+	}
+}
+
+type fakeAIHealth map[string]error
+
+func (f fakeAIHealth) Healthcheck(ctx context.Context) map[string]error {
+	return f
+}
+
+func TestAIServiceHealthIsOnlyProbedByDeepHealth(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := outbound.NewRouter(&fakeMetaSender{}, outbound.RouterConfig{})
+	handlers := NewHandlers(
+		ratelimit.NewLimiter(ratelimit.Config{MaxPerHour: 20, MaxPer24h: 100}),
+		nil,
+		router,
+		HandlerConfig{AIHealthChecker: fakeAIHealth{
+			"ai-clientes":    nil,
+			"ai-proveedores": errors.New("health status=503"),
+		}},
+	)
+	_, ginRouter := gin.CreateTestContext(httptest.NewRecorder())
+	ginRouter.GET("/health", handlers.GetHealth)
+	ginRouter.GET("/health/deep", handlers.GetDeepHealth)
+
+	rec := httptest.NewRecorder()
+	ginRouter.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/health", nil))
+	var health struct {
+		Status       string         `json:"status"`
+		Dependencies map[string]any `json:"dependencies"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &health); err != nil {
+		t.Fatalf("unmarshal response: %v", err)
+	}
+	if _, ok := health.Dependencies["ai_services"]; ok || health.Status != "healthy" {
+		t.Fatalf("expected /health to skip AI probes, got %s", rec.Body.String())
+	}
+
+	rec = httptest.NewRecorder()
+	ginRouter.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/health/deep", nil))
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Fatalf("expected status 503, got %d body=%s", rec.Code, rec.Body.String())
+	}
+	var deep struct {
+		Dependencies map[string]DependencyCheck `json:"dependencies"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &deep); err != nil {
+		t.Fatalf("unmarshal response: %v", err)
+	}
+	if !deep.Dependencies["ai:ai-clientes"].OK {
+		t.Fatalf("expected ai-clientes healthy, got %+v", deep.Dependencies)
+	}
+	if check := deep.Dependencies["ai:ai-proveedores"]; check.OK || check.Error != "health status=503" {
+		t.Fatalf("expected ai-proveedores unhealthy, got %+v", check)
+	}
+}
+
+func TestGetReadyRequiresAnAccountThatCanSend(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	metaSender := &fakeMetaSender{
+		statuses: map[string]metaoutbound.PhoneNumberStatus{
+			"12345": {
+				PhoneNumberID: "12345",
+				Status:        metaoutbound.PhoneNumberFailing,
+				FailingSince:  time.Now().Add(-time.Hour),
+			},
+		},
+	}
+	newRouter := func(accounts map[string]string) *gin.Engine {
+		router := outbound.NewRouter(
+			metaSender,
+			outbound.RouterConfig{
+				MetaOutboundEnabled: true,
+				AccountPhoneNumber:  accounts,
+			},
+		)
+		handlers := NewHandlers(
+			ratelimit.NewLimiter(ratelimit.Config{MaxPerHour: 20, MaxPer24h: 100}),
+			nil,
+			router,
+			HandlerConfig{},
+		)
+		ginRouter := gin.New()
+		ginRouter.GET("/healthz/live", handlers.GetLive)
+		ginRouter.GET("/healthz/ready", handlers.GetReady)
+		return ginRouter
+	}
+
+	failingOnly := newRouter(map[string]string{"bot-clientes": "12345"})
+	rec := httptest.NewRecorder()
+	failingOnly.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/healthz/ready", nil))
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Fatalf("expected status 503, got %d body=%s", rec.Code, rec.Body.String())
+	}
+
+	rec = httptest.NewRecorder()
+	failingOnly.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/healthz/live", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected live status 200, got %d", rec.Code)
+	}
+
+	withHealthy := newRouter(map[string]string{"bot-clientes": "12345", "bot-proveedores": "67890"})
+	rec = httptest.NewRecorder()
+	withHealthy.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/healthz/ready", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d body=%s", rec.Code, rec.Body.String())
+	}
+}
+
+func TestGetDeepHealthReportsPerAccountChecks(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	metaSender := &fakeMetaSender{
+		checkErrs: map[string]error{"67890": errors.New("phone number check status=401")},
+	}
+	router := outbound.NewRouter(
+		metaSender,
+		outbound.RouterConfig{
+			MetaOutboundEnabled: true,
+			AccountPhoneNumber: map[string]string{
+				"bot-clientes":    "12345",
+				"bot-proveedores": "67890",
+			},
+		},
+	)
+	handlers := NewHandlers(
+		ratelimit.NewLimiter(ratelimit.Config{MaxPerHour: 20, MaxPer24h: 100}),
+		nil,
+		router,
+		HandlerConfig{},
+	)
+
+	rec := httptest.NewRecorder()
+	_, ginRouter := gin.CreateTestContext(rec)
+	ginRouter.GET("/health/deep", handlers.GetDeepHealth)
+	ginRouter.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/health/deep?timeout=2s", nil))
+
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Fatalf("expected status 503, got %d body=%s", rec.Code, rec.Body.String())
+	}
+	var payload struct {
+		OK           bool                       `json:"ok"`
+		TimeoutMS    int64                      `json:"timeout_ms"`
+		Dependencies map[string]DependencyCheck `json:"dependencies"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &payload); err != nil {
+		t.Fatalf("unmarshal response: %v", err)
+	}
+	if payload.OK || payload.TimeoutMS != 2000 {
+		t.Fatalf("unexpected summary: %+v", payload)
+	}
+	if !payload.Dependencies["whatsapp:bot-clientes"].OK {
+		t.Fatalf("expected bot-clientes check to pass, got %+v", payload.Dependencies)
+	}
+	if check := payload.Dependencies["whatsapp:bot-proveedores"]; check.OK || check.Error == "" {
+		t.Fatalf("expected bot-proveedores check to fail, got %+v", check)
+	}
+}
+
+func TestPostSendPropagatesRequestContext(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := outbound.NewRouter(
+		&fakeMetaSender{},
+		outbound.RouterConfig{
+			MetaOutboundEnabled: true,
+			AccountPhoneNumber:  map[string]string{"bot-clientes": "12345"},
+		},
+	)
+	handlers := NewHandlers(
+		ratelimit.NewLimiter(ratelimit.Config{MaxPerHour: 20, MaxPer24h: 100}),
+		nil,
+		router,
+		HandlerConfig{},
+	)
+
+	rec := httptest.NewRecorder()
+	_, ginRouter := gin.CreateTestContext(rec)
+	ginRouter.POST("/send", handlers.PostSend)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	raw := []byte(`{"account_id":"bot-clientes","to":"593999111222","message":"Hola"}`)
+	req := httptest.NewRequest(http.MethodPost, "/send", bytes.NewReader(raw)).WithContext(ctx)
+	req.Header.Set("Content-Type", "application/json")
+	ginRouter.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusInternalServerError {
+		t.Fatalf("expected status 500 for canceled request, got %d body=%s", rec.Code, rec.Body.String())
+	}
+}
+
+func TestPostSendTableDriven(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name          string
+		body          string
+		seedSends     int
+		sendErr       error
+		incrementErr  error
+		metaAccounts  map[string]bool
+		wantStatus    int
+		wantTextCalls int
+		wantCode      string
+	}{
+		{
+			name:          "valid request",
+			body:          `{"account_id":"bot-clientes","to":"593999111222","message":"Hola"}`,
+			wantStatus:    http.StatusOK,
+			wantTextCalls: 1,
+		},
+		{
+			name:          "rate limit increment error is not fatal",
+			body:          `{"account_id":"bot-clientes","to":"593999111222","message":"Hola"}`,
+			incrementErr:  errors.New("counter store unavailable"),
+			wantStatus:    http.StatusOK,
+			wantTextCalls: 1,
+		},
+		{
+			name:       "missing account_id",
+			body:       `{"to":"593999111222","message":"Hola"}`,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "rate limit exceeded",
+			body:       `{"account_id":"bot-clientes","to":"593999111222","message":"Hola"}`,
+			seedSends:  2,
+			wantStatus: http.StatusTooManyRequests,
+			wantCode:   "RATE_LIMIT_EXCEEDED",
+		},
+		{
+			name:          "send error",
+			body:          `{"account_id":"bot-clientes","to":"593999111222","message":"Hola"}`,
+			sendErr:       errors.New("meta send status=500"),
+			wantStatus:    http.StatusInternalServerError,
+			wantTextCalls: 1,
+		},
+		{
+			name:       "invalid phone",
+			body:       `{"account_id":"bot-clientes","to":"52@s.whatsapp.net","message":"Hola"}`,
+			wantStatus: http.StatusBadRequest,
+			wantCode:   "INVALID_PHONE",
+		},
+		{
+			name:         "account not routed via meta",
+			body:         `{"account_id":"bot-clientes","to":"593999111222","message":"Hola"}`,
+			metaAccounts: map[string]bool{"bot-proveedores": true},
+			wantStatus:   http.StatusServiceUnavailable,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			metaSender := &fakeMetaSender{sendErr: tt.sendErr}
+			router := outbound.NewRouter(
+				metaSender,
+				outbound.RouterConfig{
+					MetaOutboundEnabled: true,
+					MetaEnabledAccounts: tt.metaAccounts,
+					AccountPhoneNumber:  map[string]string{"bot-clientes": "12345"},
+				},
+			)
+			limiter := ratelimit.NewLimiter(ratelimit.Config{MaxPerHour: 2, MaxPer24h: 100})
+			for i := 0; i < tt.seedSends; i++ {
+				if err := limiter.Increment(context.Background(), "bot-clientes", "593999111222"); err != nil {
+					t.Fatalf("seed limiter: %v", err)
+				}
+			}
+			var rl RateLimiter = limiter
+			if tt.incrementErr != nil {
+				rl = failingIncrementLimiter{Limiter: limiter, err: tt.incrementErr}
+			}
+			handlers := NewHandlers(rl, nil, router, HandlerConfig{})
+
+			rec := httptest.NewRecorder()
+			_, ginRouter := gin.CreateTestContext(rec)
+			ginRouter.POST("/send", handlers.PostSend)
+
+			req := httptest.NewRequest(http.MethodPost, "/send", bytes.NewReader([]byte(tt.body)))
+			req.Header.Set("Content-Type", "application/json")
+			ginRouter.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("expected status %d, got %d body=%s", tt.wantStatus, rec.Code, rec.Body.String())
+			}
+			if metaSender.textCalls != tt.wantTextCalls {
+				t.Fatalf("expected %d text sends, got %d", tt.wantTextCalls, metaSender.textCalls)
+			}
+
+			var payload map[string]any
+			if err := json.Unmarshal(rec.Body.Bytes(), &payload); err != nil {
+				t.Fatalf("unmarshal response: %v", err)
+			}
+			if tt.wantCode != "" && payload["code"] != tt.wantCode {
+				t.Fatalf("expected code %q, got %+v", tt.wantCode, payload)
+			}
+			if tt.wantStatus == http.StatusOK {
+				data, _ := payload["data"].(map[string]any)
+				if payload["success"] != true || data["to_phone"] != "593999111222" {
+					t.Fatalf("unexpected success payload: %+v", payload)
+				}
+				if data["message_id"] != "wamid.text-1" {
+					t.Fatalf("expected Meta message_id in response, got %+v", payload)
+				}
+			}
+		})
+	}
+}
+
+// failingIncrementLimiter allows every send but fails to count it.
+type failingIncrementLimiter struct {
+	*ratelimit.Limiter
+	err error
+}
+
+func (l failingIncrementLimiter) Increment(context.Context, string, string) error {
+	return l.err
+}
+
+type fakeWebhookTester struct {
+	result *webhook.TestWebhookResult
+	err    error
+}
+
+func (f *fakeWebhookTester) TestWebhook(ctx context.Context, accountID string) (*webhook.TestWebhookResult, error) {
+	return f.result, f.err
+}
+
+func TestPostTestWebhook(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	tests := []struct {
+		name       string
+		tester     WebhookTester
+		wantStatus int
+		wantBody   string
+	}{
+		{
+			name: "returns raw response",
+			tester: &fakeWebhookTester{result: &webhook.TestWebhookResult{
+				URL:        "http://ai-clientes:8001/handle-whatsapp-message",
+				StatusCode: http.StatusOK,
+				Body:       []byte(`{"success":true}`),
+			}},
+			wantStatus: http.StatusOK,
+			wantBody:   `"response":"{\"success\":true}"`,
+		},
+		{
+			name:       "transport error",
+			tester:     &fakeWebhookTester{err: errors.New("request failed: connection refused")},
+			wantStatus: http.StatusBadGateway,
+			wantBody:   "connection refused",
+		},
+		{
+			name:       "no webhook client",
+			wantStatus: http.StatusServiceUnavailable,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handlers := NewHandlers(
+				ratelimit.NewLimiter(ratelimit.Config{MaxPerHour: 20, MaxPer24h: 100}),
+				nil,
+				outbound.NewRouter(&fakeMetaSender{}, outbound.RouterConfig{}),
+				HandlerConfig{WebhookTester: tt.tester},
+			)
+			rec := httptest.NewRecorder()
+			_, ginRouter := gin.CreateTestContext(rec)
+			ginRouter.POST("/api/accounts/:accountId/test-webhook", handlers.PostTestWebhook)
+			ginRouter.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/accounts/bot-clientes/test-webhook", nil))
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("expected status %d, got %d body=%s", tt.wantStatus, rec.Code, rec.Body.String())
+			}
+			if !strings.Contains(rec.Body.String(), tt.wantBody) {
+				t.Fatalf("expected body to contain %s, got %s", tt.wantBody, rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestPostBroadcast(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	newHandlers := func(limiter *ratelimit.Limiter) (*Handlers, *fakeMetaSender) {
+		metaSender := &fakeMetaSender{}
+		router := outbound.NewRouter(metaSender, outbound.RouterConfig{
+			MetaOutboundEnabled: true,
+			AccountPhoneNumber: map[string]string{
+				"bot-clientes":    "12345",
+				"bot-proveedores": "67890",
+			},
+		})
+		return NewHandlers(limiter, nil, router, HandlerConfig{}), metaSender
+	}
+	post := func(h *Handlers, body string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		_, ginRouter := gin.CreateTestContext(rec)
+		ginRouter.POST("/api/broadcast", h.PostBroadcast)
+		req := httptest.NewRequest(http.MethodPost, "/api/broadcast", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		ginRouter.ServeHTTP(rec, req)
+		return rec
+	}
+	body := `{"accounts":["bot-clientes","bot-proveedores"],"recipients":["593999111222","593999111333"],"message":"Aviso"}`
+
+	t.Run("sends every pair", func(t *testing.T) {
+		limiter := ratelimit.NewLimiter(ratelimit.Config{MaxPerHour: 1, MaxPer24h: 100})
+		h, metaSender := newHandlers(limiter)
+
+		rec := post(h, body)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d body=%s", rec.Code, rec.Body.String())
+		}
+		var envelope struct {
+			Data struct {
+				Sent    int                        `json:"sent"`
+				Results []outbound.BroadcastResult `json:"results"`
+			} `json:"data"`
+		}
+		if err := json.Unmarshal(rec.Body.Bytes(), &envelope); err != nil {
+			t.Fatalf("unmarshal response: %v", err)
+		}
+		payload := envelope.Data
+		if payload.Sent != 4 || len(payload.Results) != 4 || metaSender.textCalls != 4 {
+			t.Fatalf("expected 4 sends, got sent=%d results=%d calls=%d", payload.Sent, len(payload.Results), metaSender.textCalls)
+		}
+
+		// Every pair is now at its hourly limit, so a repeat is rejected whole.
+		rec = post(h, body)
+		if rec.Code != http.StatusTooManyRequests {
+			t.Fatalf("expected 429 for repeated batch, got %d", rec.Code)
+		}
+		if metaSender.textCalls != 4 {
+			t.Fatalf("expected rate limited batch to send nothing, got %d calls", metaSender.textCalls)
+		}
+	})
+
+	t.Run("rejects oversized batch", func(t *testing.T) {
+		h, _ := newHandlers(ratelimit.NewLimiter(ratelimit.Config{}))
+		recipients := make([]string, maxBroadcastPairs)
+		for i := range recipients {
+			recipients[i] = "593999111222"
+		}
+		raw, _ := json.Marshal(BroadcastRequest{Accounts: []string{"bot-clientes", "bot-proveedores"}, Recipients: recipients, Message: "Aviso"})
+		if rec := post(h, string(raw)); rec.Code != http.StatusBadRequest {
+			t.Fatalf("expected 400 for oversized batch, got %d", rec.Code)
+		}
+	})
+
+	t.Run("requires accounts", func(t *testing.T) {
+		h, _ := newHandlers(ratelimit.NewLimiter(ratelimit.Config{}))
+		if rec := post(h, `{"accounts":[],"recipients":["593999111222"],"message":"Aviso"}`); rec.Code != http.StatusBadRequest {
+			t.Fatalf("expected 400 without accounts, got %d", rec.Code)
+		}
+	})
+}
+
+func TestGroupEndpoints(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	metaSender := &fakeMetaSender{}
+	router := outbound.NewRouter(metaSender, outbound.RouterConfig{
+		MetaOutboundEnabled: true,
+		AccountPhoneNumber: map[string]string{
+			"bot-clientes":    "12345",
+			"bot-proveedores": "67890",
+		},
+	})
+	h := NewHandlers(ratelimit.NewLimiter(ratelimit.Config{}), nil, router, HandlerConfig{
+		AccountGroups: outbound.NewAccountGroups(map[string][]string{"production": {"bot-clientes"}}),
+	})
+	_, ginRouter := gin.CreateTestContext(httptest.NewRecorder())
+	RegisterRoutes(ginRouter, h)
+	do := func(method, path, body string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.RemoteAddr = "10.0.0.5:40000"
+		ginRouter.ServeHTTP(rec, req)
+		return rec
+	}
+
+	if rec := do(http.MethodPost, "/api/groups", `{"group_id":"production","accounts":[]}`); rec.Code != http.StatusConflict {
+		t.Fatalf("expected 409 for duplicate group, got %d", rec.Code)
+	}
+	if rec := do(http.MethodPost, "/api/groups/production/members", `{"account_id":"bot-proveedores"}`); rec.Code != http.StatusOK {
+		t.Fatalf("expected 200 adding member, got %d body=%s", rec.Code, rec.Body.String())
+	}
+
+	rec := do(http.MethodPost, "/api/groups/production/send", `{"recipients":["593999111222"],"message":"Aviso"}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200 for group send, got %d body=%s", rec.Code, rec.Body.String())
+	}
+	if metaSender.textCalls != 2 {
+		t.Fatalf("expected one send per member account, got %d", metaSender.textCalls)
+	}
+
+	if rec := do(http.MethodPost, "/api/groups/staging/send", `{"recipients":["593999111222"],"message":"Aviso"}`); rec.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 for unknown group, got %d", rec.Code)
+	}
+	if rec := do(http.MethodPost, "/api/groups", `{"group_id":"staging"}`); rec.Code != http.StatusCreated {
+		t.Fatalf("expected 201 creating group, got %d", rec.Code)
+	}
+	if rec := do(http.MethodPost, "/api/groups/staging/send", `{"recipients":["593999111222"],"message":"Aviso"}`); rec.Code != http.StatusConflict {
+		t.Fatalf("expected 409 sending to empty group, got %d", rec.Code)
+	}
+
+	if rec := do(http.MethodDelete, "/api/groups/production/members/bot-clientes", ""); rec.Code != http.StatusOK {
+		t.Fatalf("expected 200 removing member, got %d", rec.Code)
+	}
+	if rec := do(http.MethodDelete, "/api/groups/staging", ""); rec.Code != http.StatusNoContent {
+		t.Fatalf("expected 204 deleting group, got %d", rec.Code)
+	}
+
+	rec = do(http.MethodGet, "/api/groups", "")
+	var envelope struct {
+		Data struct {
+			Groups []outbound.AccountGroup `json:"groups"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &envelope); err != nil {
+		t.Fatalf("unmarshal groups: %v", err)
+	}
+	payload := envelope.Data
+	if len(payload.Groups) != 1 || len(payload.Groups[0].Accounts) != 1 || payload.Groups[0].Accounts[0] != "bot-proveedores" {
+		t.Fatalf("expected production with bot-proveedores only, got %+v", payload.Groups)
+	}
+}
+
+func TestABTestEndpoints(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	wc, err := webhook.NewWebhookClient("http://clientes", "http://proveedores", "", "", "", "/handle-whatsapp-message", 1000, 0, webhook.ClientConfig{})
+	if err != nil {
+		t.Fatalf("expected webhook client, got %v", err)
+	}
+	router := outbound.NewRouter(&fakeMetaSender{}, outbound.RouterConfig{})
+	h := NewHandlers(ratelimit.NewLimiter(ratelimit.Config{}), nil, router, HandlerConfig{ABTestManager: wc})
+	_, ginRouter := gin.CreateTestContext(httptest.NewRecorder())
+	RegisterRoutes(ginRouter, h)
+	do := func(method, path, body string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		ginRouter.ServeHTTP(rec, req)
+		return rec
+	}
+
+	if rec := do(http.MethodPut, "/api/ab-tests/bot-clientes", `{"variant_url":"http://v2","percentage":-5}`); rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for a negative percentage, got %d body=%s", rec.Code, rec.Body.String())
+	}
+	if rec := do(http.MethodPut, "/api/ab-tests/bot-clientes", `{"variant_url":"http://v2/","percentage":25}`); rec.Code != http.StatusOK {
+		t.Fatalf("expected 200 starting a test, got %d body=%s", rec.Code, rec.Body.String())
+	}
+	if tests := wc.ABTests(); tests["bot-clientes"].VariantURL != "http://v2" || tests["bot-clientes"].Percentage != 25 {
+		t.Fatalf("expected the test to reach the webhook client, got %+v", tests)
+	}
+
+	rec := do(http.MethodGet, "/api/ab-tests", "")
+	var envelope struct {
+		Data struct {
+			ABTests []ABTestInfo `json:"ab_tests"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &envelope); err != nil {
+		t.Fatalf("unmarshal A/B tests: %v", err)
+	}
+	if got := envelope.Data.ABTests; len(got) != 1 || got[0] != (ABTestInfo{AccountID: "bot-clientes", VariantURL: "http://v2", Percentage: 25}) {
+		t.Fatalf("expected the bot-clientes test, got %+v", got)
+	}
+
+	if rec := do(http.MethodDelete, "/api/ab-tests/bot-clientes", ""); rec.Code != http.StatusNoContent {
+		t.Fatalf("expected 204 stopping the test, got %d", rec.Code)
+	}
+	if rec := do(http.MethodDelete, "/api/ab-tests/bot-clientes", ""); rec.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 for a stopped test, got %d", rec.Code)
+	}
+}
+
+func TestWebhookRegionEndpoints(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	wc, err := webhook.NewWebhookClient("http://clientes", "http://proveedores", "", "", "", "/handle-whatsapp-message", 1000, 0, webhook.ClientConfig{
+		RegionURLs: map[string]map[string]string{"bot-clientes": {"CO": "http://co"}},
+	})
+	if err != nil {
+		t.Fatalf("expected webhook client, got %v", err)
+	}
+	router := outbound.NewRouter(&fakeMetaSender{}, outbound.RouterConfig{})
+	h := NewHandlers(ratelimit.NewLimiter(ratelimit.Config{}), nil, router, HandlerConfig{RegionManager: wc})
+	_, ginRouter := gin.CreateTestContext(httptest.NewRecorder())
+	RegisterRoutes(ginRouter, h)
+	do := func(method, path, body string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		ginRouter.ServeHTTP(rec, req)
+		return rec
+	}
+
+	if rec := do(http.MethodPut, "/api/webhook-regions/bot-clientes/us", `{}`); rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 without a webhook url, got %d body=%s", rec.Code, rec.Body.String())
+	}
+	if rec := do(http.MethodPut, "/api/webhook-regions/bot-clientes/us", `{"webhook_url":"http://us/"}`); rec.Code != http.StatusOK {
+		t.Fatalf("expected 200 adding a region, got %d body=%s", rec.Code, rec.Body.String())
+	}
+
+	rec := do(http.MethodGet, "/api/webhook-regions", "")
+	var envelope struct {
+		Data struct {
+			Regions []webhook.WebhookRegion `json:"regions"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &envelope); err != nil {
+		t.Fatalf("unmarshal regions: %v", err)
+	}
+	want := []webhook.WebhookRegion{
+		{AccountID: "bot-clientes", CountryCode: "CO", WebhookURL: "http://co"},
+		{AccountID: "bot-clientes", CountryCode: "US", WebhookURL: "http://us"},
+	}
+	if got := envelope.Data.Regions; len(got) != len(want) || got[0] != want[0] || got[1] != want[1] {
+		t.Fatalf("expected seeded and added regions, got %+v", got)
+	}
+
+	if rec := do(http.MethodDelete, "/api/webhook-regions/bot-clientes/CO", ""); rec.Code != http.StatusNoContent {
+		t.Fatalf("expected 204 removing a seeded region, got %d", rec.Code)
+	}
+	if rec := do(http.MethodDelete, "/api/webhook-regions/bot-clientes/CO", ""); rec.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 for a removed region, got %d", rec.Code)
+	}
+}
+
+func TestPostSendMultipartUploadsMedia(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	metaSender := &fakeMetaSender{}
+	router := outbound.NewRouter(metaSender, outbound.RouterConfig{
+		MetaOutboundEnabled: true,
+		AccountPhoneNumber:  map[string]string{"bot-clientes": "12345"},
+	})
+	handlers := NewHandlers(ratelimit.NewLimiter(ratelimit.Config{MaxPerHour: 20, MaxPer24h: 100}), nil, router, HandlerConfig{})
+	engine := gin.New()
+	engine.POST("/send", handlers.PostSend)
+
+	post := func(fields map[string]string, filename string, data []byte) *httptest.ResponseRecorder {
+		var body bytes.Buffer
+		form := multipart.NewWriter(&body)
+		for key, value := range fields {
+			if err := form.WriteField(key, value); err != nil {
+				t.Fatalf("write field: %v", err)
+			}
+		}
+		if filename != "" {
+			part, err := form.CreateFormFile("file", filename)
+			if err != nil {
+				t.Fatalf("create file part: %v", err)
+			}
+			part.Write(data)
+		}
+		form.Close()
+		req := httptest.NewRequest(http.MethodPost, "/send", &body)
+		req.Header.Set("Content-Type", form.FormDataContentType())
+		rec := httptest.NewRecorder()
+		engine.ServeHTTP(rec, req)
+		return rec
+	}
+	fields := map[string]string{"account_id": "bot-clientes", "to": "593999111222", "message": "Factura"}
+
+	png := append([]byte("\x89PNG\r\n\x1a\n"), make([]byte, 32)...)
+	rec := post(fields, "logo.png", png)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d body=%s", rec.Code, rec.Body.String())
+	}
+	if metaSender.imageCalls != 1 || metaSender.lastMIME != "image/png" || metaSender.lastBody != "Factura" {
+		t.Fatalf("expected png sent as image with caption, got image_calls=%d mime=%q caption=%q", metaSender.imageCalls, metaSender.lastMIME, metaSender.lastBody)
+	}
+
+	pdf := []byte("%PDF-1.4\n%fake\n")
+	rec = post(fields, "factura.pdf", pdf)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d body=%s", rec.Code, rec.Body.String())
+	}
+	if metaSender.documentCalls != 1 || metaSender.lastMIME != "application/pdf" || !bytes.Equal(metaSender.lastData, pdf) {
+		t.Fatalf("expected pdf sent as document, got document_calls=%d mime=%q", metaSender.documentCalls, metaSender.lastMIME)
+	}
+
+	rec = post(map[string]string{"account_id": "bot-clientes", "to": "593999111222"}, "", nil)
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 without message or file, got %d", rec.Code)
+	}
+	if metaSender.textCalls != 0 {
+		t.Fatalf("expected no text sends, got %d", metaSender.textCalls)
+	}
+}
+
+func TestPostSendToList(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	metaSender := &fakeMetaSender{}
+	router := outbound.NewRouter(metaSender, outbound.RouterConfig{
+		MetaOutboundEnabled: true,
+		AccountPhoneNumber:  map[string]string{"bot-clientes": "12345"},
+	})
+	limiter := ratelimit.NewLimiter(ratelimit.Config{MaxPerHour: 1, MaxPer24h: 100})
+	if err := limiter.Increment(context.Background(), "bot-clientes", "593999111333"); err != nil {
+		t.Fatalf("seed limiter: %v", err)
+	}
+	recorder := &fakeEventRecorder{}
+	handlers := NewHandlers(limiter, nil, router, HandlerConfig{EventRecorder: recorder})
+	engine := gin.New()
+	engine.POST("/send", handlers.PostSend)
+
+	body := `{"account_id":"bot-clientes","to_list":["593999111222","593999111333","52@s.whatsapp.net"],"message":"Aviso"}`
+	req := httptest.NewRequest(http.MethodPost, "/send", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	engine.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d body=%s", rec.Code, rec.Body.String())
+	}
+	var envelope struct {
+		Success bool `json:"success"`
+		Data    struct {
+			Sent    int          `json:"sent"`
+			Results []SendResult `json:"results"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &envelope); err != nil {
+		t.Fatalf("unmarshal response: %v", err)
+	}
+	results := envelope.Data.Results
+	if envelope.Success || envelope.Data.Sent != 1 || len(results) != 3 || metaSender.textCalls != 1 {
+		t.Fatalf("expected 1 of 3 sent, got success=%t sent=%d results=%+v calls=%d", envelope.Success, envelope.Data.Sent, results, metaSender.textCalls)
+	}
+	if !results[0].Success || results[1].Code != "RATE_LIMIT_EXCEEDED" || results[1].RetryAfter <= 0 || results[2].Code != "INVALID_PHONE" {
+		t.Fatalf("unexpected per-recipient results: %+v", results)
+	}
+	if len(recorder.events) != 1 || recorder.events[0].Destination != "593999111333" {
+		t.Fatalf("expected rate limit event for the limited recipient, got %+v", recorder.events)
+	}
+
+	req = httptest.NewRequest(http.MethodPost, "/send", strings.NewReader(`{"account_id":"bot-clientes","message":"Aviso"}`))
+	req.Header.Set("Content-Type", "application/json")
+	rec = httptest.NewRecorder()
+	engine.ServeHTTP(rec, req)
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 without to or to_list, got %d", rec.Code)
+	}
+}
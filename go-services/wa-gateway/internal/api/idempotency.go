@@ -0,0 +1,255 @@
+package api
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// IdempotencyKeyHeader lets clients retry a send without sending twice.
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+// maxFingerprintBytes is how much of a request body is hashed to detect a key
+// reused for a different request. It covers the largest idempotent upload.
+const maxFingerprintBytes = maxSendUploadBytes + 1<<20
+
+type idempotentResponse struct {
+	// fingerprint identifies the request body the key was first used with.
+	fingerprint string
+	status      int
+	contentType string
+	body        []byte
+	// envelope is the APIResponse behind body, when the handler wrote one;
+	// replays render it in the format the repeated request negotiates.
+	envelope  *APIResponse
+	createdAt time.Time
+	pending   bool
+}
+
+// IdempotencyStore keeps responses by idempotency key in memory for ttl.
+type IdempotencyStore struct {
+	mu        sync.Mutex
+	ttl       time.Duration
+	responses map[string]*idempotentResponse
+}
+
+// NewIdempotencyStore creates a store, defaulting ttl to 24 hours, and
+// starts its hourly cleanup.
+func NewIdempotencyStore(ttl time.Duration) *IdempotencyStore {
+	if ttl <= 0 {
+		ttl = 24 * time.Hour
+	}
+	s := &IdempotencyStore{
+		ttl:       ttl,
+		responses: make(map[string]*idempotentResponse),
+	}
+	go s.cleanupLoop()
+	return s
+}
+
+func (s *IdempotencyStore) cleanupLoop() {
+	ticker := time.NewTicker(time.Hour)
+	defer ticker.Stop()
+	for range ticker.C {
+		s.cleanup(time.Now())
+	}
+}
+
+func (s *IdempotencyStore) cleanup(now time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for key, resp := range s.responses {
+		if !resp.pending && now.Sub(resp.createdAt) > s.ttl {
+			delete(s.responses, key)
+		}
+	}
+}
+
+// begin returns the cached response for key, or reserves key for a new
+// request with the given body fingerprint. inFlight reports that another
+// request holds the key.
+func (s *IdempotencyStore) begin(key, fingerprint string, now time.Time) (cached *idempotentResponse, inFlight bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if resp, ok := s.responses[key]; ok && (resp.pending || now.Sub(resp.createdAt) <= s.ttl) {
+		if resp.pending {
+			return nil, true
+		}
+		return resp, false
+	}
+	s.responses[key] = &idempotentResponse{fingerprint: fingerprint, pending: true, createdAt: now}
+	return nil, false
+}
+
+// finish stores the response for key. Only responses that a retry would
+// reproduce are cached; the key is released for everything else (rate limits,
+// conflicts, server errors) so the client can retry them.
+func (s *IdempotencyStore) finish(key string, status int, contentType string, body []byte, envelope *APIResponse, now time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !replayableStatus(status) {
+		delete(s.responses, key)
+		return
+	}
+	var fingerprint string
+	if reserved, ok := s.responses[key]; ok {
+		fingerprint = reserved.fingerprint
+	}
+	s.responses[key] = &idempotentResponse{
+		fingerprint: fingerprint,
+		status:      status,
+		contentType: contentType,
+		body:        body,
+		envelope:    envelope,
+		createdAt:   now,
+	}
+}
+
+// release frees key without storing a response.
+func (s *IdempotencyStore) release(key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.responses, key)
+}
+
+// replayableStatus reports whether a response is deterministic for the same
+// request: a success or a validation error.
+func replayableStatus(status int) bool {
+	switch status {
+	case http.StatusBadRequest, http.StatusNotFound, http.StatusRequestEntityTooLarge, http.StatusUnprocessableEntity:
+		return true
+	}
+	return status >= 200 && status < 300
+}
+
+// recordingWriter tees the response body so it can be cached.
+type recordingWriter struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *recordingWriter) Write(data []byte) (int, error) {
+	w.body.Write(data)
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *recordingWriter) WriteString(data string) (int, error) {
+	w.body.WriteString(data)
+	return w.ResponseWriter.WriteString(data)
+}
+
+// IdempotencyMiddleware replays the stored response for a repeated
+// Idempotency-Key instead of running the handler again. Requests without the
+// header are passed through; a key whose first request is still running gets
+// 409, and a key reused with a different body gets 422. Keys are scoped to
+// the request path.
+func IdempotencyMiddleware(store *IdempotencyStore) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := strings.TrimSpace(c.GetHeader(IdempotencyKeyHeader))
+		if key == "" {
+			c.Next()
+			return
+		}
+		scopedKey := c.Request.URL.Path + "|" + key
+		fingerprint, err := requestFingerprint(c.Request)
+		if err != nil {
+			RespondError(c, http.StatusBadRequest, "INVALID_REQUEST", "read request body: "+err.Error())
+			c.Abort()
+			return
+		}
+
+		cached, inFlight := store.begin(scopedKey, fingerprint, time.Now())
+		if inFlight {
+			RespondError(c, http.StatusConflict, "REQUEST_IN_PROGRESS", "a request with this Idempotency-Key is still being processed")
+			c.Abort()
+			return
+		}
+		if cached != nil && cached.fingerprint != fingerprint {
+			log.Printf("[API] idempotency_key_reused path=%s key=%s", c.Request.URL.Path, key)
+			RespondError(c, http.StatusUnprocessableEntity, "IDEMPOTENCY_KEY_REUSED", "Idempotency-Key was already used with a different request body")
+			c.Abort()
+			return
+		}
+		if cached != nil {
+			log.Printf("[API] idempotent_replay path=%s key=%s status=%d", c.Request.URL.Path, key, cached.status)
+			c.Header("Idempotent-Replayed", "true")
+			if cached.envelope != nil {
+				renderResponse(c, cached.status, *cached.envelope)
+			} else {
+				c.Data(cached.status, cached.contentType, cached.body)
+			}
+			c.Abort()
+			return
+		}
+
+		writer := &recordingWriter{ResponseWriter: c.Writer}
+		c.Writer = writer
+		// A panicking handler never wrote its response; free the key so a
+		// retry runs again instead of replaying gin's default 200.
+		defer func() {
+			if recovered := recover(); recovered != nil {
+				store.release(scopedKey)
+				panic(recovered)
+			}
+		}()
+		c.Next()
+		store.finish(scopedKey, writer.Status(), writer.Header().Get("Content-Type"), writer.body.Bytes(), storedEnvelope(c), time.Now())
+	}
+}
+
+// requestFingerprint hashes the request body, up to maxFingerprintBytes, and
+// leaves the body readable for the handler. Multipart boundaries are left out
+// so a client that rebuilds the same form on retry gets the same fingerprint.
+func requestFingerprint(req *http.Request) (string, error) {
+	if req.Body == nil {
+		return "", nil
+	}
+	head, err := io.ReadAll(io.LimitReader(req.Body, maxFingerprintBytes))
+	if err != nil {
+		return "", err
+	}
+	req.Body = readCloser{Reader: io.MultiReader(bytes.NewReader(head), req.Body), Closer: req.Body}
+
+	hashed := head
+	if _, params, err := mime.ParseMediaType(req.Header.Get("Content-Type")); err == nil && params["boundary"] != "" {
+		hashed = bytes.ReplaceAll(head, []byte(params["boundary"]), nil)
+	}
+	sum := sha256.Sum256(hashed)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
+
+// storedEnvelope returns the envelope the handler wrote, with its data frozen
+// as JSON so later changes to the original values do not leak into replays.
+func storedEnvelope(c *gin.Context) *APIResponse {
+	value, ok := c.Get(responseEnvelopeKey)
+	if !ok {
+		return nil
+	}
+	envelope, ok := value.(APIResponse)
+	if !ok {
+		return nil
+	}
+	if envelope.Data != nil {
+		data, err := json.Marshal(envelope.Data)
+		if err != nil {
+			return nil
+		}
+		envelope.Data = json.RawMessage(data)
+	}
+	return &envelope
+}
@@ -0,0 +1,200 @@
+package api
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/tinkubot/wa-gateway/internal/outbound"
+	"github.com/tinkubot/wa-gateway/internal/ratelimit"
+)
+
+func TestPostSendReplaysIdempotentRequests(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	metaSender := &fakeMetaSender{}
+	router := outbound.NewRouter(metaSender, outbound.RouterConfig{
+		MetaOutboundEnabled: true,
+		AccountPhoneNumber:  map[string]string{"bot-clientes": "12345"},
+	})
+	h := NewHandlers(ratelimit.NewLimiter(ratelimit.Config{MaxPerHour: 20, MaxPer24h: 100}), nil, router, HandlerConfig{})
+	engine := gin.New()
+	RegisterRoutes(engine, h)
+	sendAs := func(key, accept string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/api/send", strings.NewReader(`{"account_id":"bot-clientes","to":"593999111222","message":"Hola"}`))
+		req.Header.Set("Content-Type", "application/json")
+		if accept != "" {
+			req.Header.Set("Accept", accept)
+		}
+		if key != "" {
+			req.Header.Set(IdempotencyKeyHeader, key)
+		}
+		rec := httptest.NewRecorder()
+		engine.ServeHTTP(rec, req)
+		return rec
+	}
+	send := func(key string) *httptest.ResponseRecorder { return sendAs(key, "") }
+
+	first := send("retry-1")
+	second := send("retry-1")
+	if first.Code != http.StatusOK || second.Code != http.StatusOK {
+		t.Fatalf("expected 200 twice, got %d and %d", first.Code, second.Code)
+	}
+	if metaSender.textCalls != 1 {
+		t.Fatalf("expected one send for repeated key, got %d", metaSender.textCalls)
+	}
+	if second.Header().Get("Idempotent-Replayed") != "true" || second.Body.String() != first.Body.String() {
+		t.Fatalf("expected replay of first response, got header=%q body=%s", second.Header().Get("Idempotent-Replayed"), second.Body.String())
+	}
+	// A replay is rendered in the format the repeated request asks for.
+	xmlReplay := sendAs("retry-1", "application/xml")
+	if !strings.HasPrefix(xmlReplay.Header().Get("Content-Type"), "application/xml") || !strings.HasPrefix(xmlReplay.Body.String(), "<response><success>true</success>") {
+		t.Fatalf("expected xml replay, got %q body=%s", xmlReplay.Header().Get("Content-Type"), xmlReplay.Body.String())
+	}
+	if metaSender.textCalls != 1 {
+		t.Fatalf("expected xml replay not to send again, got %d calls", metaSender.textCalls)
+	}
+
+	send("retry-2")
+	send("")
+	send("")
+	if metaSender.textCalls != 4 {
+		t.Fatalf("expected new and missing keys to send, got %d calls", metaSender.textCalls)
+	}
+
+	// Server errors are not cached so the client can retry them.
+	metaSender.sendErr = errors.New("meta send status=500")
+	if rec := send("retry-3"); rec.Code != http.StatusInternalServerError {
+		t.Fatalf("expected 500 from failing send, got %d", rec.Code)
+	}
+	metaSender.sendErr = nil
+	if rec := send("retry-3"); rec.Code != http.StatusOK || rec.Header().Get("Idempotent-Replayed") != "" {
+		t.Fatalf("expected retried 500 to run again, got %d replayed=%q", rec.Code, rec.Header().Get("Idempotent-Replayed"))
+	}
+}
+
+func TestIdempotencyKeyReusedWithDifferentBody(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	metaSender := &fakeMetaSender{}
+	router := outbound.NewRouter(metaSender, outbound.RouterConfig{
+		MetaOutboundEnabled: true,
+		AccountPhoneNumber:  map[string]string{"bot-clientes": "12345"},
+	})
+	engine := gin.New()
+	RegisterRoutes(engine, NewHandlers(ratelimit.NewLimiter(ratelimit.Config{MaxPerHour: 20, MaxPer24h: 100}), nil, router, HandlerConfig{}))
+	send := func(message string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/api/send", strings.NewReader(`{"account_id":"bot-clientes","to":"593999111222","message":"`+message+`"}`))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set(IdempotencyKeyHeader, "retry-1")
+		rec := httptest.NewRecorder()
+		engine.ServeHTTP(rec, req)
+		return rec
+	}
+
+	if rec := send("Hola"); rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rec.Code)
+	}
+	rec := send("Chao")
+	if rec.Code != http.StatusUnprocessableEntity || !strings.Contains(rec.Body.String(), "IDEMPOTENCY_KEY_REUSED") {
+		t.Fatalf("expected 422 for reused key, got %d body=%s", rec.Code, rec.Body.String())
+	}
+	if metaSender.textCalls != 1 || metaSender.lastBody != "Hola" {
+		t.Fatalf("expected only the first message to be sent, got %d calls last=%q", metaSender.textCalls, metaSender.lastBody)
+	}
+}
+
+func TestIdempotencyMiddlewareReleasesKeyOnPanic(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	engine := gin.New()
+	engine.Use(gin.CustomRecovery(func(c *gin.Context, _ any) {
+		c.AbortWithStatus(http.StatusInternalServerError)
+	}))
+	calls := 0
+	engine.POST("/send", IdempotencyMiddleware(NewIdempotencyStore(0)), func(c *gin.Context) {
+		calls++
+		if calls == 1 {
+			panic("boom")
+		}
+		Respond(c, http.StatusOK, gin.H{"calls": calls})
+	})
+	send := func() *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/send", strings.NewReader(`{}`))
+		req.Header.Set(IdempotencyKeyHeader, "retry-1")
+		rec := httptest.NewRecorder()
+		engine.ServeHTTP(rec, req)
+		return rec
+	}
+
+	if rec := send(); rec.Code != http.StatusInternalServerError {
+		t.Fatalf("expected 500 from panicking handler, got %d", rec.Code)
+	}
+	rec := send()
+	if rec.Code != http.StatusOK || rec.Header().Get("Idempotent-Replayed") != "" || calls != 2 {
+		t.Fatalf("expected retry to run the handler again, got %d replayed=%q calls=%d", rec.Code, rec.Header().Get("Idempotent-Replayed"), calls)
+	}
+}
+
+func TestRequestFingerprintIgnoresMultipartBoundary(t *testing.T) {
+	fingerprint := func(boundary string) string {
+		body := "--" + boundary + "\r\nContent-Disposition: form-data; name=\"to\"\r\n\r\n593999111222\r\n--" + boundary + "--\r\n"
+		req := httptest.NewRequest(http.MethodPost, "/api/send", strings.NewReader(body))
+		req.Header.Set("Content-Type", "multipart/form-data; boundary="+boundary)
+		got, err := requestFingerprint(req)
+		if err != nil {
+			t.Fatalf("fingerprint: %v", err)
+		}
+		rest, _ := io.ReadAll(req.Body)
+		if string(rest) != body {
+			t.Fatalf("expected body to stay readable, got %q", rest)
+		}
+		return got
+	}
+	if fingerprint("aaaa") != fingerprint("bbbb") {
+		t.Fatal("expected the same form with a new boundary to keep its fingerprint")
+	}
+}
+
+func TestIdempotencyStoreExpiresAndRejectsInFlight(t *testing.T) {
+	store := &IdempotencyStore{ttl: time.Hour, responses: map[string]*idempotentResponse{}}
+	now := time.Now()
+
+	if cached, inFlight := store.begin("k", "body", now); cached != nil || inFlight {
+		t.Fatalf("expected fresh key to be reserved, got cached=%v inFlight=%t", cached, inFlight)
+	}
+	if _, inFlight := store.begin("k", "body", now); !inFlight {
+		t.Fatal("expected concurrent request to be reported in flight")
+	}
+	store.finish("k", http.StatusOK, "application/json", []byte(`{}`), nil, now)
+	if cached, _ := store.begin("k", "body", now.Add(30*time.Minute)); cached == nil {
+		t.Fatal("expected cached response within ttl")
+	}
+
+	store.cleanup(now.Add(2 * time.Hour))
+	if len(store.responses) != 0 {
+		t.Fatalf("expected expired response to be cleaned up, got %d", len(store.responses))
+	}
+}
+
+func TestIdempotencyStoreDoesNotCacheTransientErrors(t *testing.T) {
+	store := &IdempotencyStore{ttl: time.Hour, responses: map[string]*idempotentResponse{}}
+	now := time.Now()
+
+	for _, status := range []int{http.StatusTooManyRequests, http.StatusConflict, http.StatusBadGateway} {
+		store.begin("k", "body", now)
+		store.finish("k", status, "application/json", []byte(`{}`), nil, now)
+		if cached, inFlight := store.begin("k", "body", now); cached != nil || inFlight {
+			t.Fatalf("expected %d to release the key, got cached=%v inFlight=%t", status, cached, inFlight)
+		}
+		delete(store.responses, "k")
+	}
+
+	store.begin("k", "body", now)
+	store.finish("k", http.StatusBadRequest, "application/json", []byte(`{}`), nil, now)
+	if cached, _ := store.begin("k", "body", now); cached == nil || cached.status != http.StatusBadRequest {
+		t.Fatalf("expected 400 to be replayed, got %v", cached)
+	}
+}
@@ -0,0 +1,114 @@
+package api
+
+import (
+	"crypto/subtle"
+	"log"
+	"net/http"
+	"net/netip"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// IPAllowlistMiddleware rejects requests whose peer address is not in
+// allowedCIDRs with 403 {"error": "ip_not_allowed"}. Entries may be CIDRs or
+// bare addresses; invalid entries are logged and skipped. IPv4-mapped IPv6
+// addresses (::ffff:a.b.c.d) match IPv4 entries and vice versa.
+//
+// The check uses the TCP peer address, not X-Forwarded-For, so it cannot be
+// bypassed with a spoofed header.
+func IPAllowlistMiddleware(allowedCIDRs []string) gin.HandlerFunc {
+	prefixes := parseAllowlist(allowedCIDRs)
+	return func(c *gin.Context) {
+		addr, err := netip.ParseAddr(c.RemoteIP())
+		if err == nil && allowlistContains(prefixes, addr) {
+			c.Next()
+			return
+		}
+		log.Printf("[API] ip_not_allowed remote_ip=%s path=%s", c.RemoteIP(), c.Request.URL.Path)
+		c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "ip_not_allowed"})
+	}
+}
+
+// adminMiddleware guards the sensitive routes with IPAllowlistMiddleware
+// when an allowlist is configured, and lets every request through otherwise.
+func adminMiddleware(allowedCIDRs []string) gin.HandlerFunc {
+	if len(allowedCIDRs) == 0 {
+		return func(c *gin.Context) { c.Next() }
+	}
+	return IPAllowlistMiddleware(allowedCIDRs)
+}
+
+func parseAllowlist(entries []string) []netip.Prefix {
+	prefixes := make([]netip.Prefix, 0, len(entries))
+	for _, entry := range entries {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		prefix, err := netip.ParsePrefix(entry)
+		if err != nil {
+			addr, addrErr := netip.ParseAddr(entry)
+			if addrErr != nil {
+				log.Printf("[API] invalid_ip_allowlist_entry entry=%q err=%v", entry, err)
+				continue
+			}
+			prefix = netip.PrefixFrom(addr, addr.BitLen())
+		}
+		prefixes = append(prefixes, unmapPrefix(prefix).Masked())
+	}
+	return prefixes
+}
+
+// unmapPrefix rewrites ::ffff:a.b.c.d/n as a.b.c.d/(n-96).
+func unmapPrefix(prefix netip.Prefix) netip.Prefix {
+	addr := prefix.Addr()
+	if !addr.Is4In6() {
+		return prefix
+	}
+	bits := prefix.Bits() - 96
+	if bits < 0 {
+		bits = 0
+	}
+	return netip.PrefixFrom(addr.Unmap(), bits)
+}
+
+func allowlistContains(prefixes []netip.Prefix, addr netip.Addr) bool {
+	addr = addr.Unmap()
+	for _, prefix := range prefixes {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// APIKeyHeader carries the key checked by APIKeyMiddleware.
+const APIKeyHeader = "X-API-Key"
+
+// APIKeyMiddleware rejects requests whose X-API-Key does not match key with
+// 401 and code UNAUTHORIZED.
+func APIKeyMiddleware(key string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if subtle.ConstantTimeCompare([]byte(c.GetHeader(APIKeyHeader)), []byte(key)) == 1 {
+			c.Next()
+			return
+		}
+		log.Printf("[API] invalid_api_key remote_ip=%s path=%s", c.RemoteIP(), c.Request.URL.Path)
+		RespondError(c, http.StatusUnauthorized, "UNAUTHORIZED", "missing or invalid "+APIKeyHeader)
+		c.Abort()
+	}
+}
+
+// ContentNegotiationMiddleware selects XML envelopes for clients whose Accept
+// header prefers application/xml or text/xml; JSON stays the default.
+func ContentNegotiationMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Header("Vary", "Accept")
+		switch c.NegotiateFormat(gin.MIMEJSON, gin.MIMEXML, gin.MIMEXML2) {
+		case gin.MIMEXML, gin.MIMEXML2:
+			c.Set(responseFormatKey, gin.MIMEXML)
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,109 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/tinkubot/wa-gateway/internal/outbound"
+	"github.com/tinkubot/wa-gateway/internal/ratelimit"
+)
+
+func TestIPAllowlistMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/api/admin/ping", IPAllowlistMiddleware([]string{"10.0.0.0/8", "2001:db8::/32", "192.168.1.7", "not-a-cidr"}), func(c *gin.Context) {
+		c.Status(http.StatusNoContent)
+	})
+
+	tests := []struct {
+		name         string
+		remoteAddr   string
+		forwardedFor string
+		expectedCode int
+	}{
+		{name: "ipv4 in range", remoteAddr: "10.1.2.3:5000", expectedCode: http.StatusNoContent},
+		{name: "ipv4-mapped ipv6 in range", remoteAddr: "[::ffff:10.1.2.3]:5000", expectedCode: http.StatusNoContent},
+		{name: "ipv6 in range", remoteAddr: "[2001:db8::1]:5000", expectedCode: http.StatusNoContent},
+		{name: "bare address", remoteAddr: "192.168.1.7:5000", expectedCode: http.StatusNoContent},
+		{name: "outside allowlist", remoteAddr: "203.0.113.9:5000", expectedCode: http.StatusForbidden},
+		{name: "spoofed forwarded header", remoteAddr: "203.0.113.9:5000", forwardedFor: "10.1.2.3", expectedCode: http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/api/admin/ping", nil)
+			req.RemoteAddr = tt.remoteAddr
+			if tt.forwardedFor != "" {
+				req.Header.Set("X-Forwarded-For", tt.forwardedFor)
+			}
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			if rec.Code != tt.expectedCode {
+				t.Fatalf("expected %d, got %d", tt.expectedCode, rec.Code)
+			}
+			if tt.expectedCode == http.StatusForbidden && rec.Body.String() != `{"error":"ip_not_allowed"}` {
+				t.Fatalf("expected ip_not_allowed body, got %s", rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestAdminRoutesRequireAllowlistedIP(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := outbound.NewRouter(&fakeMetaSender{}, outbound.RouterConfig{
+		MetaOutboundEnabled: true,
+		AccountPhoneNumber:  map[string]string{"bot-clientes": "12345"},
+	})
+	newEngine := func(allowlist []string) *gin.Engine {
+		engine := gin.New()
+		RegisterRoutes(engine, NewHandlers(ratelimit.NewLimiter(ratelimit.Config{}), nil, router, HandlerConfig{AdminIPAllowlist: allowlist}))
+		return engine
+	}
+	do := func(engine *gin.Engine, method, path, remoteAddr string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(`{}`))
+		req.Header.Set("Content-Type", "application/json")
+		req.RemoteAddr = remoteAddr
+		rec := httptest.NewRecorder()
+		engine.ServeHTTP(rec, req)
+		return rec
+	}
+	routes := []struct{ method, path string }{
+		{http.MethodPost, "/api/broadcast"},
+		{http.MethodGet, "/api/groups"},
+		{http.MethodPost, "/api/groups"},
+		{http.MethodDelete, "/api/groups/production"},
+		{http.MethodPost, "/api/groups/production/members"},
+		{http.MethodDelete, "/api/groups/production/members/bot-clientes"},
+		{http.MethodPost, "/api/groups/production/send"},
+		{http.MethodPost, "/api/accounts/bot-clientes/test-webhook"},
+		{http.MethodGet, "/api/ab-tests"},
+		{http.MethodPut, "/api/ab-tests/bot-clientes"},
+		{http.MethodDelete, "/api/ab-tests/bot-clientes"},
+		{http.MethodGet, "/api/webhook-regions"},
+		{http.MethodPut, "/api/webhook-regions/bot-clientes/US"},
+		{http.MethodDelete, "/api/webhook-regions/bot-clientes/US"},
+		{http.MethodPost, "/api/accounts/bot-clientes/send-bulk-csv"},
+		{http.MethodGet, "/api/accounts/bot-clientes/bulk-jobs/bulk_1"},
+		{http.MethodPut, "/api/accounts/bot-clientes/status-message"},
+		{http.MethodPut, "/api/accounts/bot-clientes/profile-picture"},
+	}
+
+	configured := newEngine([]string{"198.51.100.0/24"})
+	unset := newEngine(nil)
+	for _, route := range routes {
+		rec := do(configured, route.method, route.path, "203.0.113.9:5000")
+		if rec.Code != http.StatusForbidden || rec.Body.String() != `{"error":"ip_not_allowed"}` {
+			t.Fatalf("%s %s: expected 403 ip_not_allowed outside the allowlist, got %d body=%s", route.method, route.path, rec.Code, rec.Body.String())
+		}
+		if rec := do(configured, route.method, route.path, "198.51.100.7:5000"); rec.Code == http.StatusForbidden {
+			t.Fatalf("%s %s: expected the allowlist to admit its range, got %d", route.method, route.path, rec.Code)
+		}
+		if rec := do(unset, route.method, route.path, "203.0.113.9:5000"); rec.Code == http.StatusForbidden {
+			t.Fatalf("%s %s: expected no allowlist when ADMIN_IP_ALLOWLIST is unset, got %d", route.method, route.path, rec.Code)
+		}
+	}
+}
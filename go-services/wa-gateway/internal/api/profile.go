@@ -0,0 +1,117 @@
+package api
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/tinkubot/wa-gateway/internal/metaoutbound"
+)
+
+// maxProfilePictureRequestBytes caps PUT profile-picture bodies. It leaves
+// room for base64 overhead; the decoded image is limited to 5 MB by
+// metaoutbound.
+const maxProfilePictureRequestBytes = 8 << 20
+
+// StatusMessageRequest is the body of PUT /api/accounts/:accountId/status-message.
+type StatusMessageRequest struct {
+	Message string `json:"message" binding:"required"`
+}
+
+// PutStatusMessage sets the account's WhatsApp status message, the "about"
+// text of its business profile (max 139 characters).
+func (h *Handlers) PutStatusMessage(c *gin.Context) {
+	accountID := c.Param("accountId")
+	var req StatusMessageRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		RespondError(c, http.StatusBadRequest, "INVALID_REQUEST", err.Error())
+		return
+	}
+	message := strings.TrimSpace(req.Message)
+	if message == "" {
+		RespondError(c, http.StatusBadRequest, "INVALID_REQUEST", "message is empty")
+		return
+	}
+	if err := h.outbound.SetStatusMessage(c.Request.Context(), accountID, message); err != nil {
+		log.Printf("[API] status_message_failed account=%s err=%v", accountID, err)
+		status, code := profileErrorStatus(err)
+		RespondError(c, status, code, err.Error())
+		return
+	}
+	log.Printf("[API] status_message_updated account=%s", accountID)
+	Respond(c, http.StatusOK, gin.H{
+		"account_id": accountID,
+		"message":    message,
+	})
+}
+
+// ProfilePictureRequest is the JSON body of PUT
+// /api/accounts/:accountId/profile-picture.
+type ProfilePictureRequest struct {
+	ImageBase64 string `json:"image_base64" binding:"required"`
+}
+
+// PutProfilePicture replaces the account's profile picture. The JPEG image
+// (max 5 MB) is sent as a multipart "file" part or as base64 in JSON.
+func (h *Handlers) PutProfilePicture(c *gin.Context) {
+	accountID := c.Param("accountId")
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxProfilePictureRequestBytes)
+	data, err := bindProfilePicture(c)
+	if err != nil {
+		RespondError(c, http.StatusBadRequest, "INVALID_REQUEST", err.Error())
+		return
+	}
+	if err := h.outbound.SetProfilePicture(c.Request.Context(), accountID, data); err != nil {
+		log.Printf("[API] profile_picture_failed account=%s bytes=%d err=%v", accountID, len(data), err)
+		status, code := profileErrorStatus(err)
+		RespondError(c, status, code, err.Error())
+		return
+	}
+	log.Printf("[API] profile_picture_updated account=%s bytes=%d", accountID, len(data))
+	Respond(c, http.StatusOK, gin.H{
+		"account_id": accountID,
+		"bytes":      len(data),
+	})
+}
+
+func bindProfilePicture(c *gin.Context) ([]byte, error) {
+	if c.ContentType() == gin.MIMEMultipartPOSTForm {
+		file, _, err := c.Request.FormFile("file")
+		if err != nil {
+			return nil, fmt.Errorf("read file: %w", err)
+		}
+		defer file.Close()
+		return io.ReadAll(file)
+	}
+	var req ProfilePictureRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		return nil, err
+	}
+	encoded := strings.TrimSpace(req.ImageBase64)
+	if _, rest, ok := strings.Cut(encoded, ";base64,"); ok && strings.HasPrefix(encoded, "data:") {
+		encoded = rest
+	}
+	data, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("decode image_base64: %w", err)
+	}
+	return data, nil
+}
+
+// profileErrorStatus maps profile update errors like sendErrorStatus, with
+// Graph API failures reported as PROFILE_UPDATE_FAILED.
+func profileErrorStatus(err error) (int, string) {
+	if errors.Is(err, metaoutbound.ErrInvalidProfilePicture) {
+		return http.StatusBadRequest, "INVALID_IMAGE"
+	}
+	status, code := sendErrorStatus(err)
+	if code == "SEND_FAILED" {
+		return http.StatusBadGateway, "PROFILE_UPDATE_FAILED"
+	}
+	return status, code
+}
@@ -0,0 +1,138 @@
+package api
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/tinkubot/wa-gateway/internal/metaoutbound"
+	"github.com/tinkubot/wa-gateway/internal/outbound"
+	"github.com/tinkubot/wa-gateway/internal/ratelimit"
+)
+
+func TestPutStatusMessage(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	metaSender := &fakeMetaSender{}
+	router := outbound.NewRouter(metaSender, outbound.RouterConfig{
+		MetaOutboundEnabled: true,
+		AccountPhoneNumber:  map[string]string{"bot-clientes": "12345"},
+	})
+	handlers := NewHandlers(ratelimit.NewLimiter(ratelimit.Config{MaxPerHour: 20, MaxPer24h: 100}), nil, router, HandlerConfig{})
+	engine := gin.New()
+	engine.PUT("/accounts/:accountId/status-message", handlers.PutStatusMessage)
+
+	put := func(accountID, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPut, "/accounts/"+accountID+"/status-message", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+		engine.ServeHTTP(rec, req)
+		return rec
+	}
+
+	if rec := put("bot-clientes", `{"message":"Atención 24/7"}`); rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d body=%s", rec.Code, rec.Body.String())
+	}
+	if metaSender.lastAbout != "Atención 24/7" {
+		t.Fatalf("expected about to be set, got %q", metaSender.lastAbout)
+	}
+	if rec := put("bot-clientes", `{"message":"  "}`); rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for blank message, got %d", rec.Code)
+	}
+	if rec := put("bot-unknown", `{"message":"Hola"}`); rec.Code != http.StatusServiceUnavailable {
+		t.Fatalf("expected 503 for unrouted account, got %d", rec.Code)
+	}
+	metaSender.sendErr = errors.New("meta send status=400")
+	if rec := put("bot-clientes", `{"message":"Hola"}`); rec.Code != http.StatusBadGateway || !strings.Contains(rec.Body.String(), "PROFILE_UPDATE_FAILED") {
+		t.Fatalf("expected 502 PROFILE_UPDATE_FAILED, got %d body=%s", rec.Code, rec.Body.String())
+	}
+}
+
+func TestPutProfilePicture(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	metaSender := &fakeMetaSender{}
+	router := outbound.NewRouter(metaSender, outbound.RouterConfig{
+		MetaOutboundEnabled: true,
+		AccountPhoneNumber:  map[string]string{"bot-clientes": "12345"},
+	})
+	handlers := NewHandlers(ratelimit.NewLimiter(ratelimit.Config{MaxPerHour: 20, MaxPer24h: 100}), nil, router, HandlerConfig{})
+	engine := gin.New()
+	engine.PUT("/accounts/:accountId/profile-picture", handlers.PutProfilePicture)
+	jpeg := []byte("\xff\xd8\xff\xe0fake-jpeg")
+
+	req := httptest.NewRequest(http.MethodPut, "/accounts/bot-clientes/profile-picture", strings.NewReader(`{"image_base64":"data:image/jpeg;base64,`+base64.StdEncoding.EncodeToString(jpeg)+`"}`))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	engine.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK || !bytes.Equal(metaSender.lastPicture, jpeg) {
+		t.Fatalf("expected JSON image to be set, got %d body=%s", rec.Code, rec.Body.String())
+	}
+
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	part, err := form.CreateFormFile("file", "logo.jpg")
+	if err != nil {
+		t.Fatalf("create file part: %v", err)
+	}
+	part.Write([]byte("\xff\xd8\xff\xe0multipart"))
+	form.Close()
+	req = httptest.NewRequest(http.MethodPut, "/accounts/bot-clientes/profile-picture", &body)
+	req.Header.Set("Content-Type", form.FormDataContentType())
+	rec = httptest.NewRecorder()
+	engine.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK || string(metaSender.lastPicture) != "\xff\xd8\xff\xe0multipart" {
+		t.Fatalf("expected multipart image to be set, got %d body=%s", rec.Code, rec.Body.String())
+	}
+
+	req = httptest.NewRequest(http.MethodPut, "/accounts/bot-clientes/profile-picture", strings.NewReader(`{"image_base64":"%%%"}`))
+	req.Header.Set("Content-Type", "application/json")
+	rec = httptest.NewRecorder()
+	engine.ServeHTTP(rec, req)
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for invalid base64, got %d", rec.Code)
+	}
+
+	metaSender.sendErr = metaoutbound.ErrInvalidProfilePicture
+	req = httptest.NewRequest(http.MethodPut, "/accounts/bot-clientes/profile-picture", strings.NewReader(`{"image_base64":"`+base64.StdEncoding.EncodeToString(jpeg)+`"}`))
+	req.Header.Set("Content-Type", "application/json")
+	rec = httptest.NewRecorder()
+	engine.ServeHTTP(rec, req)
+	if rec.Code != http.StatusBadRequest || !strings.Contains(rec.Body.String(), "INVALID_IMAGE") {
+		t.Fatalf("expected 400 INVALID_IMAGE, got %d body=%s", rec.Code, rec.Body.String())
+	}
+}
+
+func TestProfileRoutesHonorAdminAllowlist(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := outbound.NewRouter(&fakeMetaSender{}, outbound.RouterConfig{
+		MetaOutboundEnabled: true,
+		AccountPhoneNumber:  map[string]string{"bot-clientes": "12345"},
+	})
+	engine := gin.New()
+	RegisterRoutes(engine, NewHandlers(ratelimit.NewLimiter(ratelimit.Config{}), nil, router, HandlerConfig{AdminIPAllowlist: []string{"10.0.0.0/8"}}))
+
+	tests := []struct {
+		path       string
+		remoteAddr string
+		expected   int
+	}{
+		{path: "/api/accounts/bot-clientes/status-message", remoteAddr: "203.0.113.9:5000", expected: http.StatusForbidden},
+		{path: "/api/accounts/bot-clientes/status-message", remoteAddr: "10.0.0.5:5000", expected: http.StatusOK},
+		{path: "/api/accounts/bot-clientes/profile-picture", remoteAddr: "203.0.113.9:5000", expected: http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodPut, tt.path, strings.NewReader(`{"message":"Hola"}`))
+		req.Header.Set("Content-Type", "application/json")
+		req.RemoteAddr = tt.remoteAddr
+		rec := httptest.NewRecorder()
+		engine.ServeHTTP(rec, req)
+		if rec.Code != tt.expected {
+			t.Fatalf("%s from %s: expected %d, got %d", tt.path, tt.remoteAddr, tt.expected, rec.Code)
+		}
+	}
+}
@@ -0,0 +1,178 @@
+package api
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequestIDHeader carries the caller's request ID, echoed back in every
+// response envelope. One is generated when the request has none.
+const RequestIDHeader = "X-Request-ID"
+
+const (
+	requestIDKey        = "request_id"
+	responseFormatKey   = "response_format"
+	responseEnvelopeKey = "response_envelope"
+)
+
+// APIResponse is the envelope of every /api response. Health probes and the
+// Meta webhook keep their own shapes.
+type APIResponse struct {
+	XMLName   xml.Name `json:"-" xml:"response"`
+	Success   bool     `json:"success" xml:"success"`
+	Data      any      `json:"data,omitempty" xml:"data,omitempty"`
+	Error     string   `json:"error,omitempty" xml:"error,omitempty"`
+	Code      string   `json:"code,omitempty" xml:"code,omitempty"`
+	RequestID string   `json:"request_id" xml:"request_id"`
+	Timestamp string   `json:"timestamp" xml:"timestamp"`
+}
+
+// Respond writes data in a successful envelope.
+func Respond(c *gin.Context, status int, data interface{}) {
+	writeResponse(c, status, APIResponse{Success: true, Data: data})
+}
+
+// RespondError writes a failed envelope with a machine-readable code.
+func RespondError(c *gin.Context, status int, code, msg string) {
+	writeResponse(c, status, APIResponse{Error: msg, Code: code})
+}
+
+// writeResponse stamps resp with the request ID and time and writes it as
+// JSON, or as XML when ContentNegotiationMiddleware selected it. The stamped
+// envelope is kept on the context so idempotent replays can render it again.
+func writeResponse(c *gin.Context, status int, resp APIResponse) {
+	resp.RequestID = requestID(c)
+	resp.Timestamp = time.Now().UTC().Format(time.RFC3339)
+	c.Set(responseEnvelopeKey, resp)
+	renderResponse(c, status, resp)
+}
+
+// renderResponse writes resp in the negotiated format as is.
+func renderResponse(c *gin.Context, status int, resp APIResponse) {
+	if c.GetString(responseFormatKey) != gin.MIMEXML {
+		c.JSON(status, resp)
+		return
+	}
+	if resp.Data != nil {
+		tree, err := toXMLTree(resp.Data)
+		if err != nil {
+			resp = APIResponse{Error: err.Error(), Code: "ENCODING_FAILED", RequestID: resp.RequestID, Timestamp: resp.Timestamp}
+			status = http.StatusInternalServerError
+		} else {
+			resp.Data = tree
+		}
+	}
+	c.XML(status, resp)
+}
+
+// xmlTree encodes decoded JSON as XML: objects become one element per key
+// in sorted order and arrays repeat <item>. Going through JSON keeps XML
+// field names identical to the JSON ones and handles maps, which
+// encoding/xml cannot marshal. Keys that are not valid element names, such
+// as phone numbers or "a:b", become <entry key="...">.
+type xmlTree struct {
+	value any
+}
+
+func toXMLTree(data any) (xmlTree, error) {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return xmlTree{}, fmt.Errorf("encode xml: %w", err)
+	}
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+	var value any
+	if err := decoder.Decode(&value); err != nil {
+		return xmlTree{}, fmt.Errorf("encode xml: %w", err)
+	}
+	return xmlTree{value: value}, nil
+}
+
+func (t xmlTree) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	if err := e.EncodeToken(start); err != nil {
+		return err
+	}
+	switch v := t.value.(type) {
+	case map[string]any:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			if err := e.EncodeElement(xmlTree{value: v[key]}, xmlElementFor(key)); err != nil {
+				return err
+			}
+		}
+	case []any:
+		for _, item := range v {
+			if err := e.EncodeElement(xmlTree{value: item}, xml.StartElement{Name: xml.Name{Local: "item"}}); err != nil {
+				return err
+			}
+		}
+	case nil:
+	default:
+		if err := e.EncodeToken(xml.CharData(fmt.Sprint(v))); err != nil {
+			return err
+		}
+	}
+	return e.EncodeToken(start.End())
+}
+
+// xmlElementFor returns the element for an object key: the key itself when
+// it is a valid XML name, <entry key="..."> otherwise.
+func xmlElementFor(key string) xml.StartElement {
+	if isXMLName(key) {
+		return xml.StartElement{Name: xml.Name{Local: key}}
+	}
+	return xml.StartElement{
+		Name: xml.Name{Local: "entry"},
+		Attr: []xml.Attr{{Name: xml.Name{Local: "key"}, Value: key}},
+	}
+}
+
+// isXMLName reports whether key can be used as an element name without a
+// namespace: it starts with a letter or underscore, continues with letters,
+// digits, '-', '_' or '.', and does not start with the reserved "xml".
+func isXMLName(key string) bool {
+	if key == "" || strings.HasPrefix(strings.ToLower(key), "xml") {
+		return false
+	}
+	for i, r := range key {
+		switch {
+		case unicode.IsLetter(r) || r == '_':
+		case i > 0 && (unicode.IsDigit(r) || r == '-' || r == '.'):
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// requestID returns the request's X-Request-ID, generating one if needed, and
+// sets it on the response.
+func requestID(c *gin.Context) string {
+	if id := c.GetString(requestIDKey); id != "" {
+		return id
+	}
+	id := c.GetHeader(RequestIDHeader)
+	if id == "" {
+		var raw [8]byte
+		rand.Read(raw[:])
+		id = hex.EncodeToString(raw[:])
+	}
+	c.Set(requestIDKey, id)
+	c.Header(RequestIDHeader, id)
+	return id
+}
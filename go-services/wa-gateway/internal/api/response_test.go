@@ -0,0 +1,115 @@
+package api
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestRespondEnvelope(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/ok", func(c *gin.Context) { Respond(c, http.StatusOK, gin.H{"value": 1}) })
+	router.GET("/fail", func(c *gin.Context) { RespondError(c, http.StatusNotFound, "NOT_FOUND", "missing") })
+	get := func(path, requestID string) (*httptest.ResponseRecorder, APIResponse) {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		if requestID != "" {
+			req.Header.Set(RequestIDHeader, requestID)
+		}
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		var payload APIResponse
+		if err := json.Unmarshal(rec.Body.Bytes(), &payload); err != nil {
+			t.Fatalf("unmarshal envelope: %v", err)
+		}
+		return rec, payload
+	}
+
+	rec, payload := get("/ok", "req-1")
+	data, _ := payload.Data.(map[string]any)
+	if !payload.Success || data["value"] != float64(1) || payload.Error != "" || payload.Timestamp == "" {
+		t.Fatalf("unexpected success envelope: %+v", payload)
+	}
+	if payload.RequestID != "req-1" || rec.Header().Get(RequestIDHeader) != "req-1" {
+		t.Fatalf("expected request ID req-1 echoed, got body=%q header=%q", payload.RequestID, rec.Header().Get(RequestIDHeader))
+	}
+
+	rec, payload = get("/fail", "")
+	if rec.Code != http.StatusNotFound || payload.Success || payload.Code != "NOT_FOUND" || payload.Error != "missing" || payload.Data != nil {
+		t.Fatalf("unexpected error envelope: %d %+v", rec.Code, payload)
+	}
+	if payload.RequestID == "" || payload.RequestID != rec.Header().Get(RequestIDHeader) {
+		t.Fatalf("expected generated request ID, got body=%q header=%q", payload.RequestID, rec.Header().Get(RequestIDHeader))
+	}
+}
+
+func TestContentNegotiationXML(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(ContentNegotiationMiddleware())
+	router.GET("/accounts", func(c *gin.Context) {
+		Respond(c, http.StatusOK, gin.H{
+			"accounts": []AccountInfo{{AccountID: "bot-clientes", PhoneNumberID: "12345", Status: "connected"}},
+			"counts":   map[string]int{"b": 2, "a": 1},
+		})
+	})
+	get := func(accept string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/accounts", nil)
+		req.Header.Set("Accept", accept)
+		req.Header.Set(RequestIDHeader, "req-1")
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := get("application/xml")
+	if !strings.HasPrefix(rec.Header().Get("Content-Type"), "application/xml") {
+		t.Fatalf("expected xml content type, got %q", rec.Header().Get("Content-Type"))
+	}
+	wantData := "<data><accounts><item><account_id>bot-clientes</account_id><phone_number_id>12345</phone_number_id><status>connected</status></item></accounts><counts><a>1</a><b>2</b></counts></data>"
+	body := rec.Body.String()
+	if !strings.HasPrefix(body, "<response><success>true</success>"+wantData) || !strings.Contains(body, "<request_id>req-1</request_id>") {
+		t.Fatalf("unexpected xml body: %s", body)
+	}
+	if rec.Header().Get("Vary") != "Accept" {
+		t.Fatalf("expected Vary: Accept, got %q", rec.Header().Get("Vary"))
+	}
+
+	for _, accept := range []string{"", "*/*", "application/json, application/xml"} {
+		if rec := get(accept); !strings.HasPrefix(rec.Header().Get("Content-Type"), "application/json") {
+			t.Fatalf("expected json for Accept %q, got %q", accept, rec.Header().Get("Content-Type"))
+		}
+	}
+}
+
+func TestXMLTreeWrapsInvalidElementNames(t *testing.T) {
+	tree, err := toXMLTree(map[string]any{
+		"593999111222": 3,
+		"a:b":          "x",
+		"two words":    true,
+		"xmlns":        "y",
+		"valid_key-1.": "ok",
+	})
+	if err != nil {
+		t.Fatalf("expected tree, got %v", err)
+	}
+	raw, err := xml.Marshal(struct {
+		XMLName xml.Name `xml:"data"`
+		Tree    xmlTree  `xml:"tree"`
+	}{Tree: tree})
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	want := `<data><tree><entry key="593999111222">3</entry><entry key="a:b">x</entry><entry key="two words">true</entry><valid_key-1.>ok</valid_key-1.><entry key="xmlns">y</entry></tree></data>`
+	if string(raw) != want {
+		t.Fatalf("expected %s, got %s", want, raw)
+	}
+	if err := xml.Unmarshal(raw, new(struct{})); err != nil {
+		t.Fatalf("expected well-formed xml, got %v", err)
+	}
+}
@@ -0,0 +1,57 @@
+package api
+
+import "github.com/gin-gonic/gin"
+
+// RegisterRoutes mounts the gateway HTTP routes on router.
+func RegisterRoutes(router gin.IRouter, h *Handlers) {
+	// Health check (no auth)
+	router.GET("/health", h.GetHealth)
+	router.GET("/healthz/live", h.GetLive)
+	router.GET("/healthz/ready", h.GetReady)
+	router.GET("/health/deep", h.GetDeepHealth)
+	router.GET("/meta/webhook", h.GetMetaWebhook)
+	router.POST("/meta/webhook", h.PostMetaWebhook)
+
+	// API routes
+	apiGroup := router.Group("/api", ContentNegotiationMiddleware())
+	{
+		apiGroup.POST("/send", IdempotencyMiddleware(h.idempotency), h.PostSend)
+		apiGroup.GET("/accounts", h.GetAccounts)
+		apiGroup.GET("/accounts/:accountId", h.GetAccount)
+		apiGroup.GET("/accounts/:accountId/send-stats", h.GetSendStats)
+	}
+
+	// Sensitive routes: broadcasts, group management, bulk sends, profile
+	// updates, webhook routing and diagnostics, restricted by
+	// ADMIN_IP_ALLOWLIST when it is set
+	admin := adminMiddleware(h.adminIPs)
+	{
+		apiGroup.POST("/broadcast", admin, h.PostBroadcast)
+		apiGroup.GET("/groups", admin, h.GetGroups)
+		apiGroup.POST("/groups", admin, h.PostGroup)
+		apiGroup.DELETE("/groups/:groupId", admin, h.DeleteGroup)
+		apiGroup.POST("/groups/:groupId/members", admin, h.PostGroupMember)
+		apiGroup.DELETE("/groups/:groupId/members/:accountId", admin, h.DeleteGroupMember)
+		apiGroup.POST("/groups/:groupId/send", admin, h.PostGroupSend)
+		apiGroup.POST("/accounts/:accountId/test-webhook", admin, h.PostTestWebhook)
+		apiGroup.GET("/ab-tests", admin, h.GetABTests)
+		apiGroup.PUT("/ab-tests/:accountId", admin, h.PutABTest)
+		apiGroup.DELETE("/ab-tests/:accountId", admin, h.DeleteABTest)
+		apiGroup.GET("/webhook-regions", admin, h.GetWebhookRegions)
+		apiGroup.PUT("/webhook-regions/:accountId/:countryCode", admin, h.PutWebhookRegion)
+		apiGroup.DELETE("/webhook-regions/:accountId/:countryCode", admin, h.DeleteWebhookRegion)
+		apiGroup.POST("/accounts/:accountId/send-bulk-csv", admin, IdempotencyMiddleware(h.idempotency), h.PostSendBulkCSV)
+		apiGroup.GET("/accounts/:accountId/bulk-jobs/:jobId", admin, h.GetBulkJob)
+		apiGroup.PUT("/accounts/:accountId/status-message", admin, h.PutStatusMessage)
+		apiGroup.PUT("/accounts/:accountId/profile-picture", admin, h.PutProfilePicture)
+	}
+
+	// Test routes, only when an API key is configured
+	if h.testAPIKey != "" {
+		testGroup := apiGroup.Group("/test", APIKeyMiddleware(h.testAPIKey))
+		testGroup.POST("/simulate-incoming", h.PostSimulateIncoming)
+	}
+
+	// Also expose routes without /api prefix for compatibility
+	router.POST("/send", ContentNegotiationMiddleware(), IdempotencyMiddleware(h.idempotency), h.PostSend)
+}
@@ -0,0 +1,79 @@
+package api
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxSendUploadBytes caps files accepted by multipart POST /api/send.
+const maxSendUploadBytes = 16 << 20
+
+// sendUpload is a file attached to a multipart send request.
+type sendUpload struct {
+	data     []byte
+	mimeType string
+	filename string
+}
+
+// bindMultipartSend reads a multipart/form-data send request. Fields mirror
+// SendMessageRequest; when a file is attached, message is its optional
+// caption.
+func bindMultipartSend(c *gin.Context) (SendMessageRequest, *sendUpload, error) {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxSendUploadBytes+1<<20)
+	req := SendMessageRequest{
+		AccountID: strings.TrimSpace(c.PostForm("account_id")),
+		To:        strings.TrimSpace(c.PostForm("to")),
+		Message:   c.PostForm("message"),
+		ToList:    c.PostFormArray("to_list"),
+	}
+	if req.AccountID == "" || (req.To == "" && len(req.ToList) == 0) {
+		return req, nil, fmt.Errorf("account_id and to or to_list are required")
+	}
+
+	file, header, err := c.Request.FormFile("file")
+	if err == http.ErrMissingFile {
+		if req.Message == "" {
+			return req, nil, fmt.Errorf("message or file is required")
+		}
+		return req, nil, nil
+	}
+	if err != nil {
+		return req, nil, fmt.Errorf("read file: %w", err)
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(io.LimitReader(file, maxSendUploadBytes+1))
+	if err != nil {
+		return req, nil, fmt.Errorf("read file: %w", err)
+	}
+	if len(data) == 0 {
+		return req, nil, fmt.Errorf("file is empty")
+	}
+	if len(data) > maxSendUploadBytes {
+		return req, nil, fmt.Errorf("file exceeds %d bytes", maxSendUploadBytes)
+	}
+	return req, &sendUpload{
+		data:     data,
+		mimeType: detectUploadMIME(data, header.Header.Get("Content-Type")),
+		filename: header.Filename,
+	}, nil
+}
+
+// detectUploadMIME sniffs data, falling back to the part's declared type
+// when sniffing is inconclusive.
+func detectUploadMIME(data []byte, declared string) string {
+	detected := http.DetectContentType(data)
+	if i := strings.Index(detected, ";"); i >= 0 {
+		detected = detected[:i]
+	}
+	if detected == "application/octet-stream" || detected == "text/plain" {
+		if declared = strings.TrimSpace(declared); declared != "" {
+			return declared
+		}
+	}
+	return detected
+}
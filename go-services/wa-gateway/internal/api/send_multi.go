@@ -0,0 +1,108 @@
+package api
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+)
+
+// sendWorkers bounds concurrent sends within one multi-recipient send.
+const sendWorkers = 8
+
+// SendResult is the outcome for one recipient of a to_list send.
+type SendResult struct {
+	To         string `json:"to"`
+	Success    bool   `json:"success"`
+	MessageID  string `json:"message_id,omitempty"`
+	Code       string `json:"code,omitempty"`
+	Error      string `json:"error,omitempty"`
+	RetryAfter int    `json:"retry_after,omitempty"`
+}
+
+// postSendMulti sends req to every recipient in req.ToList. Unlike
+// PostBroadcast, rate limits apply per recipient: limited recipients are
+// reported in results and the rest are still sent.
+func (h *Handlers) postSendMulti(c *gin.Context, req SendMessageRequest, upload *sendUpload) {
+	recipients := make([]string, 0, len(req.ToList))
+	for _, to := range req.ToList {
+		if to = strings.TrimSpace(to); to != "" {
+			recipients = append(recipients, to)
+		}
+	}
+	if len(recipients) == 0 || len(recipients) > maxBroadcastPairs {
+		RespondError(c, http.StatusBadRequest, "INVALID_REQUEST", fmt.Sprintf("to_list must have between 1 and %d recipients", maxBroadcastPairs))
+		return
+	}
+	log.Printf(
+		"[PostSend] account=%s recipients=%d ui_type=%s metadata=%s",
+		req.AccountID,
+		len(recipients),
+		uiTypeForLog(req.UI),
+		metadataForLog(req.Metadata),
+	)
+
+	ctx := c.Request.Context()
+	results := make([]SendResult, len(recipients))
+	pending := make([]int, 0, len(recipients))
+	for i, to := range recipients {
+		results[i].To = to
+		allowed, retryAfter, decision, err := h.rateLimiter.Check(ctx, req.AccountID, to)
+		if allowed {
+			pending = append(pending, i)
+			continue
+		}
+		recipientReq := req
+		recipientReq.To = to
+		h.recordRateLimitHit(ctx, recipientReq, decision)
+		results[i].Code, results[i].Error = "RATE_LIMIT_EXCEEDED", errorMessage(err)
+		results[i].RetryAfter = int(retryAfter.Seconds())
+	}
+
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < min(sendWorkers, len(pending)); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				result := &results[i]
+				messageID, err := h.sendOne(ctx, req, result.To, upload)
+				if err != nil {
+					_, result.Code = sendErrorStatus(err)
+					result.Error = err.Error()
+					continue
+				}
+				result.Success = true
+				result.MessageID = messageID
+				if err := h.rateLimiter.Increment(ctx, req.AccountID, result.To); err != nil {
+					log.Printf("[PostSend] rate_limit_increment_failed account=%s to=%s err=%v", req.AccountID, result.To, err)
+				}
+			}
+		}()
+	}
+	for _, i := range pending {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	sent := 0
+	for _, result := range results {
+		if result.Success {
+			sent++
+		}
+	}
+	log.Printf("[PostSend] multi_done account=%s recipients=%d sent=%d failed=%d", req.AccountID, len(results), sent, len(results)-sent)
+	writeResponse(c, http.StatusOK, APIResponse{
+		Success: sent == len(results),
+		Data: gin.H{
+			"sent":    sent,
+			"failed":  len(results) - sent,
+			"results": results,
+		},
+	})
+}
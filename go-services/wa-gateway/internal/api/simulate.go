@@ -0,0 +1,59 @@
+package api
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/tinkubot/wa-gateway/internal/webhook"
+)
+
+// WebhookSimulator forwards a payload to the AI service like a real inbound
+// message.
+type WebhookSimulator interface {
+	Send(ctx context.Context, payload *webhook.WebhookPayload) (*webhook.WebhookResponse, error)
+}
+
+// PostSimulateIncoming forwards a caller-supplied WebhookPayload to the AI
+// service of its account, as if it had arrived from WhatsApp, and returns
+// the service's WebhookResponse. Nothing is sent back to WhatsApp, so AI
+// integrations can be tested end to end without a real message.
+func (h *Handlers) PostSimulateIncoming(c *gin.Context) {
+	if h.webhookSimulator == nil {
+		RespondError(c, http.StatusServiceUnavailable, "WEBHOOK_UNAVAILABLE", "no AI service webhook is configured")
+		return
+	}
+	var payload webhook.WebhookPayload
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		RespondError(c, http.StatusBadRequest, "INVALID_REQUEST", err.Error())
+		return
+	}
+	payload.AccountID = strings.TrimSpace(payload.AccountID)
+	payload.Phone = strings.TrimSpace(payload.Phone)
+	if payload.AccountID == "" || payload.Phone == "" {
+		RespondError(c, http.StatusBadRequest, "INVALID_REQUEST", "account_id and phone are required")
+		return
+	}
+	if payload.MessageType == "" {
+		payload.MessageType = "text"
+	}
+	if payload.Content == "" {
+		payload.Content = payload.Message
+	}
+	if payload.Timestamp == "" {
+		payload.Timestamp = time.Now().UTC().Format(time.RFC3339)
+	}
+
+	start := time.Now()
+	resp, err := h.webhookSimulator.Send(c.Request.Context(), &payload)
+	if err != nil {
+		log.Printf("[API] simulate_incoming_failed account=%s phone=%s err=%v", payload.AccountID, payload.Phone, err)
+		RespondError(c, http.StatusBadGateway, "WEBHOOK_TEST_FAILED", err.Error())
+		return
+	}
+	log.Printf("[API] simulate_incoming account=%s phone=%s messages=%d latency_ms=%d", payload.AccountID, payload.Phone, len(resp.Messages), time.Since(start).Milliseconds())
+	Respond(c, http.StatusOK, resp)
+}
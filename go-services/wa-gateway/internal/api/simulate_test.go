@@ -0,0 +1,81 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/tinkubot/wa-gateway/internal/outbound"
+	"github.com/tinkubot/wa-gateway/internal/ratelimit"
+	"github.com/tinkubot/wa-gateway/internal/webhook"
+)
+
+type fakeWebhookSimulator struct {
+	payloads []*webhook.WebhookPayload
+}
+
+func (f *fakeWebhookSimulator) Send(_ context.Context, payload *webhook.WebhookPayload) (*webhook.WebhookResponse, error) {
+	f.payloads = append(f.payloads, payload)
+	return &webhook.WebhookResponse{
+		Success:  true,
+		Messages: []webhook.ResponseMessage{{Response: "Hola " + payload.Message}},
+	}, nil
+}
+
+func TestPostSimulateIncoming(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	simulator := &fakeWebhookSimulator{}
+	newEngine := func(apiKey string) *gin.Engine {
+		handlers := NewHandlers(ratelimit.NewLimiter(ratelimit.Config{MaxPerHour: 20, MaxPer24h: 100}), nil, outbound.NewRouter(nil, outbound.RouterConfig{}), HandlerConfig{
+			WebhookSimulator: simulator,
+			TestAPIKey:       apiKey,
+		})
+		engine := gin.New()
+		RegisterRoutes(engine, handlers)
+		return engine
+	}
+	post := func(engine *gin.Engine, apiKey, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/api/test/simulate-incoming", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		if apiKey != "" {
+			req.Header.Set(APIKeyHeader, apiKey)
+		}
+		rec := httptest.NewRecorder()
+		engine.ServeHTTP(rec, req)
+		return rec
+	}
+	body := `{"account_id":"bot-clientes","phone":"593999111222","message":"mundo"}`
+	engine := newEngine("secret")
+
+	if rec := post(engine, "wrong", body); rec.Code != http.StatusUnauthorized {
+		t.Fatalf("expected 401 with wrong key, got %d", rec.Code)
+	}
+	if rec := post(engine, "secret", `{"message":"mundo"}`); rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 without account_id and phone, got %d", rec.Code)
+	}
+
+	rec := post(engine, "secret", body)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d body=%s", rec.Code, rec.Body.String())
+	}
+	var envelope struct {
+		Data webhook.WebhookResponse `json:"data"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &envelope); err != nil {
+		t.Fatalf("unmarshal response: %v", err)
+	}
+	if len(envelope.Data.Messages) != 1 || envelope.Data.Messages[0].Response != "Hola mundo" {
+		t.Fatalf("expected AI service response, got %+v", envelope.Data)
+	}
+	if len(simulator.payloads) != 1 || simulator.payloads[0].MessageType != "text" || simulator.payloads[0].Timestamp == "" {
+		t.Fatalf("expected payload with defaults, got %+v", simulator.payloads)
+	}
+
+	if rec := post(newEngine(""), "secret", body); rec.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 when no API key is configured, got %d", rec.Code)
+	}
+}
@@ -0,0 +1,81 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/tinkubot/wa-gateway/internal/webhook"
+)
+
+// RegionManager manages the regional routes of the AI service webhook; see
+// webhook.WebhookClient.
+type RegionManager interface {
+	WebhookRegions() []webhook.WebhookRegion
+	SetWebhookRegion(region webhook.WebhookRegion) (webhook.WebhookRegion, error)
+	DeleteWebhookRegion(accountID, country string) error
+}
+
+// WebhookRegionRequest is the body of
+// PUT /api/webhook-regions/:accountId/:countryCode.
+type WebhookRegionRequest struct {
+	WebhookURL string `json:"webhook_url" binding:"required"`
+}
+
+// GetWebhookRegions lists the regional routes.
+func (h *Handlers) GetWebhookRegions(c *gin.Context) {
+	if h.regions == nil {
+		RespondError(c, http.StatusServiceUnavailable, "WEBHOOK_UNAVAILABLE", "no AI service webhook is configured")
+		return
+	}
+	Respond(c, http.StatusOK, gin.H{"regions": h.regions.WebhookRegions()})
+}
+
+// PutWebhookRegion routes an account's senders from a country to a regional
+// AI service instance.
+func (h *Handlers) PutWebhookRegion(c *gin.Context) {
+	if h.regions == nil {
+		RespondError(c, http.StatusServiceUnavailable, "WEBHOOK_UNAVAILABLE", "no AI service webhook is configured")
+		return
+	}
+	var req WebhookRegionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		RespondError(c, http.StatusBadRequest, "INVALID_REQUEST", err.Error())
+		return
+	}
+	region, err := h.regions.SetWebhookRegion(webhook.WebhookRegion{
+		AccountID:   c.Param("accountId"),
+		CountryCode: c.Param("countryCode"),
+		WebhookURL:  req.WebhookURL,
+	})
+	if err != nil {
+		writeRegionError(c, err)
+		return
+	}
+	Respond(c, http.StatusOK, region)
+}
+
+// DeleteWebhookRegion sends an account's senders from a country back to the
+// default AI service URL.
+func (h *Handlers) DeleteWebhookRegion(c *gin.Context) {
+	if h.regions == nil {
+		RespondError(c, http.StatusServiceUnavailable, "WEBHOOK_UNAVAILABLE", "no AI service webhook is configured")
+		return
+	}
+	if err := h.regions.DeleteWebhookRegion(c.Param("accountId"), c.Param("countryCode")); err != nil {
+		writeRegionError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+func writeRegionError(c *gin.Context, err error) {
+	status, code := http.StatusInternalServerError, "INTERNAL_ERROR"
+	switch {
+	case errors.Is(err, webhook.ErrRegionNotFound):
+		status, code = http.StatusNotFound, "REGION_NOT_FOUND"
+	case errors.Is(err, webhook.ErrInvalidRegion):
+		status, code = http.StatusBadRequest, "INVALID_REGION"
+	}
+	RespondError(c, status, code, err.Error())
+}
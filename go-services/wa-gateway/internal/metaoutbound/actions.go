@@ -0,0 +1,128 @@
+package metaoutbound
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+type messageStatusPayload struct {
+	MessagingProduct string           `json:"messaging_product"`
+	Status           string           `json:"status"`
+	MessageID        string           `json:"message_id"`
+	TypingIndicator  *typingIndicator `json:"typing_indicator,omitempty"`
+}
+
+type typingIndicator struct {
+	Type string `json:"type"`
+}
+
+type blockUsersPayload struct {
+	MessagingProduct string      `json:"messaging_product"`
+	BlockUsers       []blockUser `json:"block_users"`
+}
+
+type blockUser struct {
+	User string `json:"user"`
+}
+
+type callActionPayload struct {
+	MessagingProduct string `json:"messaging_product"`
+	CallID           string `json:"call_id"`
+	Action           string `json:"action"`
+}
+
+// MarkRead marks an inbound message as read (blue ticks).
+func (c *Client) MarkRead(ctx context.Context, phoneNumberID, messageID string) error {
+	return c.sendStatus(ctx, phoneNumberID, messageID, false)
+}
+
+// SendTypingIndicator marks an inbound message as read and shows the typing
+// indicator to the user until the next reply or 25 seconds elapse.
+func (c *Client) SendTypingIndicator(ctx context.Context, phoneNumberID, messageID string) error {
+	return c.sendStatus(ctx, phoneNumberID, messageID, true)
+}
+
+// BlockUser adds user to the business phone number's block list.
+func (c *Client) BlockUser(ctx context.Context, phoneNumberID, user string) error {
+	phoneNumberID = strings.TrimSpace(phoneNumberID)
+	user = strings.TrimSpace(user)
+
+	if c == nil {
+		return fmt.Errorf("meta outbound client is nil")
+	}
+	if phoneNumberID == "" {
+		return fmt.Errorf("phone_number_id is empty")
+	}
+	accessToken := c.accessTokenFor(phoneNumberID)
+	if accessToken == "" {
+		return fmt.Errorf("meta outbound access token is empty for phone_number_id=%s", phoneNumberID)
+	}
+	if user == "" {
+		return fmt.Errorf("user is empty")
+	}
+
+	url := fmt.Sprintf("%s/%s/%s/block_users", c.baseURL, c.apiVersion, phoneNumberID)
+	return c.postJSON(ctx, phoneNumberID, url, accessToken, blockUsersPayload{
+		MessagingProduct: "whatsapp",
+		BlockUsers:       []blockUser{{User: user}},
+	})
+}
+
+// RejectCall declines an incoming WhatsApp call.
+func (c *Client) RejectCall(ctx context.Context, phoneNumberID, callID string) error {
+	phoneNumberID = strings.TrimSpace(phoneNumberID)
+	callID = strings.TrimSpace(callID)
+
+	if c == nil {
+		return fmt.Errorf("meta outbound client is nil")
+	}
+	if phoneNumberID == "" {
+		return fmt.Errorf("phone_number_id is empty")
+	}
+	accessToken := c.accessTokenFor(phoneNumberID)
+	if accessToken == "" {
+		return fmt.Errorf("meta outbound access token is empty for phone_number_id=%s", phoneNumberID)
+	}
+	if callID == "" {
+		return fmt.Errorf("call_id is empty")
+	}
+
+	url := fmt.Sprintf("%s/%s/%s/calls", c.baseURL, c.apiVersion, phoneNumberID)
+	return c.postJSON(ctx, phoneNumberID, url, accessToken, callActionPayload{
+		MessagingProduct: "whatsapp",
+		CallID:           callID,
+		Action:           "reject",
+	})
+}
+
+func (c *Client) sendStatus(ctx context.Context, phoneNumberID, messageID string, typing bool) error {
+	phoneNumberID = strings.TrimSpace(phoneNumberID)
+	messageID = strings.TrimSpace(messageID)
+
+	if c == nil {
+		return fmt.Errorf("meta outbound client is nil")
+	}
+	if phoneNumberID == "" {
+		return fmt.Errorf("phone_number_id is empty")
+	}
+	accessToken := c.accessTokenFor(phoneNumberID)
+	if accessToken == "" {
+		return fmt.Errorf("meta outbound access token is empty for phone_number_id=%s", phoneNumberID)
+	}
+	if messageID == "" {
+		return fmt.Errorf("message id is empty")
+	}
+
+	payload := messageStatusPayload{
+		MessagingProduct: "whatsapp",
+		Status:           "read",
+		MessageID:        messageID,
+	}
+	if typing {
+		payload.TypingIndicator = &typingIndicator{Type: "text"}
+	}
+
+	url := fmt.Sprintf("%s/%s/%s/messages", c.baseURL, c.apiVersion, phoneNumberID)
+	return c.postJSON(ctx, phoneNumberID, url, accessToken, payload)
+}
@@ -0,0 +1,161 @@
+package metaoutbound
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// maxProfilePictureBytes is the largest profile picture accepted.
+const maxProfilePictureBytes = 5 << 20
+
+// ErrInvalidProfilePicture is returned for profile pictures that are not a
+// JPEG of at most 5 MB.
+var ErrInvalidProfilePicture = errors.New("profile picture must be a jpeg of at most 5 MB")
+
+type businessProfilePayload struct {
+	MessagingProduct     string `json:"messaging_product"`
+	About                string `json:"about,omitempty"`
+	ProfilePictureHandle string `json:"profile_picture_handle,omitempty"`
+}
+
+type uploadSessionResponse struct {
+	ID string `json:"id"`
+}
+
+type uploadHandleResponse struct {
+	Handle string `json:"h"`
+}
+
+// SetAbout updates the "about" text of the business profile, the status
+// message shown under the name in WhatsApp.
+func (c *Client) SetAbout(ctx context.Context, phoneNumberID, about string) error {
+	phoneNumberID = strings.TrimSpace(phoneNumberID)
+	about = strings.TrimSpace(about)
+
+	if c == nil {
+		return fmt.Errorf("meta outbound client is nil")
+	}
+	if phoneNumberID == "" {
+		return fmt.Errorf("phone_number_id is empty")
+	}
+	accessToken := c.accessTokenFor(phoneNumberID)
+	if accessToken == "" {
+		return fmt.Errorf("meta outbound access token is empty for phone_number_id=%s", phoneNumberID)
+	}
+	if about == "" {
+		return fmt.Errorf("about is empty")
+	}
+	if err := validateAbout(about); err != nil {
+		return err
+	}
+
+	url := fmt.Sprintf("%s/%s/%s/whatsapp_business_profile", c.baseURL, c.apiVersion, phoneNumberID)
+	return c.postJSON(ctx, phoneNumberID, url, accessToken, businessProfilePayload{
+		MessagingProduct: "whatsapp",
+		About:            about,
+	})
+}
+
+func validateAbout(text string) error {
+	if n := utf8.RuneCountInString(text); n > maxAboutLen {
+		return fmt.Errorf("%w: about has %d characters, max %d", ErrMessageTooLong, n, maxAboutLen)
+	}
+	return nil
+}
+
+// SetProfilePicture replaces the business profile picture. The image goes
+// through the Resumable Upload API of the Meta app (Config.AppID) and the
+// returned handle is then set on the profile.
+func (c *Client) SetProfilePicture(ctx context.Context, phoneNumberID string, data []byte) error {
+	phoneNumberID = strings.TrimSpace(phoneNumberID)
+
+	if c == nil {
+		return fmt.Errorf("meta outbound client is nil")
+	}
+	if phoneNumberID == "" {
+		return fmt.Errorf("phone_number_id is empty")
+	}
+	accessToken := c.accessTokenFor(phoneNumberID)
+	if accessToken == "" {
+		return fmt.Errorf("meta outbound access token is empty for phone_number_id=%s", phoneNumberID)
+	}
+	if len(data) == 0 || len(data) > maxProfilePictureBytes || http.DetectContentType(data) != "image/jpeg" {
+		return ErrInvalidProfilePicture
+	}
+	if c.appID == "" {
+		return fmt.Errorf("meta app id is empty, required for profile picture uploads")
+	}
+
+	handle, err := c.uploadResumable(ctx, phoneNumberID, accessToken, data, "image/jpeg")
+	if err != nil {
+		return err
+	}
+	profileURL := fmt.Sprintf("%s/%s/%s/whatsapp_business_profile", c.baseURL, c.apiVersion, phoneNumberID)
+	return c.postJSON(ctx, phoneNumberID, profileURL, accessToken, businessProfilePayload{
+		MessagingProduct:     "whatsapp",
+		ProfilePictureHandle: handle,
+	})
+}
+
+// uploadResumable uploads data in one chunk through the app's Resumable
+// Upload API and returns the file handle. Uploads are not retried.
+func (c *Client) uploadResumable(ctx context.Context, phoneNumberID, accessToken string, data []byte, mimeType string) (string, error) {
+	query := url.Values{}
+	query.Set("file_length", strconv.Itoa(len(data)))
+	query.Set("file_type", mimeType)
+	sessionURL := fmt.Sprintf("%s/%s/%s/uploads?%s", c.baseURL, c.apiVersion, c.appID, query.Encode())
+	var session uploadSessionResponse
+	if err := c.doUpload(ctx, phoneNumberID, sessionURL, "Bearer "+accessToken, nil, nil, &session); err != nil {
+		return "", fmt.Errorf("create upload session: %w", err)
+	}
+	if strings.TrimSpace(session.ID) == "" {
+		return "", fmt.Errorf("create upload session: no session id returned")
+	}
+
+	uploadURL := fmt.Sprintf("%s/%s/%s", c.baseURL, c.apiVersion, strings.TrimSpace(session.ID))
+	var uploaded uploadHandleResponse
+	if err := c.doUpload(ctx, phoneNumberID, uploadURL, "OAuth "+accessToken, map[string]string{"file_offset": "0"}, data, &uploaded); err != nil {
+		return "", fmt.Errorf("upload file: %w", err)
+	}
+	if strings.TrimSpace(uploaded.Handle) == "" {
+		return "", fmt.Errorf("upload file: no handle returned")
+	}
+	return strings.TrimSpace(uploaded.Handle), nil
+}
+
+func (c *Client) doUpload(ctx context.Context, phoneNumberID, endpoint, authorization string, headers map[string]string, body []byte, out any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Authorization", authorization)
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		if isAuthFailure(resp.StatusCode) {
+			c.recordUnreachable(phoneNumberID)
+		}
+		return &SendError{StatusCode: resp.StatusCode, Body: strings.TrimSpace(string(respBody))}
+	}
+	if err := json.Unmarshal(respBody, out); err != nil {
+		return fmt.Errorf("decode response: %w", err)
+	}
+	return nil
+}
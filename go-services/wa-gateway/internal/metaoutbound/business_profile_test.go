@@ -0,0 +1,88 @@
+package metaoutbound
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestSetAboutPostsBusinessProfile(t *testing.T) {
+	var gotPath string
+	var gotPayload businessProfilePayload
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		if err := json.NewDecoder(r.Body).Decode(&gotPayload); err != nil {
+			t.Fatalf("decode body: %v", err)
+		}
+		w.Write([]byte(`{"success":true}`))
+	}))
+	defer srv.Close()
+
+	client := NewClient(Config{BaseURL: srv.URL, APIVersion: "v22.0", AccessToken: "token-123"})
+	if err := client.SetAbout(context.Background(), "1022104724314763", " Atención 24/7 "); err != nil {
+		t.Fatalf("SetAbout returned error: %v", err)
+	}
+	if gotPath != "/v22.0/1022104724314763/whatsapp_business_profile" {
+		t.Fatalf("unexpected path: %s", gotPath)
+	}
+	if gotPayload.MessagingProduct != "whatsapp" || gotPayload.About != "Atención 24/7" {
+		t.Fatalf("unexpected payload: %+v", gotPayload)
+	}
+
+	err := client.SetAbout(context.Background(), "1022104724314763", strings.Repeat("ñ", maxAboutLen+1))
+	if !errors.Is(err, ErrMessageTooLong) {
+		t.Fatalf("expected ErrMessageTooLong, got %v", err)
+	}
+}
+
+func TestSetProfilePictureUploadsThenSetsHandle(t *testing.T) {
+	jpeg := append([]byte("\xff\xd8\xff\xe0\x00\x10JFIF\x00"), make([]byte, 64)...)
+	var gotUpload []byte
+	var gotOffset, gotUploadAuth, gotFileType string
+	var gotPayload businessProfilePayload
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v22.0/app-42/uploads":
+			gotFileType = r.URL.Query().Get("file_type")
+			w.Write([]byte(`{"id":"upload:session-1"}`))
+		case "/v22.0/upload:session-1":
+			gotUploadAuth = r.Header.Get("Authorization")
+			gotOffset = r.Header.Get("file_offset")
+			gotUpload, _ = io.ReadAll(r.Body)
+			w.Write([]byte(`{"h":"handle-1"}`))
+		case "/v22.0/1022104724314763/whatsapp_business_profile":
+			if err := json.NewDecoder(r.Body).Decode(&gotPayload); err != nil {
+				t.Fatalf("decode body: %v", err)
+			}
+			w.Write([]byte(`{"success":true}`))
+		default:
+			t.Fatalf("unexpected path: %s", r.URL.Path)
+		}
+	}))
+	defer srv.Close()
+
+	client := NewClient(Config{BaseURL: srv.URL, APIVersion: "v22.0", AccessToken: "token-123", AppID: "app-42"})
+	if err := client.SetProfilePicture(context.Background(), "1022104724314763", jpeg); err != nil {
+		t.Fatalf("SetProfilePicture returned error: %v", err)
+	}
+	if gotFileType != "image/jpeg" || gotUploadAuth != "OAuth token-123" || gotOffset != "0" || !bytes.Equal(gotUpload, jpeg) {
+		t.Fatalf("unexpected upload: file_type=%q auth=%q offset=%q bytes=%d", gotFileType, gotUploadAuth, gotOffset, len(gotUpload))
+	}
+	if gotPayload.ProfilePictureHandle != "handle-1" || gotPayload.MessagingProduct != "whatsapp" {
+		t.Fatalf("unexpected payload: %+v", gotPayload)
+	}
+
+	png := append([]byte("\x89PNG\r\n\x1a\n"), make([]byte, 32)...)
+	if err := client.SetProfilePicture(context.Background(), "1022104724314763", png); !errors.Is(err, ErrInvalidProfilePicture) {
+		t.Fatalf("expected ErrInvalidProfilePicture for png, got %v", err)
+	}
+	if err := client.SetProfilePicture(context.Background(), "1022104724314763", append(jpeg, make([]byte, maxProfilePictureBytes)...)); !errors.Is(err, ErrInvalidProfilePicture) {
+		t.Fatalf("expected ErrInvalidProfilePicture for large image, got %v", err)
+	}
+}
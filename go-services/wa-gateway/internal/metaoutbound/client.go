@@ -4,13 +4,16 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log"
 	"mime"
 	"net/http"
 	"strings"
+	"sync"
 	"time"
+	"unicode/utf8"
 
 	"github.com/tinkubot/wa-gateway/internal/webhook"
 )
@@ -22,6 +25,9 @@ type Config struct {
 	AccessTokens  map[string]string
 	Timeout       time.Duration
 	RetryAttempts int
+	// AppID is the Meta app that owns the phone numbers. It is only needed
+	// for Resumable Upload API calls such as SetProfilePicture.
+	AppID string
 }
 
 type Client struct {
@@ -31,6 +37,9 @@ type Client struct {
 	accessTokens  map[string]string
 	retryAttempts int
 	httpClient    *http.Client
+	statusMu      sync.Mutex
+	statuses      map[string]*PhoneNumberStatus
+	appID         string
 }
 
 func NewClient(cfg Config) *Client {
@@ -60,6 +69,8 @@ func NewClient(cfg Config) *Client {
 		httpClient: &http.Client{
 			Timeout: timeout,
 		},
+		statuses: make(map[string]*PhoneNumberStatus),
+		appID:    strings.TrimSpace(cfg.AppID),
 	}
 }
 
@@ -98,9 +109,11 @@ type sendMessagePayload struct {
 	Type             string              `json:"type"`
 	Text             *textPayload        `json:"text,omitempty"`
 	Image            *imagePayload       `json:"image,omitempty"`
+	Video            *videoPayload       `json:"video,omitempty"`
 	Contacts         []webhook.Contact   `json:"contacts,omitempty"`
 	Template         *templatePayload    `json:"template,omitempty"`
 	Interactive      *interactivePayload `json:"interactive,omitempty"`
+	Document         *documentPayload    `json:"document,omitempty"`
 }
 
 type textPayload struct {
@@ -109,6 +122,12 @@ type textPayload struct {
 }
 
 type imagePayload struct {
+	ID      string `json:"id,omitempty"`
+	Link    string `json:"link,omitempty"`
+	Caption string `json:"caption,omitempty"`
+}
+
+type videoPayload struct {
 	Link    string `json:"link"`
 	Caption string `json:"caption,omitempty"`
 }
@@ -141,6 +160,48 @@ type interactiveFooter struct {
 
 const maxInteractiveFooterLen = 60
 
+// Cloud API limits, counted in characters. Meta rejects longer bodies with a
+// generic (#100) parameter error, so they are checked before sending.
+const (
+	maxTextBodyLen = 4096
+	maxCaptionLen  = 1024
+	maxAboutLen    = 139
+)
+
+// SendError is returned when the Graph API answers a send with a non-2xx
+// status.
+type SendError struct {
+	StatusCode int
+	Body       string
+}
+
+func (e *SendError) Error() string {
+	return fmt.Sprintf("meta send status=%d body=%s", e.StatusCode, e.Body)
+}
+
+// IsAuthFailure reports whether the access token was rejected.
+func (e *SendError) IsAuthFailure() bool {
+	return isAuthFailure(e.StatusCode)
+}
+
+// ErrMessageTooLong is returned when a text body or caption exceeds the
+// WhatsApp length limit.
+var ErrMessageTooLong = errors.New("message exceeds whatsapp length limit")
+
+func validateTextMessage(text string) error {
+	if n := utf8.RuneCountInString(text); n > maxTextBodyLen {
+		return fmt.Errorf("%w: text body has %d characters, max %d", ErrMessageTooLong, n, maxTextBodyLen)
+	}
+	return nil
+}
+
+func validateCaption(text string) error {
+	if n := utf8.RuneCountInString(text); n > maxCaptionLen {
+		return fmt.Errorf("%w: caption has %d characters, max %d", ErrMessageTooLong, n, maxCaptionLen)
+	}
+	return nil
+}
+
 func normalizeFooterText(raw string) string {
 	trimmed := strings.TrimSpace(raw)
 	if trimmed == "" {
@@ -209,6 +270,13 @@ type templateComponent struct {
 	Parameters []map[string]any `json:"parameters,omitempty"`
 }
 
+// sendMessageResponse is the Graph API reply to a sent message.
+type sendMessageResponse struct {
+	Messages []struct {
+		ID string `json:"id"`
+	} `json:"messages"`
+}
+
 type mediaMetadataResponse struct {
 	URL      string `json:"url"`
 	MimeType string `json:"mime_type,omitempty"`
@@ -216,25 +284,39 @@ type mediaMetadataResponse struct {
 }
 
 // SendText sends a plain text WhatsApp message using Meta Cloud API.
-func (c *Client) SendText(ctx context.Context, phoneNumberID, to, body string) error {
+func (c *Client) SendText(ctx context.Context, phoneNumberID, to, body string) (string, error) {
+	return c.sendText(ctx, phoneNumberID, to, body, false)
+}
+
+// SendLinkPreview sends a text message with preview_url enabled so WhatsApp
+// renders a rich preview for the first URL in body. Meta builds the title,
+// description and thumbnail from the page's Open Graph tags.
+func (c *Client) SendLinkPreview(ctx context.Context, phoneNumberID, to, body string) (string, error) {
+	return c.sendText(ctx, phoneNumberID, to, body, true)
+}
+
+func (c *Client) sendText(ctx context.Context, phoneNumberID, to, body string, previewURL bool) (string, error) {
 	phoneNumberID = strings.TrimSpace(phoneNumberID)
 	to = strings.TrimSpace(to)
 	body = strings.TrimSpace(body)
 
 	if c == nil {
-		return fmt.Errorf("meta outbound client is nil")
+		return "", fmt.Errorf("meta outbound client is nil")
 	}
 	if phoneNumberID == "" {
-		return fmt.Errorf("phone_number_id is empty")
+		return "", fmt.Errorf("phone_number_id is empty")
 	}
 	if c.accessTokenFor(phoneNumberID) == "" {
-		return fmt.Errorf("meta outbound access token is empty for phone_number_id=%s", phoneNumberID)
+		return "", fmt.Errorf("meta outbound access token is empty for phone_number_id=%s", phoneNumberID)
 	}
 	if to == "" {
-		return fmt.Errorf("destination number is empty")
+		return "", fmt.Errorf("destination number is empty")
 	}
 	if body == "" {
-		return fmt.Errorf("message body is empty")
+		return "", fmt.Errorf("message body is empty")
+	}
+	if err := validateTextMessage(body); err != nil {
+		return "", err
 	}
 
 	payload := sendMessagePayload{
@@ -243,7 +325,7 @@ func (c *Client) SendText(ctx context.Context, phoneNumberID, to, body string) e
 		To:               to,
 		Type:             "text",
 		Text: &textPayload{
-			PreviewURL: false,
+			PreviewURL: previewURL,
 			Body:       body,
 		},
 	}
@@ -252,26 +334,29 @@ func (c *Client) SendText(ctx context.Context, phoneNumberID, to, body string) e
 }
 
 // SendImage sends an image message using Meta Cloud API.
-func (c *Client) SendImage(ctx context.Context, phoneNumberID, to, imageURL, caption string) error {
+func (c *Client) SendImage(ctx context.Context, phoneNumberID, to, imageURL, caption string) (string, error) {
 	phoneNumberID = strings.TrimSpace(phoneNumberID)
 	to = strings.TrimSpace(to)
 	imageURL = strings.TrimSpace(imageURL)
 	caption = strings.TrimSpace(caption)
 
 	if c == nil {
-		return fmt.Errorf("meta outbound client is nil")
+		return "", fmt.Errorf("meta outbound client is nil")
 	}
 	if phoneNumberID == "" {
-		return fmt.Errorf("phone_number_id is empty")
+		return "", fmt.Errorf("phone_number_id is empty")
 	}
 	if c.accessTokenFor(phoneNumberID) == "" {
-		return fmt.Errorf("meta outbound access token is empty for phone_number_id=%s", phoneNumberID)
+		return "", fmt.Errorf("meta outbound access token is empty for phone_number_id=%s", phoneNumberID)
 	}
 	if to == "" {
-		return fmt.Errorf("destination number is empty")
+		return "", fmt.Errorf("destination number is empty")
 	}
 	if imageURL == "" {
-		return fmt.Errorf("image url is empty")
+		return "", fmt.Errorf("image url is empty")
+	}
+	if err := validateCaption(caption); err != nil {
+		return "", err
 	}
 
 	payload := sendMessagePayload{
@@ -288,33 +373,115 @@ func (c *Client) SendImage(ctx context.Context, phoneNumberID, to, imageURL, cap
 	return c.sendMessage(ctx, phoneNumberID, c.accessTokenFor(phoneNumberID), payload)
 }
 
+// SendVideo sends an MP4 video by public URL. Cloud API has no GIF playback
+// flag, so animated content must be provided as a short MP4.
+func (c *Client) SendVideo(ctx context.Context, phoneNumberID, to, videoURL, caption string) (string, error) {
+	phoneNumberID = strings.TrimSpace(phoneNumberID)
+	to = strings.TrimSpace(to)
+	videoURL = strings.TrimSpace(videoURL)
+	caption = strings.TrimSpace(caption)
+
+	if c == nil {
+		return "", fmt.Errorf("meta outbound client is nil")
+	}
+	if phoneNumberID == "" {
+		return "", fmt.Errorf("phone_number_id is empty")
+	}
+	if c.accessTokenFor(phoneNumberID) == "" {
+		return "", fmt.Errorf("meta outbound access token is empty for phone_number_id=%s", phoneNumberID)
+	}
+	if to == "" {
+		return "", fmt.Errorf("destination number is empty")
+	}
+	if videoURL == "" {
+		return "", fmt.Errorf("video url is empty")
+	}
+	if err := validateCaption(caption); err != nil {
+		return "", err
+	}
+
+	payload := sendMessagePayload{
+		MessagingProduct: "whatsapp",
+		RecipientType:    "individual",
+		To:               to,
+		Type:             "video",
+		Video: &videoPayload{
+			Link:    videoURL,
+			Caption: caption,
+		},
+	}
+
+	return c.sendMessage(ctx, phoneNumberID, c.accessTokenFor(phoneNumberID), payload)
+}
+
+// SendDocument sends a file by public URL as a document, shown with filename.
+func (c *Client) SendDocument(ctx context.Context, phoneNumberID, to, documentURL, filename, caption string) (string, error) {
+	phoneNumberID = strings.TrimSpace(phoneNumberID)
+	to = strings.TrimSpace(to)
+	documentURL = strings.TrimSpace(documentURL)
+	caption = strings.TrimSpace(caption)
+
+	if c == nil {
+		return "", fmt.Errorf("meta outbound client is nil")
+	}
+	if phoneNumberID == "" {
+		return "", fmt.Errorf("phone_number_id is empty")
+	}
+	if c.accessTokenFor(phoneNumberID) == "" {
+		return "", fmt.Errorf("meta outbound access token is empty for phone_number_id=%s", phoneNumberID)
+	}
+	if to == "" {
+		return "", fmt.Errorf("destination number is empty")
+	}
+	if documentURL == "" {
+		return "", fmt.Errorf("document url is empty")
+	}
+	if err := validateCaption(caption); err != nil {
+		return "", err
+	}
+
+	payload := sendMessagePayload{
+		MessagingProduct: "whatsapp",
+		RecipientType:    "individual",
+		To:               to,
+		Type:             "document",
+		Document: &documentPayload{
+			Link:     documentURL,
+			Caption:  caption,
+			Filename: strings.TrimSpace(filename),
+		},
+	}
+
+	return c.sendMessage(ctx, phoneNumberID, c.accessTokenFor(phoneNumberID), payload)
+}
+
 // SendButtons sends an interactive button message using Meta Cloud API.
 func (c *Client) SendButtons(
 	ctx context.Context,
 	phoneNumberID, to, body string,
 	ui webhook.UIConfig,
-) error {
+) (string, error) {
 	phoneNumberID = strings.TrimSpace(phoneNumberID)
 	to = strings.TrimSpace(to)
 	body = strings.TrimSpace(body)
 
 	if c == nil {
-		return fmt.Errorf("meta outbound client is nil")
+		return "", fmt.Errorf("meta outbound client is nil")
 	}
 	if phoneNumberID == "" {
-		return fmt.Errorf("phone_number_id is empty")
+		return "", fmt.Errorf("phone_number_id is empty")
 	}
 	if c.accessTokenFor(phoneNumberID) == "" {
-		return fmt.Errorf("meta outbound access token is empty for phone_number_id=%s", phoneNumberID)
+		return "", fmt.Errorf("meta outbound access token is empty for phone_number_id=%s", phoneNumberID)
 	}
 	if to == "" {
-		return fmt.Errorf("destination number is empty")
+		return "", fmt.Errorf("destination number is empty")
 	}
 	if body == "" {
-		return fmt.Errorf("message body is empty")
+		return "", fmt.Errorf("message body is empty")
 	}
 	if len(ui.Options) == 0 {
-		return fmt.Errorf("buttons options are empty")
+		return "", fmt.Errorf("buttons options are empty")
 	}
 
 	buttons := make([]interactiveButton, 0, 3)
@@ -336,7 +503,7 @@ func (c *Client) SendButtons(
 		}
 	}
 	if len(buttons) == 0 {
-		return fmt.Errorf("buttons options are invalid")
+		return "", fmt.Errorf("buttons options are invalid")
 	}
 
 	var header *interactiveHeader
@@ -346,7 +513,7 @@ func (c *Client) SendButtons(
 	case "image":
 		link := strings.TrimSpace(ui.HeaderMediaURL)
 		if link == "" {
-			return fmt.Errorf("buttons image header configured without header_media_url")
+			return "", fmt.Errorf("buttons image header configured without header_media_url")
 		}
 		header = &interactiveHeader{
 			Type:  "image",
@@ -355,14 +522,14 @@ func (c *Client) SendButtons(
 	case "text":
 		text := strings.TrimSpace(ui.HeaderText)
 		if text == "" {
-			return fmt.Errorf("buttons text header configured without header_text")
+			return "", fmt.Errorf("buttons text header configured without header_text")
 		}
 		header = &interactiveHeader{
 			Type: "text",
 			Text: text,
 		}
 	default:
-		return fmt.Errorf("unsupported buttons header_type: %s", headerType)
+		return "", fmt.Errorf("unsupported buttons header_type: %s", headerType)
 	}
 
 	var footer *interactiveFooter
@@ -398,28 +565,28 @@ func (c *Client) SendList(
 	ctx context.Context,
 	phoneNumberID, to, body string,
 	ui webhook.UIConfig,
-) error {
+) (string, error) {
 	phoneNumberID = strings.TrimSpace(phoneNumberID)
 	to = strings.TrimSpace(to)
 	body = strings.TrimSpace(body)
 
 	if c == nil {
-		return fmt.Errorf("meta outbound client is nil")
+		return "", fmt.Errorf("meta outbound client is nil")
 	}
 	if phoneNumberID == "" {
-		return fmt.Errorf("phone_number_id is empty")
+		return "", fmt.Errorf("phone_number_id is empty")
 	}
 	if c.accessTokenFor(phoneNumberID) == "" {
-		return fmt.Errorf("meta outbound access token is empty for phone_number_id=%s", phoneNumberID)
+		return "", fmt.Errorf("meta outbound access token is empty for phone_number_id=%s", phoneNumberID)
 	}
 	if to == "" {
-		return fmt.Errorf("destination number is empty")
+		return "", fmt.Errorf("destination number is empty")
 	}
 	if body == "" {
-		return fmt.Errorf("message body is empty")
+		return "", fmt.Errorf("message body is empty")
 	}
 	if len(ui.Options) == 0 {
-		return fmt.Errorf("list options are empty")
+		return "", fmt.Errorf("list options are empty")
 	}
 
 	rows := make([]interactiveRow, 0, 10)
@@ -440,7 +607,7 @@ func (c *Client) SendList(
 		}
 	}
 	if len(rows) == 0 {
-		return fmt.Errorf("list options are invalid")
+		return "", fmt.Errorf("list options are invalid")
 	}
 
 	buttonText := strings.TrimSpace(ui.ListButtonText)
@@ -471,7 +638,7 @@ func (c *Client) SendList(
 	if headerType == "text" {
 		headerText := strings.TrimSpace(ui.HeaderText)
 		if headerText == "" {
-			return fmt.Errorf("list text header configured without header_text")
+			return "", fmt.Errorf("list text header configured without header_text")
 		}
 		payload.Interactive.Header = &interactiveHeader{
 			Type: "text",
@@ -492,24 +659,24 @@ func (c *Client) SendContacts(
 	ctx context.Context,
 	phoneNumberID, to string,
 	contacts []webhook.Contact,
-) error {
+) (string, error) {
 	phoneNumberID = strings.TrimSpace(phoneNumberID)
 	to = strings.TrimSpace(to)
 
 	if c == nil {
-		return fmt.Errorf("meta outbound client is nil")
+		return "", fmt.Errorf("meta outbound client is nil")
 	}
 	if phoneNumberID == "" {
-		return fmt.Errorf("phone_number_id is empty")
+		return "", fmt.Errorf("phone_number_id is empty")
 	}
 	if c.accessTokenFor(phoneNumberID) == "" {
-		return fmt.Errorf("meta outbound access token is empty for phone_number_id=%s", phoneNumberID)
+		return "", fmt.Errorf("meta outbound access token is empty for phone_number_id=%s", phoneNumberID)
 	}
 	if to == "" {
-		return fmt.Errorf("destination number is empty")
+		return "", fmt.Errorf("destination number is empty")
 	}
 	if len(contacts) == 0 {
-		return fmt.Errorf("contacts payload is empty")
+		return "", fmt.Errorf("contacts payload is empty")
 	}
 
 	payload := sendMessagePayload{
@@ -524,25 +691,25 @@ func (c *Client) SendContacts(
 }
 
 // SendLocationRequest sends a location request interactive message.
-func (c *Client) SendLocationRequest(ctx context.Context, phoneNumberID, to, body string) error {
+func (c *Client) SendLocationRequest(ctx context.Context, phoneNumberID, to, body string) (string, error) {
 	phoneNumberID = strings.TrimSpace(phoneNumberID)
 	to = strings.TrimSpace(to)
 	body = strings.TrimSpace(body)
 
 	if c == nil {
-		return fmt.Errorf("meta outbound client is nil")
+		return "", fmt.Errorf("meta outbound client is nil")
 	}
 	if phoneNumberID == "" {
-		return fmt.Errorf("phone_number_id is empty")
+		return "", fmt.Errorf("phone_number_id is empty")
 	}
 	if c.accessTokenFor(phoneNumberID) == "" {
-		return fmt.Errorf("meta outbound access token is empty for phone_number_id=%s", phoneNumberID)
+		return "", fmt.Errorf("meta outbound access token is empty for phone_number_id=%s", phoneNumberID)
 	}
 	if to == "" {
-		return fmt.Errorf("destination number is empty")
+		return "", fmt.Errorf("destination number is empty")
 	}
 	if body == "" {
-		return fmt.Errorf("message body is empty")
+		return "", fmt.Errorf("message body is empty")
 	}
 
 	payload := sendMessagePayload{
@@ -567,22 +734,22 @@ func (c *Client) SendFlow(
 	ctx context.Context,
 	phoneNumberID, to, body string,
 	ui webhook.UIConfig,
-) error {
+) (string, error) {
 	phoneNumberID = strings.TrimSpace(phoneNumberID)
 	to = strings.TrimSpace(to)
 	body = strings.TrimSpace(body)
 
 	if c == nil {
-		return fmt.Errorf("meta outbound client is nil")
+		return "", fmt.Errorf("meta outbound client is nil")
 	}
 	if phoneNumberID == "" {
-		return fmt.Errorf("phone_number_id is empty")
+		return "", fmt.Errorf("phone_number_id is empty")
 	}
 	if c.accessTokenFor(phoneNumberID) == "" {
-		return fmt.Errorf("meta outbound access token is empty for phone_number_id=%s", phoneNumberID)
+		return "", fmt.Errorf("meta outbound access token is empty for phone_number_id=%s", phoneNumberID)
 	}
 	if to == "" {
-		return fmt.Errorf("destination number is empty")
+		return "", fmt.Errorf("destination number is empty")
 	}
 	if body == "" {
 		body = "Completa el formulario para continuar."
@@ -593,7 +760,7 @@ func (c *Client) SendFlow(
 		flowID = strings.TrimSpace(ui.ID)
 	}
 	if flowID == "" {
-		return fmt.Errorf("flow_id is empty")
+		return "", fmt.Errorf("flow_id is empty")
 	}
 
 	flowCTA := strings.TrimSpace(ui.FlowCTA)
@@ -644,21 +811,21 @@ func (c *Client) SendTemplate(
 	ctx context.Context,
 	phoneNumberID, to string,
 	ui webhook.UIConfig,
-) error {
+) (string, error) {
 	phoneNumberID = strings.TrimSpace(phoneNumberID)
 	to = strings.TrimSpace(to)
 
 	if c == nil {
-		return fmt.Errorf("meta outbound client is nil")
+		return "", fmt.Errorf("meta outbound client is nil")
 	}
 	if phoneNumberID == "" {
-		return fmt.Errorf("phone_number_id is empty")
+		return "", fmt.Errorf("phone_number_id is empty")
 	}
 	if c.accessTokenFor(phoneNumberID) == "" {
-		return fmt.Errorf("meta outbound access token is empty for phone_number_id=%s", phoneNumberID)
+		return "", fmt.Errorf("meta outbound access token is empty for phone_number_id=%s", phoneNumberID)
 	}
 	if to == "" {
-		return fmt.Errorf("destination number is empty")
+		return "", fmt.Errorf("destination number is empty")
 	}
 
 	templateName := strings.TrimSpace(ui.TemplateName)
@@ -666,7 +833,7 @@ func (c *Client) SendTemplate(
 		templateName = strings.TrimSpace(ui.ID)
 	}
 	if templateName == "" {
-		return fmt.Errorf("template_name is empty")
+		return "", fmt.Errorf("template_name is empty")
 	}
 
 	templateLanguageCode := strings.TrimSpace(ui.TemplateLanguage)
@@ -850,7 +1017,7 @@ func (c *Client) sendMessage(
 	phoneNumberID string,
 	accessToken string,
 	payload sendMessagePayload,
-) error {
+) (string, error) {
 	interactiveType := ""
 	if payload.Interactive != nil {
 		interactiveType = payload.Interactive.Type
@@ -863,9 +1030,33 @@ func (c *Client) sendMessage(
 	)
 
 	url := fmt.Sprintf("%s/%s/%s/messages", c.baseURL, c.apiVersion, phoneNumberID)
+	respBody, err := c.post(ctx, phoneNumberID, url, accessToken, payload)
+	if err != nil {
+		return "", err
+	}
+	var sent sendMessageResponse
+	if err := json.Unmarshal(respBody, &sent); err != nil || len(sent.Messages) == 0 {
+		// Meta accepted the message; a missing id is not worth a resend.
+		log.Printf("[MetaOutbound] send_response_without_id to=%s body=%s", payload.To, strings.TrimSpace(string(respBody)))
+		return "", nil
+	}
+	return sent.Messages[0].ID, nil
+}
+
+// postJSON posts payload to a Graph API url; see post.
+func (c *Client) postJSON(ctx context.Context, phoneNumberID, url, accessToken string, payload any) error {
+	_, err := c.post(ctx, phoneNumberID, url, accessToken, payload)
+	return err
+}
+
+// post posts payload to a Graph API url and returns the response body,
+// retrying transport errors and 5xx responses. 4xx responses are returned
+// immediately. The outcome is recorded against phoneNumberID for
+// PhoneNumberStatus.
+func (c *Client) post(ctx context.Context, phoneNumberID, url, accessToken string, payload any) ([]byte, error) {
 	rawPayload, err := json.Marshal(payload)
 	if err != nil {
-		return fmt.Errorf("marshal outbound payload: %w", err)
+		return nil, fmt.Errorf("marshal outbound payload: %w", err)
 	}
 
 	var lastErr error
@@ -875,7 +1066,7 @@ func (c *Client) sendMessage(
 			select {
 			case <-time.After(backoff):
 			case <-ctx.Done():
-				return fmt.Errorf("meta outbound canceled while retrying: %w", ctx.Err())
+				return nil, fmt.Errorf("meta outbound canceled while retrying: %w", ctx.Err())
 			}
 		}
 
@@ -897,17 +1088,27 @@ func (c *Client) sendMessage(
 		resp.Body.Close()
 
 		if resp.StatusCode >= 200 && resp.StatusCode < 300 {
-			return nil
+			c.recordReachable(phoneNumberID)
+			return respBody, nil
 		}
 
-		lastErr = fmt.Errorf("meta send status=%d body=%s", resp.StatusCode, strings.TrimSpace(string(respBody)))
+		lastErr = &SendError{StatusCode: resp.StatusCode, Body: strings.TrimSpace(string(respBody))}
 		if resp.StatusCode >= 400 && resp.StatusCode < 500 {
-			break
+			if isAuthFailure(resp.StatusCode) {
+				c.recordUnreachable(phoneNumberID)
+			} else {
+				// The Graph API answered; the request itself was rejected.
+				c.recordReachable(phoneNumberID)
+			}
+			return nil, lastErr
 		}
 	}
 
 	if lastErr == nil {
 		lastErr = fmt.Errorf("meta outbound request failed")
 	}
-	return lastErr
+	if ctx.Err() == nil {
+		c.recordUnreachable(phoneNumberID)
+	}
+	return nil, lastErr
 }
@@ -3,6 +3,7 @@ package metaoutbound
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"io"
 	"net/http"
 	"net/http/httptest"
@@ -24,6 +25,7 @@ func TestSendTextSuccess(t *testing.T) {
 			t.Fatalf("decode body: %v", err)
 		}
 		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`{"messaging_product":"whatsapp","contacts":[{"input":"593998823053","wa_id":"593998823053"}],"messages":[{"id":"wamid.HBgMNTkzOTk4ODIzMDUz"}]}`))
 	}))
 	defer srv.Close()
 
@@ -33,10 +35,13 @@ func TestSendTextSuccess(t *testing.T) {
 		AccessToken: "token-123",
 	})
 
-	err := client.SendText(context.Background(), "1022104724314763", "593998823053", "hola")
+	messageID, err := client.SendText(context.Background(), "1022104724314763", "593998823053", "hola")
 	if err != nil {
 		t.Fatalf("SendText returned error: %v", err)
 	}
+	if messageID != "wamid.HBgMNTkzOTk4ODIzMDUz" {
+		t.Fatalf("expected Graph API message id, got %q", messageID)
+	}
 
 	if gotPath != "/v22.0/1022104724314763/messages" {
 		t.Fatalf("unexpected path: %s", gotPath)
@@ -66,7 +71,7 @@ func TestSendTextUsesPhoneSpecificToken(t *testing.T) {
 		},
 	})
 
-	err := client.SendText(context.Background(), "1014676405063497", "593998823053", "hola")
+	_, err := client.SendText(context.Background(), "1014676405063497", "593998823053", "hola")
 	if err != nil {
 		t.Fatalf("SendText returned error: %v", err)
 	}
@@ -82,7 +87,7 @@ func TestSendTextFailsWithoutTokenForPhoneNumber(t *testing.T) {
 		AccessTokens: map[string]string{},
 	})
 
-	err := client.SendText(context.Background(), "1014676405063497", "593998823053", "hola")
+	_, err := client.SendText(context.Background(), "1014676405063497", "593998823053", "hola")
 	if err == nil {
 		t.Fatalf("expected error, got nil")
 	}
@@ -107,7 +112,7 @@ func TestSendButtonsSuccess(t *testing.T) {
 		AccessToken: "token-123",
 	})
 
-	err := client.SendButtons(
+	_, err := client.SendButtons(
 		context.Background(),
 		"1022104724314763",
 		"593998823053",
@@ -160,7 +165,7 @@ func TestSendListSuccess(t *testing.T) {
 		AccessToken: "token-123",
 	})
 
-	err := client.SendList(
+	_, err := client.SendList(
 		context.Background(),
 		"1022104724314763",
 		"593998823053",
@@ -224,7 +229,7 @@ func TestSendContactsSuccess(t *testing.T) {
 		AccessToken: "token-123",
 	})
 
-	err := client.SendContacts(
+	_, err := client.SendContacts(
 		context.Background(),
 		"1022104724314763",
 		"593998823053",
@@ -279,7 +284,7 @@ func TestSendButtonsFooterTooLongIsTruncated(t *testing.T) {
 	})
 
 	longFooter := "Al continuar aceptas el tratamiento de datos según nuestra política de privacidad vigente."
-	err := client.SendButtons(
+	_, err := client.SendButtons(
 		context.Background(),
 		"1022104724314763",
 		"593998823053",
@@ -320,7 +325,7 @@ func TestSendButtonsEmptyFooterIsOmitted(t *testing.T) {
 		AccessToken: "token-123",
 	})
 
-	err := client.SendButtons(
+	_, err := client.SendButtons(
 		context.Background(),
 		"1022104724314763",
 		"593998823053",
@@ -361,7 +366,7 @@ func TestSendImageSuccess(t *testing.T) {
 		AccessToken: "token-123",
 	})
 
-	err := client.SendImage(
+	_, err := client.SendImage(
 		context.Background(),
 		"1022104724314763",
 		"593998823053",
@@ -399,7 +404,7 @@ func TestSendLocationRequestSuccess(t *testing.T) {
 		AccessToken: "token-123",
 	})
 
-	err := client.SendLocationRequest(
+	_, err := client.SendLocationRequest(
 		context.Background(),
 		"1022104724314763",
 		"593998823053",
@@ -436,7 +441,7 @@ func TestSendFlowSuccess(t *testing.T) {
 		AccessToken: "token-123",
 	})
 
-	err := client.SendFlow(
+	_, err := client.SendFlow(
 		context.Background(),
 		"1022104724314763",
 		"593998823053",
@@ -489,7 +494,7 @@ func TestSendTemplateSuccess(t *testing.T) {
 		AccessToken: "token-123",
 	})
 
-	err := client.SendTemplate(
+	_, err := client.SendTemplate(
 		context.Background(),
 		"1022104724314763",
 		"593998823053",
@@ -564,7 +569,7 @@ func TestSendTextRetriesServerErrors(t *testing.T) {
 		RetryAttempts: 2,
 	})
 
-	err := client.SendText(context.Background(), "1022104724314763", "593998823053", "hola")
+	_, err := client.SendText(context.Background(), "1022104724314763", "593998823053", "hola")
 	if err == nil {
 		t.Fatal("expected error for repeated 5xx")
 	}
@@ -589,7 +594,7 @@ func TestSendTextStopsOnClientErrors(t *testing.T) {
 		RetryAttempts: 3,
 	})
 
-	err := client.SendText(context.Background(), "1022104724314763", "593998823053", "hola")
+	_, err := client.SendText(context.Background(), "1022104724314763", "593998823053", "hola")
 	if err == nil {
 		t.Fatal("expected error for 4xx")
 	}
@@ -704,3 +709,230 @@ func TestDownloadMediaPropagatesMetadataErrors(t *testing.T) {
 		t.Fatalf("unexpected error: %v", err)
 	}
 }
+
+func TestSendTypingIndicatorMarksReadWithIndicator(t *testing.T) {
+	var gotPath string
+	var gotPayload map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		if err := json.NewDecoder(r.Body).Decode(&gotPayload); err != nil {
+			t.Fatalf("decode body: %v", err)
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	client := NewClient(Config{BaseURL: srv.URL, APIVersion: "v22.0", AccessToken: "token-123"})
+	if err := client.SendTypingIndicator(context.Background(), "1022104724314763", "wamid.1"); err != nil {
+		t.Fatalf("SendTypingIndicator returned error: %v", err)
+	}
+	if gotPath != "/v22.0/1022104724314763/messages" {
+		t.Fatalf("unexpected path: %s", gotPath)
+	}
+	if gotPayload["status"] != "read" || gotPayload["message_id"] != "wamid.1" {
+		t.Fatalf("unexpected payload: %+v", gotPayload)
+	}
+	indicator, _ := gotPayload["typing_indicator"].(map[string]any)
+	if indicator["type"] != "text" {
+		t.Fatalf("expected typing_indicator.type text, got %+v", gotPayload["typing_indicator"])
+	}
+}
+
+func TestBlockUserPostsToBlockUsersEndpoint(t *testing.T) {
+	var gotPath string
+	var gotPayload blockUsersPayload
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		if err := json.NewDecoder(r.Body).Decode(&gotPayload); err != nil {
+			t.Fatalf("decode body: %v", err)
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	client := NewClient(Config{BaseURL: srv.URL, APIVersion: "v22.0", AccessToken: "token-123"})
+	if err := client.BlockUser(context.Background(), "1022104724314763", "593998823053"); err != nil {
+		t.Fatalf("BlockUser returned error: %v", err)
+	}
+	if gotPath != "/v22.0/1022104724314763/block_users" {
+		t.Fatalf("unexpected path: %s", gotPath)
+	}
+	if len(gotPayload.BlockUsers) != 1 || gotPayload.BlockUsers[0].User != "593998823053" {
+		t.Fatalf("unexpected payload: %+v", gotPayload)
+	}
+}
+
+func TestPhoneNumberStatusTracksAuthFailures(t *testing.T) {
+	statusCode := http.StatusUnauthorized
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(statusCode)
+	}))
+	defer srv.Close()
+
+	client := NewClient(Config{
+		BaseURL:     srv.URL,
+		APIVersion:  "v22.0",
+		AccessToken: "token-123",
+	})
+
+	if got := client.PhoneNumberStatus("1022104724314763").Status; got != PhoneNumberUnknown {
+		t.Fatalf("expected unknown status before any send, got %s", got)
+	}
+
+	_, _ = client.SendText(context.Background(), "1022104724314763", "593998823053", "hola")
+	failing := client.PhoneNumberStatus("1022104724314763")
+	if failing.Status != PhoneNumberFailing || failing.FailingSince.IsZero() {
+		t.Fatalf("expected failing status after 401, got %+v", failing)
+	}
+
+	statusCode = http.StatusBadRequest
+	_, _ = client.SendText(context.Background(), "1022104724314763", "593998823053", "hola")
+	connected := client.PhoneNumberStatus("1022104724314763")
+	if connected.Status != PhoneNumberConnected || connected.LastSeenAt.IsZero() {
+		t.Fatalf("expected connected status after Graph API answered, got %+v", connected)
+	}
+}
+
+func TestCheckPhoneNumberFetchesPhoneNumberNode(t *testing.T) {
+	var gotPath, gotQuery, gotAuth string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		gotQuery = r.URL.RawQuery
+		gotAuth = r.Header.Get("Authorization")
+		_, _ = w.Write([]byte(`{"id":"1022104724314763"}`))
+	}))
+	defer srv.Close()
+
+	client := NewClient(Config{
+		BaseURL:     srv.URL,
+		APIVersion:  "v22.0",
+		AccessToken: "token-123",
+	})
+
+	if err := client.CheckPhoneNumber(context.Background(), "1022104724314763"); err != nil {
+		t.Fatalf("CheckPhoneNumber returned error: %v", err)
+	}
+	if gotPath != "/v22.0/1022104724314763" || gotQuery != "fields=id" {
+		t.Fatalf("unexpected request: path=%s query=%s", gotPath, gotQuery)
+	}
+	if gotAuth != "Bearer token-123" {
+		t.Fatalf("unexpected auth header: %s", gotAuth)
+	}
+	if got := client.PhoneNumberStatus("1022104724314763").Status; got != PhoneNumberConnected {
+		t.Fatalf("expected connected status, got %s", got)
+	}
+}
+
+func TestSendLinkPreviewEnablesPreviewURL(t *testing.T) {
+	var gotPayload sendMessagePayload
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&gotPayload); err != nil {
+			t.Fatalf("decode body: %v", err)
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	client := NewClient(Config{
+		BaseURL:     srv.URL,
+		APIVersion:  "v22.0",
+		AccessToken: "token-123",
+	})
+
+	_, err := client.SendLinkPreview(context.Background(), "1022104724314763", "593998823053", "Mira https://tinkubot.com")
+	if err != nil {
+		t.Fatalf("SendLinkPreview returned error: %v", err)
+	}
+	if gotPayload.Type != "text" || gotPayload.Text == nil || !gotPayload.Text.PreviewURL {
+		t.Fatalf("expected text payload with preview_url, got %+v", gotPayload)
+	}
+}
+
+func TestSendVideoSuccess(t *testing.T) {
+	var gotPayload sendMessagePayload
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&gotPayload); err != nil {
+			t.Fatalf("decode body: %v", err)
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	client := NewClient(Config{
+		BaseURL:     srv.URL,
+		APIVersion:  "v22.0",
+		AccessToken: "token-123",
+	})
+
+	_, err := client.SendVideo(context.Background(), "1022104724314763", "593998823053", "https://cdn.tinkubot.com/demo.mp4", "demo")
+	if err != nil {
+		t.Fatalf("SendVideo returned error: %v", err)
+	}
+	if gotPayload.Type != "video" || gotPayload.Video == nil || gotPayload.Video.Link != "https://cdn.tinkubot.com/demo.mp4" || gotPayload.Video.Caption != "demo" {
+		t.Fatalf("unexpected payload: %+v", gotPayload)
+	}
+}
+
+func TestSendDocumentByLink(t *testing.T) {
+	var gotPayload sendMessagePayload
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&gotPayload); err != nil {
+			t.Fatalf("decode body: %v", err)
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	client := NewClient(Config{
+		BaseURL:     srv.URL,
+		APIVersion:  "v22.0",
+		AccessToken: "token-123",
+	})
+
+	_, err := client.SendDocument(context.Background(), "1022104724314763", "593998823053", "https://cdn.tinkubot.com/demo.gif", "demo.gif", "demo")
+	if err != nil {
+		t.Fatalf("SendDocument returned error: %v", err)
+	}
+	doc := gotPayload.Document
+	if gotPayload.Type != "document" || doc == nil || doc.Link != "https://cdn.tinkubot.com/demo.gif" || doc.Filename != "demo.gif" || doc.Caption != "demo" || doc.ID != "" {
+		t.Fatalf("unexpected payload: %+v", gotPayload)
+	}
+}
+
+func TestSendRejectsOverlongTextAndCaption(t *testing.T) {
+	var hits int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits++
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	client := NewClient(Config{
+		BaseURL:     srv.URL,
+		APIVersion:  "v22.0",
+		AccessToken: "token-123",
+	})
+	ctx := context.Background()
+
+	if _, err := client.SendText(ctx, "1022104724314763", "593998823053", strings.Repeat("ñ", maxTextBodyLen)); err != nil {
+		t.Fatalf("expected text at the limit to be sent, got %v", err)
+	}
+	_, err := client.SendText(ctx, "1022104724314763", "593998823053", strings.Repeat("a", maxTextBodyLen+1))
+	if !errors.Is(err, ErrMessageTooLong) || !strings.Contains(err.Error(), "text body has 4097 characters") {
+		t.Fatalf("expected ErrMessageTooLong for text, got %v", err)
+	}
+
+	longCaption := strings.Repeat("a", maxCaptionLen+1)
+	_, err = client.SendImage(ctx, "1022104724314763", "593998823053", "https://example.com/a.png", longCaption)
+	if !errors.Is(err, ErrMessageTooLong) || !strings.Contains(err.Error(), "caption has 1025 characters") {
+		t.Fatalf("expected ErrMessageTooLong for image caption, got %v", err)
+	}
+	_, err = client.SendVideo(ctx, "1022104724314763", "593998823053", "https://example.com/a.mp4", longCaption)
+	if !errors.Is(err, ErrMessageTooLong) {
+		t.Fatalf("expected ErrMessageTooLong for video caption, got %v", err)
+	}
+
+	if hits != 1 {
+		t.Fatalf("expected only the valid message to reach Meta, got %d requests", hits)
+	}
+}
@@ -0,0 +1,158 @@
+package metaoutbound
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"strings"
+)
+
+type documentPayload struct {
+	ID       string `json:"id,omitempty"`
+	Link     string `json:"link,omitempty"`
+	Caption  string `json:"caption,omitempty"`
+	Filename string `json:"filename,omitempty"`
+}
+
+type uploadMediaResponse struct {
+	ID string `json:"id"`
+}
+
+// UploadMedia uploads data to the phone number's media store and returns the
+// media ID to reference in a message. Uploads are not retried.
+func (c *Client) UploadMedia(ctx context.Context, phoneNumberID string, data []byte, mimeType, filename string) (string, error) {
+	phoneNumberID = strings.TrimSpace(phoneNumberID)
+	mimeType = strings.TrimSpace(mimeType)
+
+	if c == nil {
+		return "", fmt.Errorf("meta outbound client is nil")
+	}
+	if phoneNumberID == "" {
+		return "", fmt.Errorf("phone_number_id is empty")
+	}
+	accessToken := c.accessTokenFor(phoneNumberID)
+	if accessToken == "" {
+		return "", fmt.Errorf("meta outbound access token is empty for phone_number_id=%s", phoneNumberID)
+	}
+	if len(data) == 0 {
+		return "", fmt.Errorf("media is empty")
+	}
+	if mimeType == "" {
+		mimeType = "application/octet-stream"
+	}
+	if strings.TrimSpace(filename) == "" {
+		filename = "upload"
+	}
+
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	if err := form.WriteField("messaging_product", "whatsapp"); err != nil {
+		return "", fmt.Errorf("build media upload: %w", err)
+	}
+	if err := form.WriteField("type", mimeType); err != nil {
+		return "", fmt.Errorf("build media upload: %w", err)
+	}
+	header := textproto.MIMEHeader{}
+	header.Set("Content-Disposition", fmt.Sprintf(`form-data; name="file"; filename=%q`, filename))
+	header.Set("Content-Type", mimeType)
+	part, err := form.CreatePart(header)
+	if err != nil {
+		return "", fmt.Errorf("build media upload: %w", err)
+	}
+	if _, err := part.Write(data); err != nil {
+		return "", fmt.Errorf("build media upload: %w", err)
+	}
+	if err := form.Close(); err != nil {
+		return "", fmt.Errorf("build media upload: %w", err)
+	}
+
+	url := fmt.Sprintf("%s/%s/%s/media", c.baseURL, c.apiVersion, phoneNumberID)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, &body)
+	if err != nil {
+		return "", fmt.Errorf("create media upload request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+accessToken)
+	req.Header.Set("Content-Type", form.FormDataContentType())
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("media upload failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		if isAuthFailure(resp.StatusCode) {
+			c.recordUnreachable(phoneNumberID)
+		}
+		return "", &SendError{StatusCode: resp.StatusCode, Body: strings.TrimSpace(string(respBody))}
+	}
+	var uploaded uploadMediaResponse
+	if err := json.Unmarshal(respBody, &uploaded); err != nil || strings.TrimSpace(uploaded.ID) == "" {
+		return "", fmt.Errorf("media upload returned no id: %s", strings.TrimSpace(string(respBody)))
+	}
+	return strings.TrimSpace(uploaded.ID), nil
+}
+
+// SendImageFromBytes uploads a JPEG or PNG image and sends it.
+func (c *Client) SendImageFromBytes(ctx context.Context, phoneNumberID, to string, data []byte, mimeType, caption string) (string, error) {
+	to = strings.TrimSpace(to)
+	caption = strings.TrimSpace(caption)
+	if to == "" {
+		return "", fmt.Errorf("destination number is empty")
+	}
+	if err := validateCaption(caption); err != nil {
+		return "", err
+	}
+
+	mediaID, err := c.UploadMedia(ctx, phoneNumberID, data, mimeType, "")
+	if err != nil {
+		return "", err
+	}
+	payload := sendMessagePayload{
+		MessagingProduct: "whatsapp",
+		RecipientType:    "individual",
+		To:               to,
+		Type:             "image",
+		Image: &imagePayload{
+			ID:      mediaID,
+			Caption: caption,
+		},
+	}
+	return c.sendMessage(ctx, strings.TrimSpace(phoneNumberID), c.accessTokenFor(phoneNumberID), payload)
+}
+
+// SendDocumentFromBytes uploads a file and sends it as a document.
+func (c *Client) SendDocumentFromBytes(ctx context.Context, phoneNumberID, to string, data []byte, mimeType, filename, caption string) (string, error) {
+	to = strings.TrimSpace(to)
+	caption = strings.TrimSpace(caption)
+	filename = strings.TrimSpace(filename)
+	if to == "" {
+		return "", fmt.Errorf("destination number is empty")
+	}
+	if err := validateCaption(caption); err != nil {
+		return "", err
+	}
+
+	mediaID, err := c.UploadMedia(ctx, phoneNumberID, data, mimeType, filename)
+	if err != nil {
+		return "", err
+	}
+	payload := sendMessagePayload{
+		MessagingProduct: "whatsapp",
+		RecipientType:    "individual",
+		To:               to,
+		Type:             "document",
+		Document: &documentPayload{
+			ID:       mediaID,
+			Caption:  caption,
+			Filename: filename,
+		},
+	}
+	return c.sendMessage(ctx, strings.TrimSpace(phoneNumberID), c.accessTokenFor(phoneNumberID), payload)
+}
@@ -0,0 +1,84 @@
+package metaoutbound
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestSendDocumentFromBytesUploadsThenSends(t *testing.T) {
+	var gotUpload []byte
+	var gotUploadType string
+	var gotFilename string
+	var gotPayload sendMessagePayload
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer token-123" {
+			t.Fatalf("unexpected auth header: %s", r.Header.Get("Authorization"))
+		}
+		switch r.URL.Path {
+		case "/v22.0/1022104724314763/media":
+			if r.FormValue("messaging_product") != "whatsapp" {
+				t.Fatalf("expected messaging_product=whatsapp, got %q", r.FormValue("messaging_product"))
+			}
+			gotUploadType = r.FormValue("type")
+			file, header, err := r.FormFile("file")
+			if err != nil {
+				t.Fatalf("read file part: %v", err)
+			}
+			gotFilename = header.Filename
+			gotUpload, _ = io.ReadAll(file)
+			w.Write([]byte(`{"id":"media-1"}`))
+		case "/v22.0/1022104724314763/messages":
+			if err := json.NewDecoder(r.Body).Decode(&gotPayload); err != nil {
+				t.Fatalf("decode body: %v", err)
+			}
+			w.WriteHeader(http.StatusOK)
+		default:
+			t.Fatalf("unexpected path: %s", r.URL.Path)
+		}
+	}))
+	defer srv.Close()
+
+	client := NewClient(Config{
+		BaseURL:     srv.URL,
+		APIVersion:  "v22.0",
+		AccessToken: "token-123",
+	})
+
+	data := []byte("%PDF-1.4 test")
+	_, err := client.SendDocumentFromBytes(context.Background(), "1022104724314763", "593998823053", data, "application/pdf", "factura.pdf", "Tu factura")
+	if err != nil {
+		t.Fatalf("SendDocumentFromBytes returned error: %v", err)
+	}
+
+	if string(gotUpload) != string(data) || gotUploadType != "application/pdf" || gotFilename != "factura.pdf" {
+		t.Fatalf("unexpected upload: type=%q filename=%q data=%q", gotUploadType, gotFilename, gotUpload)
+	}
+	if gotPayload.Type != "document" || gotPayload.Document == nil {
+		t.Fatalf("unexpected payload type: %+v", gotPayload)
+	}
+	if gotPayload.Document.ID != "media-1" || gotPayload.Document.Filename != "factura.pdf" || gotPayload.Document.Caption != "Tu factura" {
+		t.Fatalf("unexpected document: %+v", gotPayload.Document)
+	}
+}
+
+func TestUploadMediaFailsWithoutID(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{}`))
+	}))
+	defer srv.Close()
+
+	client := NewClient(Config{
+		BaseURL:     srv.URL,
+		APIVersion:  "v22.0",
+		AccessToken: "token-123",
+	})
+
+	if _, err := client.UploadMedia(context.Background(), "1022104724314763", []byte("x"), "image/png", ""); err == nil {
+		t.Fatalf("expected error when upload returns no id")
+	}
+}
@@ -0,0 +1,111 @@
+package metaoutbound
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	PhoneNumberUnknown   = "unknown"
+	PhoneNumberConnected = "connected"
+	PhoneNumberFailing   = "failing"
+)
+
+// PhoneNumberStatus summarizes whether the Graph API has recently accepted
+// requests for a phone number. Cloud API has no persistent connection, so the
+// status is derived from the outcome of the last outbound request.
+type PhoneNumberStatus struct {
+	PhoneNumberID string
+	Status        string
+	LastSeenAt    time.Time
+	FailingSince  time.Time
+}
+
+// PhoneNumberStatus returns the last known reachability of phoneNumberID.
+func (c *Client) PhoneNumberStatus(phoneNumberID string) PhoneNumberStatus {
+	c.statusMu.Lock()
+	defer c.statusMu.Unlock()
+
+	if status, ok := c.statuses[phoneNumberID]; ok {
+		return *status
+	}
+	return PhoneNumberStatus{PhoneNumberID: phoneNumberID, Status: PhoneNumberUnknown}
+}
+
+func (c *Client) recordReachable(phoneNumberID string) {
+	c.statusMu.Lock()
+	defer c.statusMu.Unlock()
+
+	c.statuses[phoneNumberID] = &PhoneNumberStatus{
+		PhoneNumberID: phoneNumberID,
+		Status:        PhoneNumberConnected,
+		LastSeenAt:    time.Now(),
+	}
+}
+
+func (c *Client) recordUnreachable(phoneNumberID string) {
+	c.statusMu.Lock()
+	defer c.statusMu.Unlock()
+
+	status, ok := c.statuses[phoneNumberID]
+	if !ok {
+		status = &PhoneNumberStatus{PhoneNumberID: phoneNumberID}
+		c.statuses[phoneNumberID] = status
+	}
+	if status.Status != PhoneNumberFailing {
+		status.Status = PhoneNumberFailing
+		status.FailingSince = time.Now()
+	}
+}
+
+// isAuthFailure reports whether a Graph API status means the access token can
+// no longer send for the phone number, e.g. an expired or revoked token.
+func isAuthFailure(statusCode int) bool {
+	return statusCode == http.StatusUnauthorized || statusCode == http.StatusForbidden
+}
+
+// CheckPhoneNumber verifies that the Graph API accepts the access token for
+// phoneNumberID by fetching the phone number node. The outcome updates
+// PhoneNumberStatus.
+func (c *Client) CheckPhoneNumber(ctx context.Context, phoneNumberID string) error {
+	phoneNumberID = strings.TrimSpace(phoneNumberID)
+
+	if c == nil {
+		return fmt.Errorf("meta outbound client is nil")
+	}
+	if phoneNumberID == "" {
+		return fmt.Errorf("phone_number_id is empty")
+	}
+	accessToken := c.accessTokenFor(phoneNumberID)
+	if accessToken == "" {
+		return fmt.Errorf("meta outbound access token is empty for phone_number_id=%s", phoneNumberID)
+	}
+
+	url := fmt.Sprintf("%s/%s/%s?fields=id", c.baseURL, c.apiVersion, phoneNumberID)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return fmt.Errorf("create phone number request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+accessToken)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		c.recordUnreachable(phoneNumberID)
+		return fmt.Errorf("phone number request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		c.recordReachable(phoneNumberID)
+		return nil
+	}
+	if isAuthFailure(resp.StatusCode) || resp.StatusCode >= 500 {
+		c.recordUnreachable(phoneNumberID)
+	}
+	return fmt.Errorf("phone number check status=%d body=%s", resp.StatusCode, strings.TrimSpace(string(respBody)))
+}
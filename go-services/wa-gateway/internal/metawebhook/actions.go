@@ -0,0 +1,69 @@
+package metawebhook
+
+import (
+	"context"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/tinkubot/wa-gateway/internal/webhook"
+)
+
+// ActionExecutor runs gateway-level actions requested by AI services.
+type ActionExecutor interface {
+	MarkRead(ctx context.Context, phoneNumberID, messageID string) error
+	SendTypingIndicator(ctx context.Context, phoneNumberID, messageID string) error
+	BlockUser(ctx context.Context, phoneNumberID, user string) error
+}
+
+// executeActions runs the actions returned by the AI service for msg. Actions
+// default to the inbound message and sender unless params override them.
+func (s *Service) executeActions(
+	ctx context.Context,
+	accountID string,
+	msg incomingMessage,
+	actions []webhook.GatewayAction,
+) {
+	executor, ok := s.outboundSender.(ActionExecutor)
+	if !ok {
+		log.Printf("[MetaWebhook] Actions requested but outbound sender cannot execute them account=%s count=%d", accountID, len(actions))
+		return
+	}
+	for idx, action := range actions {
+		actionType := strings.TrimSpace(action.Type)
+		switch actionType {
+		case "mark_read", "send_typing", "add_to_blocklist":
+		case "set_conversation_state":
+			// Conversation state lives in the AI services; the gateway keeps none.
+			log.Printf("[MetaWebhook] Action set_conversation_state ignored: gateway is stateless account=%s index=%d", accountID, idx)
+			continue
+		default:
+			log.Printf("[MetaWebhook] Unsupported action type=%s account=%s index=%d", actionType, accountID, idx)
+			continue
+		}
+
+		actionCtx, cancel := context.WithTimeout(ctx, 20*time.Second)
+		var err error
+		switch actionType {
+		case "mark_read":
+			err = executor.MarkRead(actionCtx, msg.PhoneNumberID, actionParam(action, "message_id", msg.MessageID))
+		case "send_typing":
+			err = executor.SendTypingIndicator(actionCtx, msg.PhoneNumberID, actionParam(action, "message_id", msg.MessageID))
+		case "add_to_blocklist":
+			err = executor.BlockUser(actionCtx, msg.PhoneNumberID, actionParam(action, "user", msg.From))
+		}
+		cancel()
+		if err != nil {
+			log.Printf("[MetaWebhook] Action failed type=%s account=%s phone_number_id=%s from=%s index=%d err=%v", actionType, accountID, msg.PhoneNumberID, msg.From, idx, err)
+			continue
+		}
+		log.Printf("[MetaWebhook] Action ok type=%s account=%s phone_number_id=%s from=%s index=%d", actionType, accountID, msg.PhoneNumberID, msg.From, idx)
+	}
+}
+
+func actionParam(action webhook.GatewayAction, key, fallback string) string {
+	if raw, ok := action.Params[key].(string); ok && strings.TrimSpace(raw) != "" {
+		return strings.TrimSpace(raw)
+	}
+	return fallback
+}
@@ -0,0 +1,144 @@
+package metawebhook
+
+import (
+	"context"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/tinkubot/wa-gateway/internal/webhook"
+)
+
+// pendingBatch holds the messages of one chat received during the batch
+// window. The fields besides items describe the latest message.
+type pendingBatch struct {
+	inboundTraceID string
+	accountID      string
+	userIdentifier string
+	msg            incomingMessage
+	payload        *webhook.WebhookPayload
+	items          []webhook.MessageItem
+}
+
+// messageBatcher buffers inbound messages per account and chat for window,
+// counted from the first message, and then hands them to flush together.
+type messageBatcher struct {
+	mu      sync.Mutex
+	window  time.Duration
+	pending map[string]*pendingBatch
+	flush   func(*pendingBatch)
+	// closed makes add flush right away; flushing tracks flushes in progress
+	// so close can wait for them.
+	closed   bool
+	flushing sync.WaitGroup
+}
+
+func newMessageBatcher(window time.Duration, flush func(*pendingBatch)) *messageBatcher {
+	return &messageBatcher{
+		window:  window,
+		pending: make(map[string]*pendingBatch),
+		flush:   flush,
+	}
+}
+
+func (b *messageBatcher) add(inboundTraceID, accountID, userIdentifier string, msg incomingMessage, payload *webhook.WebhookPayload) {
+	key := accountID + "|" + payload.ChatJID
+	b.mu.Lock()
+	batch, ok := b.pending[key]
+	if !ok {
+		batch = &pendingBatch{}
+		b.pending[key] = batch
+		if !b.closed {
+			time.AfterFunc(b.window, func() { b.flushKey(key) })
+		}
+	}
+	batch.inboundTraceID = inboundTraceID
+	batch.accountID = accountID
+	batch.userIdentifier = userIdentifier
+	batch.msg = msg
+	batch.payload = payload
+	batch.items = append(batch.items, webhook.MessageItem{
+		MessageID:      payload.MessageID,
+		MessageType:    payload.MessageType,
+		Content:        payload.Content,
+		SelectedOption: payload.SelectedOption,
+		Location:       payload.Location,
+		MediaBase64:    payload.MediaBase64,
+		MediaMimetype:  payload.MediaMimetype,
+		MediaFilename:  payload.MediaFilename,
+		Timestamp:      payload.Timestamp,
+	})
+	closed := b.closed
+	b.mu.Unlock()
+	if closed {
+		b.flushKey(key)
+	}
+}
+
+func (b *messageBatcher) flushKey(key string) {
+	b.mu.Lock()
+	batch := b.pending[key]
+	delete(b.pending, key)
+	if batch != nil {
+		b.flushing.Add(1)
+	}
+	b.mu.Unlock()
+	if batch != nil {
+		defer b.flushing.Done()
+		b.flush(batch)
+	}
+}
+
+// close flushes every pending batch without waiting for its window, and
+// waits for those and any flushes already in progress. Messages added
+// afterwards are forwarded right away.
+func (b *messageBatcher) close() {
+	b.mu.Lock()
+	b.closed = true
+	batches := make([]*pendingBatch, 0, len(b.pending))
+	for key, batch := range b.pending {
+		batches = append(batches, batch)
+		delete(b.pending, key)
+	}
+	b.flushing.Add(len(batches))
+	b.mu.Unlock()
+	for _, batch := range batches {
+		go func() {
+			defer b.flushing.Done()
+			b.flush(batch)
+		}()
+	}
+	b.flushing.Wait()
+}
+
+// forwardBatch forwards a chat's batched messages as one payload built on the
+// latest message. Content and Message join the text of every message so AI
+// services that ignore Messages still see all of it. Media is only sent in
+// Messages, so the latest message's media is not sent twice.
+func (s *Service) forwardBatch(batch *pendingBatch) {
+	payload := batch.payload
+	payload.Messages = batch.items
+	payload.MediaBase64 = ""
+	payload.MediaMimetype = ""
+	payload.MediaFilename = ""
+	contents := make([]string, 0, len(batch.items))
+	for _, item := range batch.items {
+		if item.Content != "" {
+			contents = append(contents, item.Content)
+		}
+	}
+	payload.Content = strings.Join(contents, "\n")
+	payload.Message = payload.Content
+
+	log.Printf(
+		"[MetaWebhook] batch_flushed inbound_trace_id=%s account=%s chat=%s messages=%d",
+		batch.inboundTraceID,
+		batch.accountID,
+		payload.ChatJID,
+		len(batch.items),
+	)
+	// The Meta webhook request that delivered these messages has already
+	// been answered, so its context is gone.
+	s.forward(context.Background(), batch.inboundTraceID, batch.accountID, batch.userIdentifier, batch.msg, payload)
+}
@@ -0,0 +1,107 @@
+package metawebhook
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/tinkubot/wa-gateway/internal/webhook"
+)
+
+type channelSender struct {
+	payloads chan *webhook.WebhookPayload
+}
+
+func (f *channelSender) Send(_ context.Context, payload *webhook.WebhookPayload) (*webhook.WebhookResponse, error) {
+	f.payloads <- payload
+	return &webhook.WebhookResponse{Success: true}, nil
+}
+
+func TestProcessEventBatchesMessagesPerChat(t *testing.T) {
+	fs := &channelSender{payloads: make(chan *webhook.WebhookPayload, 4)}
+	svc := NewService(Config{
+		Enabled:   true,
+		AppSecret: "secret-1",
+		PhoneNumberToAccount: map[string]string{
+			"123456789": "bot-clientes",
+		},
+		BatchWindow: 50 * time.Millisecond,
+	}, fs, nil, nil)
+
+	send := func(id, from, text string) {
+		body := []byte(fmt.Sprintf(`{
+			"object":"whatsapp_business_account",
+			"entry":[{"id":"waba-1","changes":[{"field":"messages","value":{
+				"metadata":{"phone_number_id":"123456789"},
+				"messages":[{"from":%q,"id":%q,"timestamp":"1730000001","type":"text","text":{"body":%q}}]
+			}}]}]
+		}`, from, id, text))
+		if err := svc.ProcessEvent(context.Background(), buildSignature("secret-1", body), body); err != nil {
+			t.Fatalf("expected nil error, got %v", err)
+		}
+	}
+	send("wamid.1", "593999111222", "Hola")
+	send("wamid.2", "593999111222", "necesito un plomero")
+	send("wamid.3", "593999111333", "Buenas")
+
+	select {
+	case payload := <-fs.payloads:
+		t.Fatalf("expected nothing forwarded before the window closes, got %+v", payload)
+	default:
+	}
+
+	byChat := map[string]*webhook.WebhookPayload{}
+	for i := 0; i < 2; i++ {
+		select {
+		case payload := <-fs.payloads:
+			byChat[payload.Phone] = payload
+		case <-time.After(time.Second):
+			t.Fatalf("expected 2 batched payloads, got %d", len(byChat))
+		}
+	}
+
+	batched := byChat["593999111222"]
+	if batched == nil || len(batched.Messages) != 2 || batched.Messages[0].MessageID != "wamid.1" || batched.Messages[1].Content != "necesito un plomero" {
+		t.Fatalf("unexpected batched payload: %+v", batched)
+	}
+	if batched.Content != "Hola\nnecesito un plomero" || batched.Message != batched.Content || batched.MessageID != "wamid.2" {
+		t.Fatalf("expected joined content and latest message id, got content=%q message_id=%q", batched.Content, batched.MessageID)
+	}
+	if single := byChat["593999111333"]; single == nil || len(single.Messages) != 1 || single.Content != "Buenas" {
+		t.Fatalf("unexpected single-message payload: %+v", single)
+	}
+}
+
+func TestCloseFlushesPendingBatchesWithMediaInMessagesOnly(t *testing.T) {
+	fs := &channelSender{payloads: make(chan *webhook.WebhookPayload, 1)}
+	media := &fakeMediaDownloader{data: []byte("front-image-bytes"), mimetype: "image/jpeg", filename: "cedula.jpg"}
+	svc := NewService(Config{
+		Enabled:   true,
+		AppSecret: "secret-1",
+		PhoneNumberToAccount: map[string]string{
+			"123456789": "bot-proveedores",
+		},
+		BatchWindow: time.Hour,
+	}, fs, nil, media)
+
+	body := []byte(`{"object":"whatsapp_business_account","entry":[{"id":"waba-1","changes":[{"field":"messages","value":{"metadata":{"phone_number_id":"123456789"},"messages":[{"from":"593999111222","id":"wamid.img","timestamp":"1730000001","type":"image","image":{"id":"1479537139650973","mime_type":"image/jpeg","caption":"frente"}}]}}]}]}`)
+	if err := svc.ProcessEvent(context.Background(), buildSignature("secret-1", body), body); err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	if err := svc.Close(context.Background()); err != nil {
+		t.Fatalf("expected pending batch to be flushed, got %v", err)
+	}
+
+	select {
+	case payload := <-fs.payloads:
+		if payload.MediaBase64 != "" || payload.MediaMimetype != "" {
+			t.Fatalf("expected no top-level media in batched payload, got %q/%q", payload.MediaBase64, payload.MediaMimetype)
+		}
+		if len(payload.Messages) != 1 || payload.Messages[0].MediaBase64 != "ZnJvbnQtaW1hZ2UtYnl0ZXM=" {
+			t.Fatalf("expected media in messages, got %+v", payload.Messages)
+		}
+	default:
+		t.Fatal("expected Close to forward the pending batch")
+	}
+}
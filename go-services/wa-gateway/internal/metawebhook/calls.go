@@ -0,0 +1,137 @@
+package metawebhook
+
+import (
+	"context"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/tinkubot/wa-gateway/internal/webhook"
+)
+
+// CallSender is implemented by senders that can forward call events.
+type CallSender interface {
+	SendCall(ctx context.Context, event *webhook.CallEvent) error
+}
+
+// CallRejecter is implemented by outbound senders that can decline calls.
+type CallRejecter interface {
+	RejectCall(ctx context.Context, phoneNumberID, callID string) error
+}
+
+// metaCall is an entry of a "calls" webhook change. Event is "connect" when
+// a user calls and "terminate" when the call ends; Duration is in seconds.
+type metaCall struct {
+	ID        string `json:"id"`
+	From      string `json:"from"`
+	To        string `json:"to"`
+	Event     string `json:"event"`
+	Direction string `json:"direction"`
+	Timestamp string `json:"timestamp"`
+	Status    string `json:"status"`
+	Duration  int    `json:"duration"`
+}
+
+type incomingCall struct {
+	PhoneNumberID string
+	Event         webhook.CallEvent
+}
+
+// extractCallEvents returns the call offers and terminations in evt. The
+// Cloud API Calling API only carries voice calls.
+func extractCallEvents(evt webhookEvent) []incomingCall {
+	out := make([]incomingCall, 0)
+	for _, e := range evt.Entry {
+		for _, ch := range e.Changes {
+			if ch.Field != "calls" {
+				continue
+			}
+			for _, call := range ch.Value.Calls {
+				var event string
+				switch strings.TrimSpace(call.Event) {
+				case "connect":
+					event = webhook.CallEventOffer
+				case "terminate":
+					event = webhook.CallEventTerminate
+				default:
+					continue
+				}
+				from := strings.TrimSpace(call.From)
+				out = append(out, incomingCall{
+					PhoneNumberID: ch.Value.Metadata.PhoneNumberID,
+					Event: webhook.CallEvent{
+						Event:           event,
+						Phone:           from,
+						CallID:          strings.TrimSpace(call.ID),
+						CallerJID:       buildFromNumber(from, ""),
+						CallType:        "audio",
+						Direction:       strings.TrimSpace(call.Direction),
+						Status:          strings.TrimSpace(call.Status),
+						DurationSeconds: call.Duration,
+						Timestamp:       strings.TrimSpace(call.Timestamp),
+					},
+				})
+			}
+		}
+	}
+	return out
+}
+
+// forwardCalls sends call events to the AI service, first declining offers
+// when AutoRejectCalls is set. Events are deduplicated per call and event.
+func (s *Service) forwardCalls(ctx context.Context, calls []incomingCall) {
+	if len(calls) == 0 {
+		return
+	}
+	callSender, _ := s.sender.(CallSender)
+	for _, call := range calls {
+		accountID, ok := s.cfg.PhoneNumberToAccount[call.PhoneNumberID]
+		if !ok || accountID == "" {
+			log.Printf("[MetaWebhook] Unknown phone_number_id=%s, skipping call", call.PhoneNumberID)
+			continue
+		}
+		if len(s.cfg.EnabledAccounts) > 0 && !s.cfg.EnabledAccounts[accountID] {
+			continue
+		}
+		event := call.Event
+		event.AccountID = accountID
+		if _, loaded := s.seenMessages.LoadOrStore("call:"+event.CallID+":"+event.Event, time.Now()); loaded {
+			log.Printf("[MetaWebhook] duplicate_call_skipped call_id=%s event=%s account=%s", event.CallID, event.Event, accountID)
+			continue
+		}
+		log.Printf(
+			"[MetaWebhook] call_received account=%s from=%s call_id=%s event=%s status=%s duration_seconds=%d",
+			accountID, event.Phone, event.CallID, event.Event, event.Status, event.DurationSeconds,
+		)
+
+		if event.Event == webhook.CallEventOffer && s.cfg.AutoRejectCalls {
+			event.Rejected = s.rejectCall(ctx, accountID, call.PhoneNumberID, event.CallID)
+		}
+		if callSender == nil {
+			log.Printf("[MetaWebhook] call_event_dropped call_id=%s reason=sender_unsupported", event.CallID)
+			continue
+		}
+		sendCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+		err := callSender.SendCall(sendCtx, &event)
+		cancel()
+		if err != nil {
+			log.Printf("[MetaWebhook] Failed forwarding call account=%s call_id=%s: %v", accountID, event.CallID, err)
+		}
+	}
+}
+
+func (s *Service) rejectCall(ctx context.Context, accountID, phoneNumberID, callID string) bool {
+	rejecter, ok := s.outboundSender.(CallRejecter)
+	if !ok {
+		log.Printf("[MetaWebhook] call_reject_skipped account=%s call_id=%s reason=sender_unsupported", accountID, callID)
+		return false
+	}
+	rejectCtx, cancel := context.WithTimeout(ctx, 15*time.Second)
+	defer cancel()
+	if err := rejecter.RejectCall(rejectCtx, phoneNumberID, callID); err != nil {
+		log.Printf("[MetaWebhook] call_reject_failed account=%s call_id=%s err=%v", accountID, callID, err)
+		return false
+	}
+	log.Printf("[MetaWebhook] call_rejected account=%s call_id=%s", accountID, callID)
+	return true
+}
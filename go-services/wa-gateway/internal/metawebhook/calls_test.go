@@ -0,0 +1,75 @@
+package metawebhook
+
+import (
+	"context"
+	"testing"
+
+	"github.com/tinkubot/wa-gateway/internal/webhook"
+)
+
+type fakeCallSender struct {
+	fakeSender
+	calls []*webhook.CallEvent
+}
+
+func (f *fakeCallSender) SendCall(_ context.Context, event *webhook.CallEvent) error {
+	f.calls = append(f.calls, event)
+	return nil
+}
+
+type fakeCallRejecter struct {
+	fakeOutboundSender
+	rejected []string
+}
+
+func (f *fakeCallRejecter) RejectCall(_ context.Context, phoneNumberID, callID string) error {
+	f.rejected = append(f.rejected, phoneNumberID+"/"+callID)
+	return nil
+}
+
+func TestProcessEventForwardsAndRejectsCalls(t *testing.T) {
+	fs := &fakeCallSender{}
+	rejecter := &fakeCallRejecter{}
+	svc := NewService(Config{
+		Enabled:   true,
+		AppSecret: "secret-1",
+		PhoneNumberToAccount: map[string]string{
+			"123456789": "bot-clientes",
+		},
+		AutoRejectCalls: true,
+	}, fs, rejecter, nil)
+
+	body := []byte(`{
+		"object":"whatsapp_business_account",
+		"entry":[{"id":"waba-1","changes":[{"field":"calls","value":{
+			"metadata":{"phone_number_id":"123456789"},
+			"calls":[
+				{"id":"wacid.1","from":"593999111222","to":"593111222333","event":"connect","direction":"USER_INITIATED","timestamp":"1730000001"},
+				{"id":"wacid.1","from":"593999111222","to":"593111222333","event":"terminate","direction":"USER_INITIATED","timestamp":"1730000005","status":"Completed","duration":4}
+			]
+		}}]}]
+	}`)
+	sig := buildSignature("secret-1", body)
+	for i := 0; i < 2; i++ {
+		if err := svc.ProcessEvent(context.Background(), sig, body); err != nil {
+			t.Fatalf("expected nil error, got %v", err)
+		}
+	}
+
+	if len(fs.calls) != 2 {
+		t.Fatalf("expected 2 deduplicated call events, got %d", len(fs.calls))
+	}
+	offer, terminate := fs.calls[0], fs.calls[1]
+	if offer.Event != webhook.CallEventOffer || offer.AccountID != "bot-clientes" || offer.CallID != "wacid.1" || offer.CallerJID != "593999111222@s.whatsapp.net" || offer.CallType != "audio" || !offer.Rejected {
+		t.Fatalf("unexpected offer event: %+v", offer)
+	}
+	if terminate.Event != webhook.CallEventTerminate || terminate.DurationSeconds != 4 || terminate.Status != "Completed" || terminate.Rejected {
+		t.Fatalf("unexpected terminate event: %+v", terminate)
+	}
+	if len(rejecter.rejected) != 1 || rejecter.rejected[0] != "123456789/wacid.1" {
+		t.Fatalf("expected one rejected call, got %v", rejecter.rejected)
+	}
+	if len(fs.payloads) != 0 {
+		t.Fatalf("expected calls not to be forwarded as messages, got %d", len(fs.payloads))
+	}
+}
@@ -0,0 +1,365 @@
+package metawebhook
+
+import "strings"
+
+type country struct {
+	iso  string
+	name string
+}
+
+// callingCodes maps ITU-T E.164 country calling codes to the country that
+// primarily uses them. +1 is resolved by area code through nanpAreaCodes.
+// Other codes shared by several countries (+7, +44, +47, +61, +262, +590,
+// +599) resolve to the largest member; the longer prefixes +76/+77 are split
+// out for Kazakhstan.
+var callingCodes = map[string]country{
+	"1":   {"US", "United States"},
+	"7":   {"RU", "Russia"},
+	"76":  {"KZ", "Kazakhstan"},
+	"77":  {"KZ", "Kazakhstan"},
+	"20":  {"EG", "Egypt"},
+	"27":  {"ZA", "South Africa"},
+	"30":  {"GR", "Greece"},
+	"31":  {"NL", "Netherlands"},
+	"32":  {"BE", "Belgium"},
+	"33":  {"FR", "France"},
+	"34":  {"ES", "Spain"},
+	"36":  {"HU", "Hungary"},
+	"39":  {"IT", "Italy"},
+	"40":  {"RO", "Romania"},
+	"41":  {"CH", "Switzerland"},
+	"43":  {"AT", "Austria"},
+	"44":  {"GB", "United Kingdom"},
+	"45":  {"DK", "Denmark"},
+	"46":  {"SE", "Sweden"},
+	"47":  {"NO", "Norway"},
+	"48":  {"PL", "Poland"},
+	"49":  {"DE", "Germany"},
+	"51":  {"PE", "Peru"},
+	"52":  {"MX", "Mexico"},
+	"53":  {"CU", "Cuba"},
+	"54":  {"AR", "Argentina"},
+	"55":  {"BR", "Brazil"},
+	"56":  {"CL", "Chile"},
+	"57":  {"CO", "Colombia"},
+	"58":  {"VE", "Venezuela"},
+	"60":  {"MY", "Malaysia"},
+	"61":  {"AU", "Australia"},
+	"62":  {"ID", "Indonesia"},
+	"63":  {"PH", "Philippines"},
+	"64":  {"NZ", "New Zealand"},
+	"65":  {"SG", "Singapore"},
+	"66":  {"TH", "Thailand"},
+	"81":  {"JP", "Japan"},
+	"82":  {"KR", "South Korea"},
+	"84":  {"VN", "Vietnam"},
+	"86":  {"CN", "China"},
+	"90":  {"TR", "Turkey"},
+	"91":  {"IN", "India"},
+	"92":  {"PK", "Pakistan"},
+	"93":  {"AF", "Afghanistan"},
+	"94":  {"LK", "Sri Lanka"},
+	"95":  {"MM", "Myanmar"},
+	"98":  {"IR", "Iran"},
+	"211": {"SS", "South Sudan"},
+	"212": {"MA", "Morocco"},
+	"213": {"DZ", "Algeria"},
+	"216": {"TN", "Tunisia"},
+	"218": {"LY", "Libya"},
+	"220": {"GM", "Gambia"},
+	"221": {"SN", "Senegal"},
+	"222": {"MR", "Mauritania"},
+	"223": {"ML", "Mali"},
+	"224": {"GN", "Guinea"},
+	"225": {"CI", "Côte d'Ivoire"},
+	"226": {"BF", "Burkina Faso"},
+	"227": {"NE", "Niger"},
+	"228": {"TG", "Togo"},
+	"229": {"BJ", "Benin"},
+	"230": {"MU", "Mauritius"},
+	"231": {"LR", "Liberia"},
+	"232": {"SL", "Sierra Leone"},
+	"233": {"GH", "Ghana"},
+	"234": {"NG", "Nigeria"},
+	"235": {"TD", "Chad"},
+	"236": {"CF", "Central African Republic"},
+	"237": {"CM", "Cameroon"},
+	"238": {"CV", "Cape Verde"},
+	"239": {"ST", "São Tomé and Príncipe"},
+	"240": {"GQ", "Equatorial Guinea"},
+	"241": {"GA", "Gabon"},
+	"242": {"CG", "Republic of the Congo"},
+	"243": {"CD", "DR Congo"},
+	"244": {"AO", "Angola"},
+	"245": {"GW", "Guinea-Bissau"},
+	"248": {"SC", "Seychelles"},
+	"249": {"SD", "Sudan"},
+	"250": {"RW", "Rwanda"},
+	"251": {"ET", "Ethiopia"},
+	"252": {"SO", "Somalia"},
+	"253": {"DJ", "Djibouti"},
+	"254": {"KE", "Kenya"},
+	"255": {"TZ", "Tanzania"},
+	"256": {"UG", "Uganda"},
+	"257": {"BI", "Burundi"},
+	"258": {"MZ", "Mozambique"},
+	"260": {"ZM", "Zambia"},
+	"261": {"MG", "Madagascar"},
+	"262": {"RE", "Réunion"},
+	"263": {"ZW", "Zimbabwe"},
+	"264": {"NA", "Namibia"},
+	"265": {"MW", "Malawi"},
+	"266": {"LS", "Lesotho"},
+	"267": {"BW", "Botswana"},
+	"268": {"SZ", "Eswatini"},
+	"269": {"KM", "Comoros"},
+	"290": {"SH", "Saint Helena"},
+	"291": {"ER", "Eritrea"},
+	"297": {"AW", "Aruba"},
+	"298": {"FO", "Faroe Islands"},
+	"299": {"GL", "Greenland"},
+	"350": {"GI", "Gibraltar"},
+	"351": {"PT", "Portugal"},
+	"352": {"LU", "Luxembourg"},
+	"353": {"IE", "Ireland"},
+	"354": {"IS", "Iceland"},
+	"355": {"AL", "Albania"},
+	"356": {"MT", "Malta"},
+	"357": {"CY", "Cyprus"},
+	"358": {"FI", "Finland"},
+	"359": {"BG", "Bulgaria"},
+	"370": {"LT", "Lithuania"},
+	"371": {"LV", "Latvia"},
+	"372": {"EE", "Estonia"},
+	"373": {"MD", "Moldova"},
+	"374": {"AM", "Armenia"},
+	"375": {"BY", "Belarus"},
+	"376": {"AD", "Andorra"},
+	"377": {"MC", "Monaco"},
+	"378": {"SM", "San Marino"},
+	"380": {"UA", "Ukraine"},
+	"381": {"RS", "Serbia"},
+	"382": {"ME", "Montenegro"},
+	"383": {"XK", "Kosovo"},
+	"385": {"HR", "Croatia"},
+	"386": {"SI", "Slovenia"},
+	"387": {"BA", "Bosnia and Herzegovina"},
+	"389": {"MK", "North Macedonia"},
+	"420": {"CZ", "Czech Republic"},
+	"421": {"SK", "Slovakia"},
+	"423": {"LI", "Liechtenstein"},
+	"500": {"FK", "Falkland Islands"},
+	"501": {"BZ", "Belize"},
+	"502": {"GT", "Guatemala"},
+	"503": {"SV", "El Salvador"},
+	"504": {"HN", "Honduras"},
+	"505": {"NI", "Nicaragua"},
+	"506": {"CR", "Costa Rica"},
+	"507": {"PA", "Panama"},
+	"508": {"PM", "Saint Pierre and Miquelon"},
+	"509": {"HT", "Haiti"},
+	"590": {"GP", "Guadeloupe"},
+	"591": {"BO", "Bolivia"},
+	"592": {"GY", "Guyana"},
+	"593": {"EC", "Ecuador"},
+	"594": {"GF", "French Guiana"},
+	"595": {"PY", "Paraguay"},
+	"596": {"MQ", "Martinique"},
+	"597": {"SR", "Suriname"},
+	"598": {"UY", "Uruguay"},
+	"599": {"CW", "Curaçao"},
+	"670": {"TL", "Timor-Leste"},
+	"672": {"NF", "Norfolk Island"},
+	"673": {"BN", "Brunei"},
+	"674": {"NR", "Nauru"},
+	"675": {"PG", "Papua New Guinea"},
+	"676": {"TO", "Tonga"},
+	"677": {"SB", "Solomon Islands"},
+	"678": {"VU", "Vanuatu"},
+	"679": {"FJ", "Fiji"},
+	"680": {"PW", "Palau"},
+	"681": {"WF", "Wallis and Futuna"},
+	"682": {"CK", "Cook Islands"},
+	"683": {"NU", "Niue"},
+	"685": {"WS", "Samoa"},
+	"686": {"KI", "Kiribati"},
+	"687": {"NC", "New Caledonia"},
+	"688": {"TV", "Tuvalu"},
+	"689": {"PF", "French Polynesia"},
+	"690": {"TK", "Tokelau"},
+	"691": {"FM", "Micronesia"},
+	"692": {"MH", "Marshall Islands"},
+	"850": {"KP", "North Korea"},
+	"852": {"HK", "Hong Kong"},
+	"853": {"MO", "Macau"},
+	"855": {"KH", "Cambodia"},
+	"856": {"LA", "Laos"},
+	"880": {"BD", "Bangladesh"},
+	"886": {"TW", "Taiwan"},
+	"960": {"MV", "Maldives"},
+	"961": {"LB", "Lebanon"},
+	"962": {"JO", "Jordan"},
+	"963": {"SY", "Syria"},
+	"964": {"IQ", "Iraq"},
+	"965": {"KW", "Kuwait"},
+	"966": {"SA", "Saudi Arabia"},
+	"967": {"YE", "Yemen"},
+	"968": {"OM", "Oman"},
+	"970": {"PS", "Palestine"},
+	"971": {"AE", "United Arab Emirates"},
+	"972": {"IL", "Israel"},
+	"973": {"BH", "Bahrain"},
+	"974": {"QA", "Qatar"},
+	"975": {"BT", "Bhutan"},
+	"976": {"MN", "Mongolia"},
+	"977": {"NP", "Nepal"},
+	"992": {"TJ", "Tajikistan"},
+	"993": {"TM", "Turkmenistan"},
+	"994": {"AZ", "Azerbaijan"},
+	"995": {"GE", "Georgia"},
+	"996": {"KG", "Kyrgyzstan"},
+	"998": {"UZ", "Uzbekistan"},
+}
+
+// nanpAreaCodes maps the North American Numbering Plan area codes outside
+// the United States to their country or territory. Any other +1 area code
+// resolves to the United States.
+var nanpAreaCodes = map[string]country{
+	"204": {"CA", "Canada"},
+	"226": {"CA", "Canada"},
+	"236": {"CA", "Canada"},
+	"242": {"BS", "Bahamas"},
+	"246": {"BB", "Barbados"},
+	"249": {"CA", "Canada"},
+	"250": {"CA", "Canada"},
+	"257": {"CA", "Canada"},
+	"263": {"CA", "Canada"},
+	"264": {"AI", "Anguilla"},
+	"268": {"AG", "Antigua and Barbuda"},
+	"284": {"VG", "British Virgin Islands"},
+	"289": {"CA", "Canada"},
+	"306": {"CA", "Canada"},
+	"340": {"VI", "U.S. Virgin Islands"},
+	"343": {"CA", "Canada"},
+	"345": {"KY", "Cayman Islands"},
+	"354": {"CA", "Canada"},
+	"365": {"CA", "Canada"},
+	"367": {"CA", "Canada"},
+	"368": {"CA", "Canada"},
+	"382": {"CA", "Canada"},
+	"387": {"CA", "Canada"},
+	"403": {"CA", "Canada"},
+	"416": {"CA", "Canada"},
+	"418": {"CA", "Canada"},
+	"428": {"CA", "Canada"},
+	"431": {"CA", "Canada"},
+	"437": {"CA", "Canada"},
+	"438": {"CA", "Canada"},
+	"441": {"BM", "Bermuda"},
+	"450": {"CA", "Canada"},
+	"460": {"CA", "Canada"},
+	"468": {"CA", "Canada"},
+	"473": {"GD", "Grenada"},
+	"474": {"CA", "Canada"},
+	"506": {"CA", "Canada"},
+	"514": {"CA", "Canada"},
+	"519": {"CA", "Canada"},
+	"548": {"CA", "Canada"},
+	"579": {"CA", "Canada"},
+	"581": {"CA", "Canada"},
+	"584": {"CA", "Canada"},
+	"587": {"CA", "Canada"},
+	"600": {"CA", "Canada"},
+	"604": {"CA", "Canada"},
+	"613": {"CA", "Canada"},
+	"639": {"CA", "Canada"},
+	"647": {"CA", "Canada"},
+	"649": {"TC", "Turks and Caicos Islands"},
+	"658": {"JM", "Jamaica"},
+	"664": {"MS", "Montserrat"},
+	"670": {"MP", "Northern Mariana Islands"},
+	"671": {"GU", "Guam"},
+	"672": {"CA", "Canada"},
+	"683": {"CA", "Canada"},
+	"684": {"AS", "American Samoa"},
+	"705": {"CA", "Canada"},
+	"709": {"CA", "Canada"},
+	"721": {"SX", "Sint Maarten"},
+	"742": {"CA", "Canada"},
+	"753": {"CA", "Canada"},
+	"758": {"LC", "Saint Lucia"},
+	"767": {"DM", "Dominica"},
+	"778": {"CA", "Canada"},
+	"780": {"CA", "Canada"},
+	"782": {"CA", "Canada"},
+	"784": {"VC", "Saint Vincent and the Grenadines"},
+	"787": {"PR", "Puerto Rico"},
+	"807": {"CA", "Canada"},
+	"809": {"DO", "Dominican Republic"},
+	"819": {"CA", "Canada"},
+	"825": {"CA", "Canada"},
+	"829": {"DO", "Dominican Republic"},
+	"849": {"DO", "Dominican Republic"},
+	"867": {"CA", "Canada"},
+	"868": {"TT", "Trinidad and Tobago"},
+	"869": {"KN", "Saint Kitts and Nevis"},
+	"873": {"CA", "Canada"},
+	"876": {"JM", "Jamaica"},
+	"879": {"CA", "Canada"},
+	"902": {"CA", "Canada"},
+	"905": {"CA", "Canada"},
+	"939": {"PR", "Puerto Rico"},
+	"942": {"CA", "Canada"},
+}
+
+// countryNames indexes callingCodes and nanpAreaCodes by ISO code so
+// Meta-provided country codes can be named too.
+var countryNames = func() map[string]string {
+	names := make(map[string]string, len(callingCodes)+len(nanpAreaCodes))
+	for _, codes := range []map[string]country{callingCodes, nanpAreaCodes} {
+		for _, c := range codes {
+			names[c.iso] = c.name
+		}
+	}
+	return names
+}()
+
+// countryFromPhone resolves the sender country from an E.164 number (digits,
+// optionally with a leading +). The longest matching calling code wins; +1
+// numbers are resolved by their area code.
+func countryFromPhone(phone string) (string, string) {
+	phone = strings.TrimPrefix(strings.TrimSpace(phone), "+")
+	for _, r := range phone {
+		if r < '0' || r > '9' {
+			return "", ""
+		}
+	}
+	if strings.HasPrefix(phone, "1") && len(phone) == 11 {
+		if c, ok := nanpAreaCodes[phone[1:4]]; ok {
+			return c.iso, c.name
+		}
+	}
+	for n := 3; n >= 1; n-- {
+		if len(phone) <= n {
+			continue
+		}
+		if c, ok := callingCodes[phone[:n]]; ok {
+			return c.iso, c.name
+		}
+	}
+	return "", ""
+}
+
+// countryName returns the English name for an ISO 3166-1 alpha-2 code.
+func countryName(iso string) string {
+	return countryNames[strings.ToUpper(strings.TrimSpace(iso))]
+}
+
+// resolveCountry prefers the country code Meta attaches to the contact
+// profile and falls back to the sender's calling code.
+func resolveCountry(metaCountryCode, from string) (string, string) {
+	if iso := strings.ToUpper(strings.TrimSpace(metaCountryCode)); iso != "" {
+		return iso, countryName(iso)
+	}
+	return countryFromPhone(from)
+}
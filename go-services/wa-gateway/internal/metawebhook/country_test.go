@@ -0,0 +1,36 @@
+package metawebhook
+
+import "testing"
+
+func TestResolveCountry(t *testing.T) {
+	tests := []struct {
+		name     string
+		metaCode string
+		from     string
+		wantISO  string
+		wantName string
+	}{
+		{"meta code wins", "us", "593999111222", "US", "United States"},
+		{"ecuador from calling code", "", "593999111222", "EC", "Ecuador"},
+		{"leading plus", "", "+5215512345678", "MX", "Mexico"},
+		{"kazakhstan split from +7", "", "77011234567", "KZ", "Kazakhstan"},
+		{"russia", "", "79161234567", "RU", "Russia"},
+		{"united states", "", "14155550123", "US", "United States"},
+		{"canada by area code", "", "+14165550123", "CA", "Canada"},
+		{"puerto rico by area code", "", "17875550123", "PR", "Puerto Rico"},
+		{"dominican republic by area code", "", "18095550123", "DO", "Dominican Republic"},
+		{"jamaica by area code", "", "18765550123", "JM", "Jamaica"},
+		{"meta code for a nanp territory", "pr", "", "PR", "Puerto Rico"},
+		{"unassigned code", "", "999123456789", "", ""},
+		{"bsuid without phone", "", "", "", ""},
+		{"non digit", "", "EC.123456", "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			iso, name := resolveCountry(tt.metaCode, tt.from)
+			if iso != tt.wantISO || name != tt.wantName {
+				t.Fatalf("resolveCountry(%q, %q) = (%q, %q), want (%q, %q)", tt.metaCode, tt.from, iso, name, tt.wantISO, tt.wantName)
+			}
+		})
+	}
+}
@@ -0,0 +1,41 @@
+package metawebhook
+
+import "sync"
+
+// deliveryQueue orders reply delivery per conversation. Replies to a user's
+// second message must not interleave with, or overtake, the delayed replies
+// to the first one, so each delivery waits for the previous one of the same
+// account and user to finish.
+type deliveryQueue struct {
+	mu    sync.Mutex
+	tails map[string]chan struct{}
+}
+
+func newDeliveryQueue() *deliveryQueue {
+	return &deliveryQueue{tails: make(map[string]chan struct{})}
+}
+
+// enqueue reserves the next delivery slot of the conversation. prev is closed
+// once every earlier delivery has finished, or nil when there is none; done
+// must be called when this delivery finishes. Slots are handed out in the
+// order enqueue is called, so call it before starting the delivery goroutine.
+func (q *deliveryQueue) enqueue(accountID, userID string) (prev <-chan struct{}, done func()) {
+	key := metadataKey(accountID, userID)
+	cur := make(chan struct{})
+
+	q.mu.Lock()
+	if tail, ok := q.tails[key]; ok {
+		prev = tail
+	}
+	q.tails[key] = cur
+	q.mu.Unlock()
+
+	return prev, func() {
+		close(cur)
+		q.mu.Lock()
+		if q.tails[key] == cur {
+			delete(q.tails, key)
+		}
+		q.mu.Unlock()
+	}
+}
@@ -0,0 +1,94 @@
+package metawebhook
+
+import (
+	"sync"
+	"time"
+)
+
+// maxMetadataKeys bounds how many keys one conversation may hold.
+const maxMetadataKeys = 50
+
+type metadataEntry struct {
+	values    map[string]string
+	updatedAt time.Time
+}
+
+// conversationMetadata keeps the key/value pairs AI services attach to a
+// conversation via WebhookResponse.Metadata. It lives in memory and entries
+// expire ttl after their last update.
+type conversationMetadata struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	entries map[string]*metadataEntry
+}
+
+func newConversationMetadata(ttl time.Duration) *conversationMetadata {
+	return &conversationMetadata{
+		ttl:     ttl,
+		entries: make(map[string]*metadataEntry),
+	}
+}
+
+func metadataKey(accountID, userID string) string {
+	return accountID + "|" + userID
+}
+
+// get returns a copy of the conversation's metadata, or nil when none is
+// stored or it has expired.
+func (m *conversationMetadata) get(accountID, userID string, now time.Time) map[string]string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	entry, ok := m.entries[metadataKey(accountID, userID)]
+	if !ok || now.Sub(entry.updatedAt) >= m.ttl {
+		return nil
+	}
+	out := make(map[string]string, len(entry.values))
+	for key, value := range entry.values {
+		out[key] = value
+	}
+	return out
+}
+
+// merge upserts values into the conversation's metadata. An empty value
+// deletes the key. New keys beyond maxMetadataKeys are dropped.
+func (m *conversationMetadata) merge(accountID, userID string, values map[string]string, now time.Time) {
+	if userID == "" || len(values) == 0 {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	key := metadataKey(accountID, userID)
+	entry, ok := m.entries[key]
+	if !ok || now.Sub(entry.updatedAt) >= m.ttl {
+		entry = &metadataEntry{values: map[string]string{}}
+		m.entries[key] = entry
+	}
+	for k, v := range values {
+		if v == "" {
+			delete(entry.values, k)
+			continue
+		}
+		if _, exists := entry.values[k]; !exists && len(entry.values) >= maxMetadataKeys {
+			continue
+		}
+		entry.values[k] = v
+	}
+	entry.updatedAt = now
+	if len(entry.values) == 0 {
+		delete(m.entries, key)
+	}
+}
+
+// cleanup removes expired conversations.
+func (m *conversationMetadata) cleanup(now time.Time) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for key, entry := range m.entries {
+		if now.Sub(entry.updatedAt) >= m.ttl {
+			delete(m.entries, key)
+		}
+	}
+}
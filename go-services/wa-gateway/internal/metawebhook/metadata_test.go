@@ -0,0 +1,121 @@
+package metawebhook
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/tinkubot/wa-gateway/internal/webhook"
+)
+
+func TestConversationMetadataMergeAndExpiry(t *testing.T) {
+	now := time.Date(2025, 3, 10, 8, 0, 0, 0, time.UTC)
+	m := newConversationMetadata(time.Hour)
+
+	m.merge("bot-clientes", "593999111222", map[string]string{"crm_id": "42", "lang": "es"}, now)
+	m.merge("bot-clientes", "593999111222", map[string]string{"lang": "", "stage": "quote"}, now.Add(time.Minute))
+
+	got := m.get("bot-clientes", "593999111222", now.Add(2*time.Minute))
+	if len(got) != 2 || got["crm_id"] != "42" || got["stage"] != "quote" {
+		t.Fatalf("expected merged metadata without deleted key, got %v", got)
+	}
+	got["crm_id"] = "mutated"
+	if m.get("bot-clientes", "593999111222", now.Add(2*time.Minute))["crm_id"] != "42" {
+		t.Fatal("expected get to return a copy")
+	}
+	if other := m.get("bot-proveedores", "593999111222", now); other != nil {
+		t.Fatalf("expected metadata scoped per account, got %v", other)
+	}
+
+	if expired := m.get("bot-clientes", "593999111222", now.Add(time.Minute+time.Hour)); expired != nil {
+		t.Fatalf("expected metadata to expire after ttl, got %v", expired)
+	}
+	m.cleanup(now.Add(2 * time.Hour))
+	if len(m.entries) != 0 {
+		t.Fatalf("expected cleanup to drop expired conversations, got %d", len(m.entries))
+	}
+}
+
+func TestConversationMetadataCapsKeys(t *testing.T) {
+	now := time.Now()
+	m := newConversationMetadata(time.Hour)
+	values := map[string]string{}
+	for i := 0; i < maxMetadataKeys+10; i++ {
+		values[fmt.Sprintf("k%d", i)] = "v"
+	}
+	m.merge("bot-clientes", "593999111222", values, now)
+	if got := m.get("bot-clientes", "593999111222", now); len(got) != maxMetadataKeys {
+		t.Fatalf("expected %d keys, got %d", maxMetadataKeys, len(got))
+	}
+}
+
+func TestProcessEventEchoesPreviousMetadata(t *testing.T) {
+	fs := &fakeSender{resp: &webhook.WebhookResponse{
+		Success:  true,
+		Metadata: map[string]string{"crm_id": "42"},
+	}}
+	svc := NewService(Config{
+		Enabled:   true,
+		AppSecret: "secret-1",
+		PhoneNumberToAccount: map[string]string{
+			"123456789": "bot-clientes",
+		},
+	}, fs, nil, nil)
+
+	for i, text := range []string{"hola", "sigo aqui"} {
+		body := []byte(fmt.Sprintf(`{
+			"object":"whatsapp_business_account",
+			"entry":[{"id":"waba-1","changes":[{"field":"messages","value":{
+				"metadata":{"phone_number_id":"123456789"},
+				"messages":[{"from":"593999111222","id":"wamid.meta%d","timestamp":"1730000000","type":"text","text":{"body":%q}}]
+			}}]}]
+		}`, i, text))
+		if err := svc.ProcessEvent(context.Background(), buildSignature("secret-1", body), body); err != nil {
+			t.Fatalf("expected nil error, got %v", err)
+		}
+	}
+
+	if len(fs.payloads) != 2 {
+		t.Fatalf("expected 2 forwarded payloads, got %d", len(fs.payloads))
+	}
+	if fs.payloads[0].PreviousMetadata != nil {
+		t.Fatalf("expected no metadata on first message, got %v", fs.payloads[0].PreviousMetadata)
+	}
+	if fs.payloads[1].PreviousMetadata["crm_id"] != "42" {
+		t.Fatalf("expected crm_id echoed on second message, got %v", fs.payloads[1].PreviousMetadata)
+	}
+}
+
+func TestProcessEventMergesStreamedMetadata(t *testing.T) {
+	fs := &fakeStreamingSender{
+		replies: []webhook.StreamChunk{
+			{ResponseMessage: webhook.ResponseMessage{Response: "hola"}},
+			{Metadata: map[string]string{"crm_id": "42"}},
+		},
+	}
+	svc := NewService(Config{
+		Enabled:   true,
+		AppSecret: "secret-1",
+		PhoneNumberToAccount: map[string]string{
+			"123456789": "bot-clientes",
+		},
+	}, fs, nil, nil)
+
+	for i := 0; i < 2; i++ {
+		body := []byte(fmt.Sprintf(`{"object":"whatsapp_business_account","entry":[{"id":"waba-1","changes":[{"field":"messages","value":{"metadata":{"phone_number_id":"123456789"},"messages":[{"from":"593999111222","id":"wamid.stream%d","timestamp":"1730000000","type":"text","text":{"body":"hola"}}]}}]}]}`, i))
+		if err := svc.ProcessEvent(context.Background(), buildSignature("secret-1", body), body); err != nil {
+			t.Fatalf("expected nil error, got %v", err)
+		}
+		if err := svc.Close(context.Background()); err != nil {
+			t.Fatalf("expected streamed replies to finish, got %v", err)
+		}
+	}
+
+	if len(fs.payloads) != 2 {
+		t.Fatalf("expected 2 streamed payloads, got %d", len(fs.payloads))
+	}
+	if fs.payloads[1].PreviousMetadata["crm_id"] != "42" {
+		t.Fatalf("expected streamed crm_id echoed on second message, got %v", fs.payloads[1].PreviousMetadata)
+	}
+}
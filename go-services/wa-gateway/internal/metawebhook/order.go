@@ -0,0 +1,90 @@
+package metawebhook
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"math"
+	"strconv"
+	"strings"
+)
+
+type metaOrder struct {
+	CatalogID    string          `json:"catalog_id"`
+	Text         string          `json:"text,omitempty"`
+	ProductItems []metaOrderItem `json:"product_items"`
+}
+
+type metaOrderItem struct {
+	ProductRetailerID string     `json:"product_retailer_id"`
+	Quantity          metaNumber `json:"quantity"`
+	ItemPrice         metaNumber `json:"item_price"`
+	Currency          string     `json:"currency"`
+}
+
+// metaNumber accepts both JSON numbers and numeric strings; Meta documents
+// order quantities and prices as strings but sends numbers.
+type metaNumber float64
+
+func (n *metaNumber) UnmarshalJSON(data []byte) error {
+	raw := strings.Trim(strings.TrimSpace(string(data)), `"`)
+	if raw == "" || raw == "null" {
+		*n = 0
+		return nil
+	}
+	value, err := strconv.ParseFloat(raw, 64)
+	if err != nil {
+		return fmt.Errorf("invalid number %q: %w", raw, err)
+	}
+	*n = metaNumber(value)
+	return nil
+}
+
+// incomingOrder summarizes a cart sent from a WhatsApp Business catalog.
+type incomingOrder struct {
+	// OrderID is the message ID; the Cloud API does not assign order IDs.
+	OrderID     string
+	CatalogID   string
+	ItemCount   int
+	TotalAmount float64
+	Currency    string
+	Text        string
+	JSON        string
+}
+
+// extractOrder returns the order carried by msg, or nil for other messages.
+func extractOrder(msg metaMessage) *incomingOrder {
+	if msg.Type != "order" || msg.Order == nil {
+		return nil
+	}
+	order := &incomingOrder{
+		OrderID:   strings.TrimSpace(msg.ID),
+		CatalogID: strings.TrimSpace(msg.Order.CatalogID),
+		Text:      strings.TrimSpace(msg.Order.Text),
+	}
+	total := 0.0
+	for _, item := range msg.Order.ProductItems {
+		order.ItemCount += int(item.Quantity)
+		total += float64(item.Quantity) * float64(item.ItemPrice)
+		if order.Currency == "" {
+			order.Currency = strings.TrimSpace(item.Currency)
+		}
+	}
+	order.TotalAmount = math.Round(total*100) / 100
+
+	raw, err := json.Marshal(msg.Order)
+	if err != nil {
+		log.Printf("[MetaWebhook] order_marshal_failed message_id=%s err=%v", msg.ID, err)
+	} else {
+		order.JSON = string(raw)
+	}
+	return order
+}
+
+// content returns the buyer's note or a summary of the cart.
+func (o *incomingOrder) content() string {
+	if o.Text != "" {
+		return o.Text
+	}
+	return fmt.Sprintf("Order: %d items, total %.2f %s", o.ItemCount, o.TotalAmount, o.Currency)
+}
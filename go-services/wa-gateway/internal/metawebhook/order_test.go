@@ -0,0 +1,67 @@
+package metawebhook
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestExtractIncomingMessagesOrder(t *testing.T) {
+	tests := []struct {
+		name        string
+		order       string
+		wantItems   int
+		wantTotal   float64
+		wantContent string
+	}{
+		{
+			name:        "numeric fields",
+			order:       `{"catalog_id":"cat-1","product_items":[{"product_retailer_id":"sku-1","quantity":2,"item_price":12.5,"currency":"USD"},{"product_retailer_id":"sku-2","quantity":1,"item_price":3.1,"currency":"USD"}]}`,
+			wantItems:   3,
+			wantTotal:   28.1,
+			wantContent: "Order: 3 items, total 28.10 USD",
+		},
+		{
+			name:        "string fields and buyer note",
+			order:       `{"catalog_id":"cat-1","text":"Entregar en la tarde","product_items":[{"product_retailer_id":"sku-1","quantity":"3","item_price":"0.99","currency":"USD"}]}`,
+			wantItems:   3,
+			wantTotal:   2.97,
+			wantContent: "Entregar en la tarde",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			raw := `{"entry":[{"changes":[{"field":"messages","value":{"metadata":{"phone_number_id":"123"},"messages":[{"from":"593999111222","id":"wamid.order.1","type":"order","order":` + tt.order + `}]}}]}]}`
+			var evt webhookEvent
+			if err := json.Unmarshal([]byte(raw), &evt); err != nil {
+				t.Fatalf("unmarshal event: %v", err)
+			}
+
+			msgs := extractIncomingMessages(evt)
+			if len(msgs) != 1 {
+				t.Fatalf("expected 1 message, got %d", len(msgs))
+			}
+			msg := msgs[0]
+			if msg.MessageType != "order" || msg.Content != tt.wantContent {
+				t.Fatalf("expected order with content %q, got type=%q content=%q", tt.wantContent, msg.MessageType, msg.Content)
+			}
+			order := msg.Order
+			if order == nil {
+				t.Fatal("expected order to be extracted")
+			}
+			if order.OrderID != "wamid.order.1" || order.CatalogID != "cat-1" || order.Currency != "USD" {
+				t.Fatalf("unexpected order identity %+v", order)
+			}
+			if order.ItemCount != tt.wantItems || order.TotalAmount != tt.wantTotal {
+				t.Fatalf("expected %d items totalling %.2f, got %d totalling %.2f", tt.wantItems, tt.wantTotal, order.ItemCount, order.TotalAmount)
+			}
+			var cart metaOrder
+			if err := json.Unmarshal([]byte(order.JSON), &cart); err != nil {
+				t.Fatalf("expected order JSON to round-trip, got %v", err)
+			}
+			if cart.CatalogID != "cat-1" || len(cart.ProductItems) == 0 || cart.ProductItems[0].ProductRetailerID != "sku-1" {
+				t.Fatalf("expected full cart in order JSON, got %s", order.JSON)
+			}
+		})
+	}
+}
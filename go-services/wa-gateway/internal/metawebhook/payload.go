@@ -1,6 +1,7 @@
 package metawebhook
 
 import (
+	"fmt"
 	"log"
 	"strings"
 )
@@ -23,8 +24,9 @@ type change struct {
 type changeValue struct {
 	Metadata metaMetadata  `json:"metadata"`
 	Messages []metaMessage `json:"messages"`
-	Statuses []interface{} `json:"statuses"`
+	Statuses []metaStatus  `json:"statuses"`
 	Contacts []metaContact `json:"contacts"`
+	Calls    []metaCall    `json:"calls"`
 }
 
 type metaContact struct {
@@ -61,6 +63,7 @@ type metaMessage struct {
 	Audio       *metaAudio       `json:"audio,omitempty"`
 	Video       *metaVideo       `json:"video,omitempty"`
 	Button      *metaButton      `json:"button,omitempty"`
+	Order       *metaOrder       `json:"order,omitempty"`
 }
 
 type metaButton struct {
@@ -69,8 +72,17 @@ type metaButton struct {
 }
 
 type metaContext struct {
-	From string `json:"from,omitempty"`
-	ID   string `json:"id,omitempty"`
+	From                string `json:"from,omitempty"`
+	ID                  string `json:"id,omitempty"`
+	Forwarded           bool   `json:"forwarded,omitempty"`
+	FrequentlyForwarded bool   `json:"frequently_forwarded,omitempty"`
+	// ReferredProduct is set when the user messages about a catalog product.
+	ReferredProduct *metaReferredProduct `json:"referred_product,omitempty"`
+}
+
+type metaReferredProduct struct {
+	CatalogID         string `json:"catalog_id"`
+	ProductRetailerID string `json:"product_retailer_id"`
 }
 
 type metaText struct {
@@ -134,6 +146,7 @@ type incomingMessage struct {
 	MessageTS      string
 	ContextFrom    string
 	ContextID      string
+	IsForwarded    bool
 	Content        string
 	MessageType    string
 	SelectedOption string
@@ -143,12 +156,18 @@ type incomingMessage struct {
 	MediaMimetype  string
 	MediaFilename  string
 	// Contact profile fields
+	PushName      string
 	DisplayName   string
 	FormattedName string
 	FirstName     string
 	LastName      string
 	Username      string
 	CountryCode   string
+	// Catalog product the message refers to, if any
+	ProductID        string
+	CatalogID        string
+	BusinessOwnerJID string
+	Order            *incomingOrder
 }
 
 func extractIncomingMessages(evt webhookEvent) []incomingMessage {
@@ -190,6 +209,17 @@ func extractIncomingMessages(evt webhookEvent) []incomingMessage {
 					continue
 				}
 				content, messageType, selectedOption, flowPayload, location, media := extractMessageData(msg)
+				product := referredProduct(msg.Context)
+				order := extractOrder(msg)
+				if order != nil {
+					content, messageType = order.content(), "order"
+				}
+				if content == "" && product != nil {
+					content = productInquiryText(product)
+					if messageType == "" {
+						messageType = "text"
+					}
+				}
 				if content == "" && selectedOption == "" && flowPayload == nil && location == nil && media == nil {
 					log.Printf("[MetaWebhook] DEBUG message[%d] skipped: no extractable data (type=%s)", k, msg.Type)
 					continue
@@ -197,6 +227,7 @@ func extractIncomingMessages(evt webhookEvent) []incomingMessage {
 
 				// Extract contact info if available
 				var (
+					pushName      string
 					displayName   string
 					formattedName string
 					firstName     string
@@ -209,7 +240,8 @@ func extractIncomingMessages(evt webhookEvent) []incomingMessage {
 					formattedName = strings.TrimSpace(contact.Profile.FormattedName)
 					firstName = strings.TrimSpace(contact.Profile.FirstName)
 					lastName = strings.TrimSpace(contact.Profile.LastName)
-					displayName = strings.TrimSpace(contact.Profile.Name)
+					pushName = strings.TrimSpace(contact.Profile.Name)
+					displayName = pushName
 					if formattedName != "" {
 						displayName = formattedName
 					}
@@ -224,7 +256,8 @@ func extractIncomingMessages(evt webhookEvent) []incomingMessage {
 					formattedName = strings.TrimSpace(contact.Profile.FormattedName)
 					firstName = strings.TrimSpace(contact.Profile.FirstName)
 					lastName = strings.TrimSpace(contact.Profile.LastName)
-					displayName = strings.TrimSpace(contact.Profile.Name)
+					pushName = strings.TrimSpace(contact.Profile.Name)
+					displayName = pushName
 					if formattedName != "" {
 						displayName = formattedName
 					}
@@ -256,11 +289,13 @@ func extractIncomingMessages(evt webhookEvent) []incomingMessage {
 					MessageTS:      strings.TrimSpace(msg.Timestamp),
 					ContextFrom:    strings.TrimSpace(contextFrom(msg.Context)),
 					ContextID:      strings.TrimSpace(contextID(msg.Context)),
+					IsForwarded:    isForwarded(msg.Context),
 					Content:        content,
 					MessageType:    messageType,
 					SelectedOption: selectedOption,
 					FlowPayload:    flowPayload,
 					Location:       location,
+					PushName:       pushName,
 					DisplayName:    displayName,
 					FormattedName:  formattedName,
 					FirstName:      firstName,
@@ -268,6 +303,12 @@ func extractIncomingMessages(evt webhookEvent) []incomingMessage {
 					Username:       username,
 					CountryCode:    countryCode,
 				}
+				if product != nil {
+					entry.ProductID = strings.TrimSpace(product.ProductRetailerID)
+					entry.CatalogID = strings.TrimSpace(product.CatalogID)
+					entry.BusinessOwnerJID = buildFromNumber(msg.Context.From, "")
+				}
+				entry.Order = order
 				if media != nil {
 					entry.MediaID = media.ID
 					entry.MediaMimetype = media.MimeType
@@ -390,3 +431,30 @@ func contextID(ctx *metaContext) string {
 	}
 	return ctx.ID
 }
+
+func isForwarded(ctx *metaContext) bool {
+	if ctx == nil {
+		return false
+	}
+	return ctx.Forwarded || ctx.FrequentlyForwarded
+}
+
+// referredProduct returns the catalog product a message refers to, or nil.
+func referredProduct(ctx *metaContext) *metaReferredProduct {
+	if ctx == nil || ctx.ReferredProduct == nil {
+		return nil
+	}
+	if strings.TrimSpace(ctx.ReferredProduct.ProductRetailerID) == "" {
+		return nil
+	}
+	return ctx.ReferredProduct
+}
+
+// productInquiryText describes a product inquiry that arrived without text.
+func productInquiryText(product *metaReferredProduct) string {
+	productID := strings.TrimSpace(product.ProductRetailerID)
+	if catalogID := strings.TrimSpace(product.CatalogID); catalogID != "" {
+		return fmt.Sprintf("Product inquiry: %s (catalog %s)", productID, catalogID)
+	}
+	return "Product inquiry: " + productID
+}
@@ -0,0 +1,135 @@
+package metawebhook
+
+import (
+	"context"
+	"log"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/tinkubot/wa-gateway/internal/webhook"
+)
+
+// PaymentSender is implemented by senders that can forward WhatsApp Pay
+// payment events.
+type PaymentSender interface {
+	SendPayment(ctx context.Context, event *webhook.PaymentEvent) error
+}
+
+type metaStatus struct {
+	ID          string       `json:"id"`
+	RecipientID string       `json:"recipient_id"`
+	Type        string       `json:"type,omitempty"`
+	Status      string       `json:"status"`
+	Timestamp   string       `json:"timestamp"`
+	Payment     *metaPayment `json:"payment,omitempty"`
+}
+
+type metaPayment struct {
+	ReferenceID string                  `json:"reference_id"`
+	Amount      metaPaymentAmount       `json:"amount"`
+	Currency    string                  `json:"currency"`
+	Transaction *metaPaymentTransaction `json:"transaction,omitempty"`
+}
+
+// metaPaymentAmount is value/offset, e.g. {"value": 21000, "offset": 100}
+// for 210.00.
+type metaPaymentAmount struct {
+	Value  int64 `json:"value"`
+	Offset int64 `json:"offset"`
+}
+
+type metaPaymentTransaction struct {
+	ID     string `json:"id"`
+	Type   string `json:"type,omitempty"`
+	Status string `json:"status"`
+}
+
+type incomingPayment struct {
+	PhoneNumberID string
+	Event         webhook.PaymentEvent
+}
+
+func (a metaPaymentAmount) float() float64 {
+	if a.Offset <= 0 {
+		return float64(a.Value)
+	}
+	return math.Round(float64(a.Value)/float64(a.Offset)*100) / 100
+}
+
+// extractPaymentEvents returns the payment status updates in evt.
+func extractPaymentEvents(evt webhookEvent) []incomingPayment {
+	out := make([]incomingPayment, 0)
+	for _, e := range evt.Entry {
+		for _, ch := range e.Changes {
+			if ch.Field != "messages" {
+				continue
+			}
+			for _, status := range ch.Value.Statuses {
+				if status.Type != "payment" || status.Payment == nil {
+					continue
+				}
+				event := webhook.PaymentEvent{
+					Event:       webhook.PaymentEventReceived,
+					Phone:       strings.TrimSpace(status.RecipientID),
+					MessageID:   strings.TrimSpace(status.ID),
+					ReferenceID: strings.TrimSpace(status.Payment.ReferenceID),
+					Amount:      status.Payment.Amount.float(),
+					Currency:    strings.TrimSpace(status.Payment.Currency),
+					Status:      strings.TrimSpace(status.Status),
+					Timestamp:   strings.TrimSpace(status.Timestamp),
+				}
+				if tx := status.Payment.Transaction; tx != nil {
+					event.TransactionID = strings.TrimSpace(tx.ID)
+					event.TransactionStatus = strings.TrimSpace(tx.Status)
+				}
+				out = append(out, incomingPayment{
+					PhoneNumberID: ch.Value.Metadata.PhoneNumberID,
+					Event:         event,
+				})
+			}
+		}
+	}
+	return out
+}
+
+// forwardPayments sends payment events to the payments webhook. Events are
+// deduplicated per reference and status since Meta redelivers statuses.
+func (s *Service) forwardPayments(ctx context.Context, payments []incomingPayment) {
+	if len(payments) == 0 {
+		return
+	}
+	paymentSender, ok := s.sender.(PaymentSender)
+	if !ok {
+		log.Printf("[MetaWebhook] payment_events_dropped count=%d reason=sender_unsupported", len(payments))
+		return
+	}
+	for _, payment := range payments {
+		accountID, ok := s.cfg.PhoneNumberToAccount[payment.PhoneNumberID]
+		if !ok || accountID == "" {
+			log.Printf("[MetaWebhook] Unknown phone_number_id=%s, skipping payment", payment.PhoneNumberID)
+			continue
+		}
+		if len(s.cfg.EnabledAccounts) > 0 && !s.cfg.EnabledAccounts[accountID] {
+			continue
+		}
+		event := payment.Event
+		event.AccountID = accountID
+		dedupKey := "payment:" + event.ReferenceID + ":" + event.TransactionID + ":" + event.Status
+		if _, loaded := s.seenMessages.LoadOrStore(dedupKey, time.Now()); loaded {
+			log.Printf("[MetaWebhook] duplicate_payment_skipped reference_id=%s status=%s account=%s", event.ReferenceID, event.Status, accountID)
+			continue
+		}
+
+		log.Printf(
+			"[MetaWebhook] payment_received account=%s phone=%s reference_id=%s transaction_id=%s amount=%.2f currency=%s status=%s",
+			accountID, event.Phone, event.ReferenceID, event.TransactionID, event.Amount, event.Currency, event.Status,
+		)
+		sendCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+		err := paymentSender.SendPayment(sendCtx, &event)
+		cancel()
+		if err != nil {
+			log.Printf("[MetaWebhook] Failed forwarding payment account=%s reference_id=%s: %v", accountID, event.ReferenceID, err)
+		}
+	}
+}
@@ -10,6 +10,7 @@ import (
 	"errors"
 	"fmt"
 	"log"
+	"path"
 	"strings"
 	"sync"
 	"time"
@@ -29,16 +30,49 @@ type Sender interface {
 	Send(ctx context.Context, payload *webhook.WebhookPayload) (*webhook.WebhookResponse, error)
 }
 
+// SendTimeoutProvider is implemented by senders that know how long a Send,
+// retries included, may take for a payload.
+type SendTimeoutProvider interface {
+	SendTimeout(payload *webhook.WebhookPayload) time.Duration
+}
+
+// defaultForwardTimeout bounds a Send when the sender does not report its
+// own budget.
+const defaultForwardTimeout = 30 * time.Second
+
+// StreamingSender is implemented by senders that can relay AI replies as they
+// are produced instead of waiting for the full response.
+type StreamingSender interface {
+	SendStreaming(ctx context.Context, payload *webhook.WebhookPayload) (<-chan webhook.StreamChunk, error)
+}
+
 // OutboundSender abstracts outbound Meta Cloud API sends.
 type OutboundSender interface {
-	SendText(ctx context.Context, phoneNumberID, to, body string) error
-	SendImage(ctx context.Context, phoneNumberID, to, imageURL, caption string) error
-	SendContacts(ctx context.Context, phoneNumberID, to string, contacts []webhook.Contact) error
-	SendButtons(ctx context.Context, phoneNumberID, to, body string, ui webhook.UIConfig) error
-	SendList(ctx context.Context, phoneNumberID, to, body string, ui webhook.UIConfig) error
-	SendLocationRequest(ctx context.Context, phoneNumberID, to, body string) error
-	SendFlow(ctx context.Context, phoneNumberID, to, body string, ui webhook.UIConfig) error
-	SendTemplate(ctx context.Context, phoneNumberID, to string, ui webhook.UIConfig) error
+	SendText(ctx context.Context, phoneNumberID, to, body string) (string, error)
+	SendImage(ctx context.Context, phoneNumberID, to, imageURL, caption string) (string, error)
+	SendContacts(ctx context.Context, phoneNumberID, to string, contacts []webhook.Contact) (string, error)
+	SendButtons(ctx context.Context, phoneNumberID, to, body string, ui webhook.UIConfig) (string, error)
+	SendList(ctx context.Context, phoneNumberID, to, body string, ui webhook.UIConfig) (string, error)
+	SendLocationRequest(ctx context.Context, phoneNumberID, to, body string) (string, error)
+	SendFlow(ctx context.Context, phoneNumberID, to, body string, ui webhook.UIConfig) (string, error)
+	SendTemplate(ctx context.Context, phoneNumberID, to string, ui webhook.UIConfig) (string, error)
+}
+
+// LinkPreviewSender is implemented by outbound senders that can ask WhatsApp
+// to render a URL preview for a text message.
+type LinkPreviewSender interface {
+	SendLinkPreview(ctx context.Context, phoneNumberID, to, body string) (string, error)
+}
+
+// VideoSender is implemented by outbound senders that can deliver MP4 videos.
+type VideoSender interface {
+	SendVideo(ctx context.Context, phoneNumberID, to, videoURL, caption string) (string, error)
+}
+
+// DocumentSender is implemented by outbound senders that can deliver files
+// by URL as documents.
+type DocumentSender interface {
+	SendDocument(ctx context.Context, phoneNumberID, to, documentURL, filename, caption string) (string, error)
 }
 
 // MediaDownloader resolves inbound Meta media ids into raw bytes.
@@ -56,6 +90,22 @@ type Config struct {
 	LogRawInboundMaxLen  int
 	EnabledAccounts      map[string]bool
 	PhoneNumberToAccount map[string]string
+	MaxResponseDelay     time.Duration
+	// SessionRotation is the length of a conversation session window for
+	// WebhookPayload.SessionID. Defaults to 24h.
+	SessionRotation time.Duration
+	// MetadataTTL is how long conversation metadata set by AI services is
+	// kept after its last update. Defaults to 24h.
+	MetadataTTL time.Duration
+	// BatchWindow buffers messages per account and chat for this long and
+	// forwards them as one payload. Zero forwards every message immediately.
+	BatchWindow time.Duration
+	// AutoRejectCalls declines incoming WhatsApp calls before forwarding
+	// the call event.
+	AutoRejectCalls bool
+	// StreamTimeout bounds a streamed AI reply from the request until the
+	// last line. Zero uses the sender's forward timeout.
+	StreamTimeout time.Duration
 }
 
 // Service validates and processes Meta webhook events.
@@ -65,6 +115,12 @@ type Service struct {
 	outboundSender  OutboundSender
 	mediaDownloader MediaDownloader
 	seenMessages    sync.Map // message_id -> time.Time for dedup
+	metadata        *conversationMetadata
+	deliveries      *deliveryQueue
+	batcher         *messageBatcher
+	// background tracks delayed replies still being sent after the webhook
+	// request was answered.
+	background sync.WaitGroup
 }
 
 // Enabled reports whether webhook processing is active.
@@ -80,17 +136,32 @@ func NewService(cfg Config, sender Sender, outboundSender OutboundSender, mediaD
 	if cfg.PhoneNumberToAccount == nil {
 		cfg.PhoneNumberToAccount = map[string]string{}
 	}
+	if cfg.MaxResponseDelay <= 0 {
+		cfg.MaxResponseDelay = 5 * time.Second
+	}
+	if cfg.SessionRotation <= 0 {
+		cfg.SessionRotation = 24 * time.Hour
+	}
+	if cfg.MetadataTTL <= 0 {
+		cfg.MetadataTTL = 24 * time.Hour
+	}
 	svc := &Service{
 		cfg:             cfg,
 		sender:          sender,
 		outboundSender:  outboundSender,
 		mediaDownloader: mediaDownloader,
+		metadata:        newConversationMetadata(cfg.MetadataTTL),
+		deliveries:      newDeliveryQueue(),
+	}
+	if cfg.BatchWindow > 0 {
+		svc.batcher = newMessageBatcher(cfg.BatchWindow, svc.forwardBatch)
 	}
 	go svc.cleanupSeenMessages()
 	return svc
 }
 
-// cleanupSeenMessages removes stale entries from the dedup cache every 5 minutes.
+// cleanupSeenMessages removes stale entries from the dedup cache and expired
+// conversation metadata every 5 minutes.
 func (s *Service) cleanupSeenMessages() {
 	const ttl = 5 * time.Minute
 	ticker := time.NewTicker(ttl)
@@ -103,6 +174,7 @@ func (s *Service) cleanupSeenMessages() {
 			}
 			return true
 		})
+		s.metadata.cleanup(time.Now())
 	}
 }
 
@@ -245,18 +317,20 @@ func (s *Service) ProcessEvent(ctx context.Context, signature string, body []byt
 			)
 		}
 
+		fromJID := buildFromNumber(msg.From, msg.FromUserID)
 		payload := &webhook.WebhookPayload{
 			Phone:          userIdentifier, // BSUID with fallback to phone number
-			FromNumber:     buildFromNumber(msg.From, msg.FromUserID),
+			FromNumber:     fromJID,
 			UserID:         msg.FromUserID, // BSUID - may be empty for backwards compatibility
+			PushName:       msg.PushName,
 			DisplayName:    msg.DisplayName,
 			FormattedName:  msg.FormattedName,
 			FirstName:      msg.FirstName,
 			LastName:       msg.LastName,
 			Username:       msg.Username,
-			CountryCode:    msg.CountryCode,
 			ContextFrom:    msg.ContextFrom,
 			ContextID:      msg.ContextID,
+			IsForwarded:    msg.IsForwarded,
 			Content:        msg.Content,
 			Message:        msg.Content,
 			MessageType:    msg.MessageType,
@@ -264,8 +338,22 @@ func (s *Service) ProcessEvent(ctx context.Context, signature string, body []byt
 			FlowPayload:    msg.FlowPayload,
 			Timestamp:      time.Now().Format(time.RFC3339),
 			MessageID:      msg.MessageID,
+			ChatJID:        fromJID,
 			AccountID:      accountID,
+			SessionID:      buildSessionID(accountID, userIdentifier, time.Now(), s.cfg.SessionRotation),
+		}
+		payload.CountryCode, payload.CountryName = resolveCountry(msg.CountryCode, msg.From)
+		payload.PreviousMetadata = s.metadata.get(accountID, userIdentifier, time.Now())
+		payload.ProductID, payload.CatalogID, payload.BusinessOwnerJID = msg.ProductID, msg.CatalogID, msg.BusinessOwnerJID
+		if msg.Order != nil {
+			payload.OrderID = msg.Order.OrderID
+			payload.CatalogID = msg.Order.CatalogID
+			payload.ItemCount = msg.Order.ItemCount
+			payload.TotalAmount = msg.Order.TotalAmount
+			payload.Currency = msg.Order.Currency
+			payload.OrderJSON = msg.Order.JSON
 		}
+
 		if msg.Location != nil {
 			payload.Location = &webhook.LocationPayload{
 				Latitude:  msg.Location.Latitude,
@@ -299,42 +387,202 @@ func (s *Service) ProcessEvent(ctx context.Context, signature string, body []byt
 			}
 		}
 
-		log.Printf(
-			"[MetaWebhook] forwarding inbound_trace_id=%s account=%s destination=%s from=%s phone_number_id=%s message_type=%s selected_option=%q",
-			inboundTraceID,
-			accountID,
-			payload.AccountID,
-			msg.From,
-			msg.PhoneNumberID,
-			msg.MessageType,
-			msg.SelectedOption,
-		)
-		sendCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
-		resp, err := s.sender.Send(sendCtx, payload)
-		cancel()
-		if err != nil {
-			log.Printf("[MetaWebhook] Failed forwarding event inbound_trace_id=%s account=%s from=%s destination=%s: %v", inboundTraceID, accountID, msg.From, payload.AccountID, err)
+		if s.batcher != nil {
+			s.batcher.add(inboundTraceID, accountID, userIdentifier, msg, payload)
 			continue
 		}
+		s.forward(ctx, inboundTraceID, accountID, userIdentifier, msg, payload)
+	}
+
+	s.forwardPayments(ctx, extractPaymentEvents(evt))
+	s.forwardCalls(ctx, extractCallEvents(evt))
+	return nil
+}
+
+// forward sends payload to the AI service and dispatches its replies and
+// actions to the user.
+func (s *Service) forward(
+	ctx context.Context,
+	inboundTraceID, accountID, userIdentifier string,
+	msg incomingMessage,
+	payload *webhook.WebhookPayload,
+) {
+	log.Printf(
+		"[MetaWebhook] forwarding inbound_trace_id=%s account=%s destination=%s from=%s phone_number_id=%s message_type=%s selected_option=%q",
+		inboundTraceID,
+		accountID,
+		payload.AccountID,
+		msg.From,
+		msg.PhoneNumberID,
+		msg.MessageType,
+		msg.SelectedOption,
+	)
+	if s.forwardStreaming(ctx, inboundTraceID, accountID, userIdentifier, msg, payload) {
+		return
+	}
+	sendCtx, cancel := context.WithTimeout(ctx, s.forwardTimeout(payload))
+	resp, err := s.sender.Send(sendCtx, payload)
+	cancel()
+	if err != nil {
+		log.Printf("[MetaWebhook] Failed forwarding event inbound_trace_id=%s account=%s from=%s destination=%s: %v", inboundTraceID, accountID, msg.From, payload.AccountID, err)
+		return
+	}
+	log.Printf(
+		"[MetaWebhook] forwarding_ok inbound_trace_id=%s account=%s destination=%s from=%s success=%t outbound_messages=%d",
+		inboundTraceID,
+		accountID,
+		payload.AccountID,
+		msg.From,
+		resp.Success,
+		len(resp.Messages),
+	)
+	if !resp.Success {
+		log.Printf("[MetaWebhook] Downstream returned error inbound_trace_id=%s account=%s from=%s err=%s", inboundTraceID, accountID, msg.From, resp.Error)
+	}
+	s.metadata.merge(accountID, userIdentifier, resp.Metadata, time.Now())
+	if !s.cfg.OutboundEnabled {
+		return
+	}
+	outboundMessages := normalizeOutboundMessages(resp)
+	prev, done := s.deliveries.enqueue(accountID, userIdentifier)
+	if prev == nil && !hasResponseDelay(outboundMessages) {
+		defer done()
+		s.deliverResponse(ctx, accountID, msg, outboundMessages, resp.Actions)
+		return
+	}
+	// Delayed replies, and replies queued behind an earlier delivery to the
+	// same user, are sent after Meta's webhook request has been answered so
+	// the pauses do not hold it open.
+	s.background.Add(1)
+	go func() {
+		defer s.background.Done()
+		defer done()
+		if prev != nil {
+			<-prev
+		}
+		s.deliverResponse(context.WithoutCancel(ctx), accountID, msg, outboundMessages, resp.Actions)
+	}()
+}
+
+// deliverResponse sends the AI replies to the user, then runs its actions.
+func (s *Service) deliverResponse(
+	ctx context.Context,
+	accountID string,
+	msg incomingMessage,
+	messages []webhook.ResponseMessage,
+	actions []webhook.GatewayAction,
+) {
+	if len(messages) > 0 {
+		delayBudget := s.cfg.MaxResponseDelay
+		s.dispatchOutboundReplies(ctx, accountID, msg.PhoneNumberID, msg.From, messages, &delayBudget)
+	}
+	if len(actions) > 0 {
+		s.executeActions(ctx, accountID, msg, actions)
+	}
+}
+
+// Close forwards messages still held for batching and waits for them and for
+// delayed replies still being sent in the background, or until ctx ends.
+func (s *Service) Close(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		if s.batcher != nil {
+			s.batcher.close()
+		}
+		s.background.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// forwardTimeout is the deadline for forwarding payload, taken from the
+// sender's configured timeouts and retries when it reports them.
+func (s *Service) forwardTimeout(payload *webhook.WebhookPayload) time.Duration {
+	if provider, ok := s.sender.(SendTimeoutProvider); ok {
+		if timeout := provider.SendTimeout(payload); timeout > 0 {
+			return timeout
+		}
+	}
+	return defaultForwardTimeout
+}
+
+// forwardStreaming relays replies to the user as the AI service streams them,
+// reporting false when the sender cannot stream so the caller falls back to
+// Send. Only the request waits for the response headers; the replies are
+// read and sent in the background so Meta's webhook request is answered
+// without waiting for the whole stream.
+func (s *Service) forwardStreaming(
+	ctx context.Context,
+	inboundTraceID, accountID, userIdentifier string,
+	msg incomingMessage,
+	payload *webhook.WebhookPayload,
+) bool {
+	streamer, ok := s.sender.(StreamingSender)
+	if !ok {
+		return false
+	}
+	streamCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), s.streamTimeout(payload))
+
+	replies, err := streamer.SendStreaming(streamCtx, payload)
+	if errors.Is(err, webhook.ErrStreamingUnsupported) {
+		cancel()
+		return false
+	}
+	if err != nil {
+		cancel()
+		log.Printf("[MetaWebhook] Failed streaming event inbound_trace_id=%s account=%s from=%s: %v", inboundTraceID, accountID, msg.From, err)
+		return true
+	}
+
+	prev, done := s.deliveries.enqueue(accountID, userIdentifier)
+	s.background.Add(1)
+	go func() {
+		defer s.background.Done()
+		defer cancel()
+		defer done()
+		if prev != nil {
+			<-prev
+		}
+
+		count := 0
+		var actions []webhook.GatewayAction
+		delayBudget := s.cfg.MaxResponseDelay
+		for chunk := range replies {
+			actions = append(actions, chunk.Actions...)
+			s.metadata.merge(accountID, userIdentifier, chunk.Metadata, time.Now())
+			if !chunk.HasMessage() {
+				continue
+			}
+			count++
+			if s.cfg.OutboundEnabled {
+				s.dispatchOutboundReplies(streamCtx, accountID, msg.PhoneNumberID, msg.From, []webhook.ResponseMessage{chunk.ResponseMessage}, &delayBudget)
+			}
+		}
 		log.Printf(
-			"[MetaWebhook] forwarding_ok inbound_trace_id=%s account=%s destination=%s from=%s success=%t outbound_messages=%d",
+			"[MetaWebhook] forwarding_ok streaming=true inbound_trace_id=%s account=%s from=%s outbound_messages=%d",
 			inboundTraceID,
 			accountID,
-			payload.AccountID,
 			msg.From,
-			resp.Success,
-			len(resp.Messages),
+			count,
 		)
-		if !resp.Success {
-			log.Printf("[MetaWebhook] Downstream returned error inbound_trace_id=%s account=%s from=%s err=%s", inboundTraceID, accountID, msg.From, resp.Error)
-		}
-		outboundMessages := normalizeOutboundMessages(resp)
-		if len(outboundMessages) > 0 && s.cfg.OutboundEnabled {
-			s.dispatchOutboundReplies(ctx, accountID, msg.PhoneNumberID, msg.From, outboundMessages)
+		if len(actions) > 0 && s.cfg.OutboundEnabled {
+			s.executeActions(streamCtx, accountID, msg, actions)
 		}
-	}
+	}()
+	return true
+}
 
-	return nil
+// streamTimeout is the deadline for a streamed reply to payload.
+func (s *Service) streamTimeout(payload *webhook.WebhookPayload) time.Duration {
+	if s.cfg.StreamTimeout > 0 {
+		return s.cfg.StreamTimeout
+	}
+	return s.forwardTimeout(payload)
 }
 
 func redactInboundPayload(body []byte, maxLen int) string {
@@ -405,23 +653,29 @@ func shouldRedactKey(key string) bool {
 	}
 }
 
+// dispatchOutboundReplies sends messages to the user, waiting for each
+// reply's delay out of delayBudget.
 func (s *Service) dispatchOutboundReplies(
 	ctx context.Context,
 	accountID, phoneNumberID, to string,
 	messages []webhook.ResponseMessage,
+	delayBudget *time.Duration,
 ) {
 	if s.outboundSender == nil {
 		log.Printf("[MetaWebhook] Outbound enabled but sender is nil account=%s phone_number_id=%s", accountID, phoneNumberID)
 		return
 	}
 	for idx, reply := range messages {
+		if !s.waitResponseDelay(ctx, accountID, idx, reply.Delay, delayBudget) {
+			return
+		}
 		body := strings.TrimSpace(reply.Response)
 		imageURL := strings.TrimSpace(reply.MediaURL)
 		imageCaption := strings.TrimSpace(reply.MediaCaption)
 		mediaType := strings.ToLower(strings.TrimSpace(reply.MediaType))
 		if len(reply.Contacts) > 0 {
 			sendCtx, cancel := context.WithTimeout(ctx, 20*time.Second)
-			err := s.outboundSender.SendContacts(sendCtx, phoneNumberID, to, reply.Contacts)
+			_, err := s.outboundSender.SendContacts(sendCtx, phoneNumberID, to, reply.Contacts)
 			cancel()
 			if err != nil {
 				log.Printf("[MetaWebhook] Outbound contacts send failed account=%s phone_number_id=%s to=%s index=%d err=%v", accountID, phoneNumberID, to, idx, err)
@@ -432,12 +686,21 @@ func (s *Service) dispatchOutboundReplies(
 				continue
 			}
 		}
+		if mediaType == "link_preview" {
+			s.sendLinkPreview(ctx, accountID, phoneNumberID, to, idx, body, imageURL)
+			continue
+		}
+		if imageURL != "" && (mediaType == "video" || mediaType == "gif") {
+			if s.sendVideo(ctx, accountID, phoneNumberID, to, idx, imageURL, imageCaption, body) && reply.UI == nil {
+				continue
+			}
+		}
 		if imageURL != "" && (mediaType == "" || mediaType == "image") {
 			if imageCaption == "" {
 				imageCaption = body
 			}
 			sendCtx, cancel := context.WithTimeout(ctx, 20*time.Second)
-			err := s.outboundSender.SendImage(sendCtx, phoneNumberID, to, imageURL, imageCaption)
+			_, err := s.outboundSender.SendImage(sendCtx, phoneNumberID, to, imageURL, imageCaption)
 			cancel()
 			if err != nil {
 				log.Printf("[MetaWebhook] Outbound image send failed account=%s phone_number_id=%s to=%s index=%d err=%v", accountID, phoneNumberID, to, idx, err)
@@ -462,7 +725,7 @@ func (s *Service) dispatchOutboundReplies(
 					continue
 				}
 				sendCtx, cancel := context.WithTimeout(ctx, 20*time.Second)
-				err := s.outboundSender.SendButtons(sendCtx, phoneNumberID, to, body, *reply.UI)
+				_, err := s.outboundSender.SendButtons(sendCtx, phoneNumberID, to, body, *reply.UI)
 				cancel()
 				if err != nil {
 					log.Printf("[MetaWebhook] Outbound buttons send failed account=%s phone_number_id=%s to=%s index=%d err=%v", accountID, phoneNumberID, to, idx, err)
@@ -476,7 +739,7 @@ func (s *Service) dispatchOutboundReplies(
 					continue
 				}
 				sendCtx, cancel := context.WithTimeout(ctx, 20*time.Second)
-				err := s.outboundSender.SendList(sendCtx, phoneNumberID, to, body, *reply.UI)
+				_, err := s.outboundSender.SendList(sendCtx, phoneNumberID, to, body, *reply.UI)
 				cancel()
 				if err != nil {
 					log.Printf("[MetaWebhook] Outbound list send failed account=%s phone_number_id=%s to=%s index=%d err=%v", accountID, phoneNumberID, to, idx, err)
@@ -489,7 +752,7 @@ func (s *Service) dispatchOutboundReplies(
 					body = "Comparte tu ubicación para continuar."
 				}
 				sendCtx, cancel := context.WithTimeout(ctx, 20*time.Second)
-				err := s.outboundSender.SendLocationRequest(sendCtx, phoneNumberID, to, body)
+				_, err := s.outboundSender.SendLocationRequest(sendCtx, phoneNumberID, to, body)
 				cancel()
 				if err != nil {
 					log.Printf("[MetaWebhook] Outbound location request send failed account=%s phone_number_id=%s to=%s index=%d err=%v", accountID, phoneNumberID, to, idx, err)
@@ -499,7 +762,7 @@ func (s *Service) dispatchOutboundReplies(
 				continue
 			case "flow":
 				sendCtx, cancel := context.WithTimeout(ctx, 20*time.Second)
-				err := s.outboundSender.SendFlow(sendCtx, phoneNumberID, to, body, *reply.UI)
+				_, err := s.outboundSender.SendFlow(sendCtx, phoneNumberID, to, body, *reply.UI)
 				cancel()
 				if err != nil {
 					log.Printf("[MetaWebhook] Outbound flow send failed account=%s phone_number_id=%s to=%s index=%d err=%v", accountID, phoneNumberID, to, idx, err)
@@ -509,7 +772,7 @@ func (s *Service) dispatchOutboundReplies(
 				continue
 			case "template":
 				sendCtx, cancel := context.WithTimeout(ctx, 20*time.Second)
-				err := s.outboundSender.SendTemplate(sendCtx, phoneNumberID, to, *reply.UI)
+				_, err := s.outboundSender.SendTemplate(sendCtx, phoneNumberID, to, *reply.UI)
 				cancel()
 				if err != nil {
 					log.Printf("[MetaWebhook] Outbound template send failed account=%s phone_number_id=%s to=%s index=%d err=%v", accountID, phoneNumberID, to, idx, err)
@@ -526,7 +789,7 @@ func (s *Service) dispatchOutboundReplies(
 			continue
 		}
 		sendCtx, cancel := context.WithTimeout(ctx, 20*time.Second)
-		err := s.outboundSender.SendText(sendCtx, phoneNumberID, to, body)
+		_, err := s.outboundSender.SendText(sendCtx, phoneNumberID, to, body)
 		cancel()
 		if err != nil {
 			log.Printf("[MetaWebhook] Outbound send failed account=%s phone_number_id=%s to=%s index=%d err=%v", accountID, phoneNumberID, to, idx, err)
@@ -536,6 +799,118 @@ func (s *Service) dispatchOutboundReplies(
 	}
 }
 
+// sendLinkPreview sends body with a rendered preview of linkURL. The URL is
+// appended to body when missing, since WhatsApp previews the first URL in the
+// text. Senders without LinkPreviewSender fall back to plain text.
+func (s *Service) sendLinkPreview(ctx context.Context, accountID, phoneNumberID, to string, idx int, body, linkURL string) {
+	if linkURL != "" && !strings.Contains(body, linkURL) {
+		body = strings.TrimSpace(body + "\n" + linkURL)
+	}
+	if body == "" {
+		log.Printf("[MetaWebhook] Skipping empty link preview account=%s index=%d", accountID, idx)
+		return
+	}
+
+	sendCtx, cancel := context.WithTimeout(ctx, 20*time.Second)
+	defer cancel()
+	var err error
+	if previewer, ok := s.outboundSender.(LinkPreviewSender); ok {
+		_, err = previewer.SendLinkPreview(sendCtx, phoneNumberID, to, body)
+	} else {
+		_, err = s.outboundSender.SendText(sendCtx, phoneNumberID, to, body)
+	}
+	if err != nil {
+		log.Printf("[MetaWebhook] Outbound link preview send failed account=%s phone_number_id=%s to=%s index=%d err=%v", accountID, phoneNumberID, to, idx, err)
+		return
+	}
+	log.Printf("[MetaWebhook] Outbound link preview send ok account=%s phone_number_id=%s to=%s index=%d", accountID, phoneNumberID, to, idx)
+}
+
+// sendVideo sends videoURL with caption (or body when caption is empty). It
+// reports true when the reply's text was delivered as the caption. Cloud API
+// only plays animations delivered as MP4 and rejects GIF videos and images,
+// so GIF URLs are sent as documents instead.
+func (s *Service) sendVideo(ctx context.Context, accountID, phoneNumberID, to string, idx int, videoURL, caption, body string) bool {
+	if caption == "" {
+		caption = body
+	}
+	if urlPath := strings.SplitN(videoURL, "?", 2)[0]; strings.HasSuffix(strings.ToLower(urlPath), ".gif") {
+		return s.sendGIFDocument(ctx, accountID, phoneNumberID, to, idx, videoURL, path.Base(urlPath), caption, body)
+	}
+	videoSender, ok := s.outboundSender.(VideoSender)
+	if !ok {
+		log.Printf("[MetaWebhook] Outbound video unsupported by sender account=%s index=%d, fallback text", accountID, idx)
+		return false
+	}
+
+	sendCtx, cancel := context.WithTimeout(ctx, 20*time.Second)
+	_, err := videoSender.SendVideo(sendCtx, phoneNumberID, to, videoURL, caption)
+	cancel()
+	if err != nil {
+		log.Printf("[MetaWebhook] Outbound video send failed account=%s phone_number_id=%s to=%s index=%d err=%v", accountID, phoneNumberID, to, idx, err)
+		return false
+	}
+	log.Printf("[MetaWebhook] Outbound video send ok account=%s phone_number_id=%s to=%s index=%d", accountID, phoneNumberID, to, idx)
+	return caption == body || body == ""
+}
+
+// sendGIFDocument sends a GIF as a document, which WhatsApp shows as a file
+// rather than a looping video. It reports true when the reply's text was
+// delivered as the caption.
+func (s *Service) sendGIFDocument(ctx context.Context, accountID, phoneNumberID, to string, idx int, gifURL, filename, caption, body string) bool {
+	documentSender, ok := s.outboundSender.(DocumentSender)
+	if !ok {
+		log.Printf("[MetaWebhook] Outbound document unsupported by sender account=%s index=%d, fallback text", accountID, idx)
+		return false
+	}
+	sendCtx, cancel := context.WithTimeout(ctx, 20*time.Second)
+	_, err := documentSender.SendDocument(sendCtx, phoneNumberID, to, gifURL, filename, caption)
+	cancel()
+	if err != nil {
+		log.Printf("[MetaWebhook] Outbound GIF document send failed account=%s phone_number_id=%s to=%s index=%d err=%v", accountID, phoneNumberID, to, idx, err)
+		return false
+	}
+	log.Printf("[MetaWebhook] Outbound GIF sent as document account=%s phone_number_id=%s to=%s index=%d", accountID, phoneNumberID, to, idx)
+	return caption == body || body == ""
+}
+
+// waitResponseDelay sleeps for the reply's requested delay, capped at what
+// is left of budget, so the delays of one response add up to at most
+// MaxResponseDelay. It reports false if ctx ends first.
+func (s *Service) waitResponseDelay(ctx context.Context, accountID string, idx, delayMs int, budget *time.Duration) bool {
+	if delayMs <= 0 {
+		return true
+	}
+	delay := time.Duration(delayMs) * time.Millisecond
+	if delay > *budget {
+		log.Printf("[MetaWebhook] Response delay capped account=%s index=%d requested_ms=%d remaining_ms=%d max_ms=%d", accountID, idx, delayMs, budget.Milliseconds(), s.cfg.MaxResponseDelay.Milliseconds())
+		delay = *budget
+	}
+	*budget -= delay
+	if delay <= 0 {
+		return true
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return true
+	case <-ctx.Done():
+		log.Printf("[MetaWebhook] Outbound dispatch canceled during delay account=%s index=%d err=%v", accountID, idx, ctx.Err())
+		return false
+	}
+}
+
+// hasResponseDelay reports whether any reply asks to be delayed.
+func hasResponseDelay(messages []webhook.ResponseMessage) bool {
+	for _, msg := range messages {
+		if msg.Delay > 0 {
+			return true
+		}
+	}
+	return false
+}
+
 func normalizeOutboundMessages(resp *webhook.WebhookResponse) []webhook.ResponseMessage {
 	if resp == nil || len(resp.Messages) == 0 {
 		return nil
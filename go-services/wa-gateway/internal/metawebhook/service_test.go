@@ -6,8 +6,10 @@ import (
 	"crypto/sha256"
 	"encoding/hex"
 	"errors"
+	"fmt"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/tinkubot/wa-gateway/internal/webhook"
 )
@@ -29,6 +31,16 @@ func (f *fakeSender) Send(_ context.Context, payload *webhook.WebhookPayload) (*
 	return &webhook.WebhookResponse{Success: true}, nil
 }
 
+type fakePaymentSender struct {
+	fakeSender
+	payments []*webhook.PaymentEvent
+}
+
+func (f *fakePaymentSender) SendPayment(_ context.Context, event *webhook.PaymentEvent) error {
+	f.payments = append(f.payments, event)
+	return nil
+}
+
 type fakeOutboundSender struct {
 	requests []outboundRequest
 	err      error
@@ -70,9 +82,22 @@ func (f *fakeMediaDownloader) DownloadMedia(_ context.Context, phoneNumberID, me
 	return f.data, f.mimetype, f.filename, nil
 }
 
-func (f *fakeOutboundSender) SendText(_ context.Context, phoneNumberID, to, body string) error {
+func (f *fakeOutboundSender) SendLinkPreview(_ context.Context, phoneNumberID, to, body string) (string, error) {
+	if f.err != nil {
+		return "", f.err
+	}
+	f.requests = append(f.requests, outboundRequest{
+		kind:          "link_preview",
+		phoneNumberID: phoneNumberID,
+		to:            to,
+		body:          body,
+	})
+	return "", nil
+}
+
+func (f *fakeOutboundSender) SendText(_ context.Context, phoneNumberID, to, body string) (string, error) {
 	if f.err != nil {
-		return f.err
+		return "", f.err
 	}
 	f.requests = append(f.requests, outboundRequest{
 		kind:          "text",
@@ -80,12 +105,12 @@ func (f *fakeOutboundSender) SendText(_ context.Context, phoneNumberID, to, body
 		to:            to,
 		body:          body,
 	})
-	return nil
+	return "", nil
 }
 
-func (f *fakeOutboundSender) SendImage(_ context.Context, phoneNumberID, to, imageURL, caption string) error {
+func (f *fakeOutboundSender) SendImage(_ context.Context, phoneNumberID, to, imageURL, caption string) (string, error) {
 	if f.err != nil {
-		return f.err
+		return "", f.err
 	}
 	f.requests = append(f.requests, outboundRequest{
 		kind:          "image",
@@ -94,16 +119,45 @@ func (f *fakeOutboundSender) SendImage(_ context.Context, phoneNumberID, to, ima
 		imageURL:      imageURL,
 		imageCaption:  caption,
 	})
-	return nil
+	return "", nil
+}
+
+func (f *fakeOutboundSender) SendDocument(_ context.Context, phoneNumberID, to, documentURL, filename, caption string) (string, error) {
+	if f.err != nil {
+		return "", f.err
+	}
+	f.requests = append(f.requests, outboundRequest{
+		kind:          "document",
+		phoneNumberID: phoneNumberID,
+		to:            to,
+		body:          filename,
+		imageURL:      documentURL,
+		imageCaption:  caption,
+	})
+	return "", nil
+}
+
+func (f *fakeOutboundSender) SendVideo(_ context.Context, phoneNumberID, to, videoURL, caption string) (string, error) {
+	if f.err != nil {
+		return "", f.err
+	}
+	f.requests = append(f.requests, outboundRequest{
+		kind:          "video",
+		phoneNumberID: phoneNumberID,
+		to:            to,
+		imageURL:      videoURL,
+		imageCaption:  caption,
+	})
+	return "", nil
 }
 
 func (f *fakeOutboundSender) SendContacts(
 	_ context.Context,
 	phoneNumberID, to string,
 	contacts []webhook.Contact,
-) error {
+) (string, error) {
 	if f.err != nil {
-		return f.err
+		return "", f.err
 	}
 	f.requests = append(f.requests, outboundRequest{
 		kind:          "contacts",
@@ -111,16 +165,16 @@ func (f *fakeOutboundSender) SendContacts(
 		to:            to,
 		contacts:      contacts,
 	})
-	return nil
+	return "", nil
 }
 
 func (f *fakeOutboundSender) SendButtons(
 	_ context.Context,
 	phoneNumberID, to, body string,
 	ui webhook.UIConfig,
-) error {
+) (string, error) {
 	if f.err != nil {
-		return f.err
+		return "", f.err
 	}
 	copyUI := ui
 	f.requests = append(f.requests, outboundRequest{
@@ -131,16 +185,16 @@ func (f *fakeOutboundSender) SendButtons(
 		options:       ui.Options,
 		ui:            &copyUI,
 	})
-	return nil
+	return "", nil
 }
 
 func (f *fakeOutboundSender) SendList(
 	_ context.Context,
 	phoneNumberID, to, body string,
 	ui webhook.UIConfig,
-) error {
+) (string, error) {
 	if f.err != nil {
-		return f.err
+		return "", f.err
 	}
 	copyUI := ui
 	f.requests = append(f.requests, outboundRequest{
@@ -151,15 +205,15 @@ func (f *fakeOutboundSender) SendList(
 		options:       ui.Options,
 		ui:            &copyUI,
 	})
-	return nil
+	return "", nil
 }
 
 func (f *fakeOutboundSender) SendLocationRequest(
 	_ context.Context,
 	phoneNumberID, to, body string,
-) error {
+) (string, error) {
 	if f.err != nil {
-		return f.err
+		return "", f.err
 	}
 	f.requests = append(f.requests, outboundRequest{
 		kind:          "location_request",
@@ -167,16 +221,16 @@ func (f *fakeOutboundSender) SendLocationRequest(
 		to:            to,
 		body:          body,
 	})
-	return nil
+	return "", nil
 }
 
 func (f *fakeOutboundSender) SendFlow(
 	_ context.Context,
 	phoneNumberID, to, body string,
 	ui webhook.UIConfig,
-) error {
+) (string, error) {
 	if f.err != nil {
-		return f.err
+		return "", f.err
 	}
 	copyUI := ui
 	f.requests = append(f.requests, outboundRequest{
@@ -186,16 +240,16 @@ func (f *fakeOutboundSender) SendFlow(
 		body:          body,
 		ui:            &copyUI,
 	})
-	return nil
+	return "", nil
 }
 
 func (f *fakeOutboundSender) SendTemplate(
 	_ context.Context,
 	phoneNumberID, to string,
 	ui webhook.UIConfig,
-) error {
+) (string, error) {
 	if f.err != nil {
-		return f.err
+		return "", f.err
 	}
 	copyUI := ui
 	f.requests = append(f.requests, outboundRequest{
@@ -204,7 +258,7 @@ func (f *fakeOutboundSender) SendTemplate(
 		to:            to,
 		ui:            &copyUI,
 	})
-	return nil
+	return "", nil
 }
 
 func TestVerifyChallenge(t *testing.T) {
@@ -284,6 +338,12 @@ func TestProcessEventValidSignatureRoutesToClientes(t *testing.T) {
 	if got.MessageType != "text" {
 		t.Fatalf("expected message_type text, got %s", got.MessageType)
 	}
+	if got.MessageID != "wamid.1" {
+		t.Fatalf("expected id wamid.1, got %s", got.MessageID)
+	}
+	if got.ChatJID != "593999111222@s.whatsapp.net" {
+		t.Fatalf("expected chat_jid 593999111222@s.whatsapp.net, got %s", got.ChatJID)
+	}
 }
 
 func TestProcessEventInteractiveButtonReply(t *testing.T) {
@@ -431,6 +491,73 @@ func TestProcessEventCapturesReplyContext(t *testing.T) {
 	}
 }
 
+func TestProcessEventCapturesReferredProduct(t *testing.T) {
+	fs := &fakeSender{}
+	svc := NewService(Config{
+		Enabled:   true,
+		AppSecret: "secret-1",
+		PhoneNumberToAccount: map[string]string{
+			"123456789": "bot-clientes",
+		},
+	}, fs, nil, nil)
+
+	body := []byte(`{
+		"object":"whatsapp_business_account",
+		"entry":[
+			{
+				"id":"waba-1",
+				"changes":[
+					{
+						"field":"messages",
+						"value":{
+							"metadata":{"phone_number_id":"123456789"},
+							"messages":[
+								{
+									"from":"593999111222",
+									"id":"wamid.product.1",
+									"timestamp":"1730000001",
+									"context":{"from":"593111222333","id":"wamid.catalog.1","referred_product":{"catalog_id":"cat-1","product_retailer_id":"sku-42"}},
+									"type":"text",
+									"text":{"body":"¿Tienen este en azul?"}
+								},
+								{
+									"from":"593999111222",
+									"id":"wamid.product.2",
+									"timestamp":"1730000002",
+									"context":{"from":"593111222333","id":"wamid.catalog.1","referred_product":{"catalog_id":"cat-1","product_retailer_id":"sku-42"}},
+									"type":"unsupported"
+								}
+							]
+						}
+					}
+				]
+			}
+		]
+	}`)
+	sig := buildSignature("secret-1", body)
+
+	if err := svc.ProcessEvent(context.Background(), sig, body); err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	if len(fs.payloads) != 2 {
+		t.Fatalf("expected 2 forwarded payloads, got %d", len(fs.payloads))
+	}
+	got := fs.payloads[0]
+	if got.ProductID != "sku-42" || got.CatalogID != "cat-1" {
+		t.Fatalf("expected product sku-42 in catalog cat-1, got product=%q catalog=%q", got.ProductID, got.CatalogID)
+	}
+	if got.BusinessOwnerJID != "593111222333@s.whatsapp.net" {
+		t.Fatalf("expected business owner jid 593111222333@s.whatsapp.net, got %q", got.BusinessOwnerJID)
+	}
+	if got.Message != "¿Tienen este en azul?" {
+		t.Fatalf("expected user text to be kept, got %q", got.Message)
+	}
+	fallback := fs.payloads[1]
+	if fallback.Message != "Product inquiry: sku-42 (catalog cat-1)" || fallback.MessageType != "text" {
+		t.Fatalf("expected descriptive fallback text, got message=%q type=%q", fallback.Message, fallback.MessageType)
+	}
+}
+
 func TestProcessEventInteractiveButtonReplyFallbackTitle(t *testing.T) {
 	fs := &fakeSender{}
 	svc := NewService(Config{
@@ -1646,3 +1773,539 @@ func TestProcessEventVideoMessage(t *testing.T) {
 		t.Fatalf("expected media_mimetype video/mp4, got %s", got.MediaMimetype)
 	}
 }
+
+type fakeStreamingSender struct {
+	fakeSender
+	replies   []webhook.StreamChunk
+	streamErr error
+	// release, when set, holds the replies back until it is closed.
+	release  chan struct{}
+	deadline time.Time
+}
+
+func (f *fakeStreamingSender) SendStreaming(ctx context.Context, payload *webhook.WebhookPayload) (<-chan webhook.StreamChunk, error) {
+	if f.streamErr != nil {
+		return nil, f.streamErr
+	}
+	f.payloads = append(f.payloads, payload)
+	f.deadline, _ = ctx.Deadline()
+	out := make(chan webhook.StreamChunk)
+	go func() {
+		defer close(out)
+		if f.release != nil {
+			<-f.release
+		}
+		for _, reply := range f.replies {
+			select {
+			case out <- reply:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return out, nil
+}
+
+func TestProcessEventStreamsRepliesWhenSenderSupportsIt(t *testing.T) {
+	fs := &fakeStreamingSender{
+		replies: []webhook.StreamChunk{
+			{ResponseMessage: webhook.ResponseMessage{Response: "parte 1"}},
+			{ResponseMessage: webhook.ResponseMessage{Response: "parte 2"}},
+		},
+	}
+	fo := &fakeOutboundSender{}
+	svc := NewService(Config{
+		Enabled:         true,
+		AppSecret:       "secret-1",
+		OutboundEnabled: true,
+		PhoneNumberToAccount: map[string]string{
+			"123456789": "bot-clientes",
+		},
+	}, fs, fo, nil)
+
+	body := []byte(`{"object":"whatsapp_business_account","entry":[{"id":"waba-1","changes":[{"field":"messages","value":{"metadata":{"phone_number_id":"123456789"},"messages":[{"from":"593999111222","id":"wamid.stream","timestamp":"1730000000","type":"text","text":{"body":"hola"}}]}}]}]}`)
+	if err := svc.ProcessEvent(context.Background(), buildSignature("secret-1", body), body); err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	if err := svc.Close(context.Background()); err != nil {
+		t.Fatalf("expected streamed replies to finish, got %v", err)
+	}
+	if len(fs.payloads) != 1 {
+		t.Fatalf("expected 1 streamed payload, got %d", len(fs.payloads))
+	}
+	if len(fo.requests) != 2 || fo.requests[0].body != "parte 1" || fo.requests[1].body != "parte 2" {
+		t.Fatalf("unexpected outbound requests: %+v", fo.requests)
+	}
+}
+
+func TestProcessEventAnswersBeforeStreamEnds(t *testing.T) {
+	fs := &fakeStreamingSender{
+		replies: []webhook.StreamChunk{{ResponseMessage: webhook.ResponseMessage{Response: "tarde"}}},
+		release: make(chan struct{}),
+	}
+	fo := &fakeOutboundSender{}
+	svc := NewService(Config{
+		Enabled:         true,
+		AppSecret:       "secret-1",
+		OutboundEnabled: true,
+		StreamTimeout:   30 * time.Second,
+		PhoneNumberToAccount: map[string]string{
+			"123456789": "bot-clientes",
+		},
+	}, fs, fo, nil)
+
+	body := []byte(`{"object":"whatsapp_business_account","entry":[{"id":"waba-1","changes":[{"field":"messages","value":{"metadata":{"phone_number_id":"123456789"},"messages":[{"from":"593999111222","id":"wamid.stream-slow","timestamp":"1730000000","type":"text","text":{"body":"hola"}}]}}]}]}`)
+	ctx, cancel := context.WithCancel(context.Background())
+	if err := svc.ProcessEvent(ctx, buildSignature("secret-1", body), body); err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	// Meta's request context ends once the webhook is answered.
+	cancel()
+	if len(fo.requests) != 0 {
+		t.Fatalf("expected no reply before the stream produces one, got %+v", fo.requests)
+	}
+	if remaining := time.Until(fs.deadline); remaining <= 29*time.Second || remaining > 30*time.Second {
+		t.Fatalf("expected the stream deadline from StreamTimeout, got %s", remaining)
+	}
+
+	close(fs.release)
+	if err := svc.Close(context.Background()); err != nil {
+		t.Fatalf("expected streamed replies to finish, got %v", err)
+	}
+	if len(fo.requests) != 1 || fo.requests[0].body != "tarde" {
+		t.Fatalf("expected the streamed reply after the webhook was answered, got %+v", fo.requests)
+	}
+}
+
+func TestProcessEventFallsBackToSendWhenStreamingUnsupported(t *testing.T) {
+	fs := &fakeStreamingSender{streamErr: webhook.ErrStreamingUnsupported}
+	fs.resp = &webhook.WebhookResponse{
+		Success:  true,
+		Messages: []webhook.ResponseMessage{{Response: "respuesta"}},
+	}
+	fo := &fakeOutboundSender{}
+	svc := NewService(Config{
+		Enabled:         true,
+		AppSecret:       "secret-1",
+		OutboundEnabled: true,
+		PhoneNumberToAccount: map[string]string{
+			"123456789": "bot-clientes",
+		},
+	}, fs, fo, nil)
+
+	body := []byte(`{"object":"whatsapp_business_account","entry":[{"id":"waba-1","changes":[{"field":"messages","value":{"metadata":{"phone_number_id":"123456789"},"messages":[{"from":"593999111222","id":"wamid.fallback","timestamp":"1730000000","type":"text","text":{"body":"hola"}}]}}]}]}`)
+	if err := svc.ProcessEvent(context.Background(), buildSignature("secret-1", body), body); err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	if len(fs.payloads) != 1 {
+		t.Fatalf("expected fallback Send, got %d payloads", len(fs.payloads))
+	}
+	if len(fo.requests) != 1 || fo.requests[0].body != "respuesta" {
+		t.Fatalf("unexpected outbound requests: %+v", fo.requests)
+	}
+}
+
+func TestProcessEventCapturesForwardedFlagAndPushName(t *testing.T) {
+	fs := &fakeSender{}
+	svc := NewService(Config{
+		Enabled:   true,
+		AppSecret: "secret-1",
+		PhoneNumberToAccount: map[string]string{
+			"123456789": "bot-clientes",
+		},
+	}, fs, nil, nil)
+
+	body := []byte(`{
+		"object":"whatsapp_business_account",
+		"entry":[
+			{
+				"id":"waba-1",
+				"changes":[
+					{
+						"field":"messages",
+						"value":{
+							"metadata":{"phone_number_id":"123456789"},
+							"contacts":[{"profile":{"name":"Ana","formatted_name":"Ana Pérez"},"wa_id":"593999111222"}],
+							"messages":[{
+								"from":"593999111222",
+								"id":"wamid.fwd.1",
+								"timestamp":"1730000001",
+								"context":{"forwarded":true},
+								"type":"text",
+								"text":{"body":"mira esto"}
+							}]
+						}
+					}
+				]
+			}
+		]
+	}`)
+	sig := buildSignature("secret-1", body)
+
+	if err := svc.ProcessEvent(context.Background(), sig, body); err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	if len(fs.payloads) != 1 {
+		t.Fatalf("expected 1 forwarded payload, got %d", len(fs.payloads))
+	}
+	got := fs.payloads[0]
+	if !got.IsForwarded {
+		t.Fatalf("expected is_forwarded true")
+	}
+	if got.PushName != "Ana" {
+		t.Fatalf("expected push_name Ana, got %q", got.PushName)
+	}
+	if got.DisplayName != "Ana Pérez" {
+		t.Fatalf("expected display_name Ana Pérez, got %q", got.DisplayName)
+	}
+}
+
+func (f *fakeOutboundSender) MarkRead(_ context.Context, phoneNumberID, messageID string) error {
+	f.requests = append(f.requests, outboundRequest{kind: "mark_read", phoneNumberID: phoneNumberID, body: messageID})
+	return f.err
+}
+
+func (f *fakeOutboundSender) SendTypingIndicator(_ context.Context, phoneNumberID, messageID string) error {
+	f.requests = append(f.requests, outboundRequest{kind: "typing", phoneNumberID: phoneNumberID, body: messageID})
+	return f.err
+}
+
+func (f *fakeOutboundSender) BlockUser(_ context.Context, phoneNumberID, user string) error {
+	f.requests = append(f.requests, outboundRequest{kind: "block", phoneNumberID: phoneNumberID, to: user})
+	return f.err
+}
+
+func TestProcessEventExecutesResponseActionsAfterReplies(t *testing.T) {
+	fs := &fakeSender{
+		resp: &webhook.WebhookResponse{
+			Success:  true,
+			Messages: []webhook.ResponseMessage{{Response: "listo"}},
+			Actions: []webhook.GatewayAction{
+				{Type: "mark_read"},
+				{Type: "send_typing", Params: map[string]any{"message_id": "wamid.other"}},
+				{Type: "add_to_blocklist"},
+				{Type: "set_conversation_state", Params: map[string]any{"state": "idle"}},
+				{Type: "unknown"},
+			},
+		},
+	}
+	fo := &fakeOutboundSender{}
+	svc := NewService(Config{
+		Enabled:         true,
+		AppSecret:       "secret-1",
+		OutboundEnabled: true,
+		PhoneNumberToAccount: map[string]string{
+			"123456789": "bot-clientes",
+		},
+	}, fs, fo, nil)
+
+	body := []byte(`{"object":"whatsapp_business_account","entry":[{"id":"waba-1","changes":[{"field":"messages","value":{"metadata":{"phone_number_id":"123456789"},"messages":[{"from":"593999111222","id":"wamid.actions","timestamp":"1730000000","type":"text","text":{"body":"hola"}}]}}]}]}`)
+	if err := svc.ProcessEvent(context.Background(), buildSignature("secret-1", body), body); err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	if len(fo.requests) != 4 {
+		t.Fatalf("expected reply + 3 actions, got %+v", fo.requests)
+	}
+	if fo.requests[0].kind != "text" {
+		t.Fatalf("expected reply to be sent before actions, got %+v", fo.requests[0])
+	}
+	if fo.requests[1].kind != "mark_read" || fo.requests[1].body != "wamid.actions" {
+		t.Fatalf("unexpected mark_read request: %+v", fo.requests[1])
+	}
+	if fo.requests[2].kind != "typing" || fo.requests[2].body != "wamid.other" {
+		t.Fatalf("unexpected typing request: %+v", fo.requests[2])
+	}
+	if fo.requests[3].kind != "block" || fo.requests[3].to != "593999111222" {
+		t.Fatalf("unexpected block request: %+v", fo.requests[3])
+	}
+}
+
+func TestProcessEventExecutesStreamedActions(t *testing.T) {
+	fs := &fakeStreamingSender{
+		replies: []webhook.StreamChunk{
+			{ResponseMessage: webhook.ResponseMessage{Response: "listo"}},
+			{Actions: []webhook.GatewayAction{{Type: "mark_read"}}},
+		},
+	}
+	fo := &fakeOutboundSender{}
+	svc := NewService(Config{
+		Enabled:         true,
+		AppSecret:       "secret-1",
+		OutboundEnabled: true,
+		PhoneNumberToAccount: map[string]string{
+			"123456789": "bot-clientes",
+		},
+	}, fs, fo, nil)
+
+	body := []byte(`{"object":"whatsapp_business_account","entry":[{"id":"waba-1","changes":[{"field":"messages","value":{"metadata":{"phone_number_id":"123456789"},"messages":[{"from":"593999111222","id":"wamid.stream-actions","timestamp":"1730000000","type":"text","text":{"body":"hola"}}]}}]}]}`)
+	if err := svc.ProcessEvent(context.Background(), buildSignature("secret-1", body), body); err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	if err := svc.Close(context.Background()); err != nil {
+		t.Fatalf("expected streamed replies to finish, got %v", err)
+	}
+	if len(fo.requests) != 2 {
+		t.Fatalf("expected reply + mark_read, got %+v", fo.requests)
+	}
+	if fo.requests[0].kind != "text" || fo.requests[0].body != "listo" {
+		t.Fatalf("expected streamed reply first, got %+v", fo.requests[0])
+	}
+	if fo.requests[1].kind != "mark_read" || fo.requests[1].body != "wamid.stream-actions" {
+		t.Fatalf("unexpected mark_read request: %+v", fo.requests[1])
+	}
+}
+
+func TestProcessEventAppliesCappedResponseDelay(t *testing.T) {
+	fs := &fakeSender{
+		resp: &webhook.WebhookResponse{
+			Success: true,
+			Messages: []webhook.ResponseMessage{
+				{Response: "primero"},
+				{Response: "segundo", Delay: 60000},
+				{Response: "tercero", Delay: 60000},
+			},
+		},
+	}
+	fo := &fakeOutboundSender{}
+	svc := NewService(Config{
+		Enabled:          true,
+		AppSecret:        "secret-1",
+		OutboundEnabled:  true,
+		MaxResponseDelay: 200 * time.Millisecond,
+		PhoneNumberToAccount: map[string]string{
+			"123456789": "bot-clientes",
+		},
+	}, fs, fo, nil)
+
+	body := []byte(`{"object":"whatsapp_business_account","entry":[{"id":"waba-1","changes":[{"field":"messages","value":{"metadata":{"phone_number_id":"123456789"},"messages":[{"from":"593999111222","id":"wamid.delay","timestamp":"1730000000","type":"text","text":{"body":"hola"}}]}}]}]}`)
+	start := time.Now()
+	if err := svc.ProcessEvent(context.Background(), buildSignature("secret-1", body), body); err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	if acked := time.Since(start); acked >= 200*time.Millisecond {
+		t.Fatalf("expected webhook to be answered before the delay, took %s", acked)
+	}
+	if err := svc.Close(context.Background()); err != nil {
+		t.Fatalf("expected pending replies to finish, got %v", err)
+	}
+	elapsed := time.Since(start)
+
+	if len(fo.requests) != 3 {
+		t.Fatalf("expected 3 outbound sends, got %d", len(fo.requests))
+	}
+	if elapsed < 200*time.Millisecond {
+		t.Fatalf("expected delay to be applied, elapsed=%s", elapsed)
+	}
+	if elapsed >= 400*time.Millisecond {
+		t.Fatalf("expected delays capped in total at 200ms, elapsed=%s", elapsed)
+	}
+}
+
+func TestProcessEventKeepsRepliesInOrderPerConversation(t *testing.T) {
+	fs := &fakeSender{
+		resp: &webhook.WebhookResponse{
+			Success: true,
+			Messages: []webhook.ResponseMessage{
+				{Response: "primero"},
+				{Response: "segundo", Delay: 100},
+				{Response: "tercero", Delay: 100},
+			},
+		},
+	}
+	fo := &fakeOutboundSender{}
+	svc := NewService(Config{
+		Enabled:          true,
+		AppSecret:        "secret-1",
+		OutboundEnabled:  true,
+		MaxResponseDelay: time.Second,
+		PhoneNumberToAccount: map[string]string{
+			"123456789": "bot-clientes",
+		},
+	}, fs, fo, nil)
+
+	for i, next := range []*webhook.WebhookResponse{
+		{Success: true, Messages: []webhook.ResponseMessage{{Response: "cuarto"}}},
+		nil,
+	} {
+		body := []byte(fmt.Sprintf(`{"object":"whatsapp_business_account","entry":[{"id":"waba-1","changes":[{"field":"messages","value":{"metadata":{"phone_number_id":"123456789"},"messages":[{"from":"593999111222","id":"wamid.order%d","timestamp":"1730000000","type":"text","text":{"body":"hola"}}]}}]}]}`, i))
+		if err := svc.ProcessEvent(context.Background(), buildSignature("secret-1", body), body); err != nil {
+			t.Fatalf("expected nil error, got %v", err)
+		}
+		fs.resp = next
+	}
+	if err := svc.Close(context.Background()); err != nil {
+		t.Fatalf("expected pending replies to finish, got %v", err)
+	}
+
+	var got []string
+	for _, req := range fo.requests {
+		got = append(got, req.body)
+	}
+	if strings.Join(got, ",") != "primero,segundo,tercero,cuarto" {
+		t.Fatalf("expected the second reply after the delayed first one, got %v", got)
+	}
+}
+
+func TestProcessEventSendsLinkPreviewReply(t *testing.T) {
+	fs := &fakeSender{
+		resp: &webhook.WebhookResponse{
+			Success: true,
+			Messages: []webhook.ResponseMessage{
+				{Response: "Revisa los detalles aquí", MediaType: "link_preview", MediaURL: "https://tinkubot.com/servicios"},
+				{Response: "Mira https://tinkubot.com/ayuda", MediaType: "link_preview", MediaURL: "https://tinkubot.com/ayuda"},
+			},
+		},
+	}
+	fo := &fakeOutboundSender{}
+	svc := NewService(Config{
+		Enabled:         true,
+		AppSecret:       "secret-1",
+		OutboundEnabled: true,
+		PhoneNumberToAccount: map[string]string{
+			"123456789": "bot-clientes",
+		},
+	}, fs, fo, nil)
+
+	body := []byte(`{"object":"whatsapp_business_account","entry":[{"id":"waba-1","changes":[{"field":"messages","value":{"metadata":{"phone_number_id":"123456789"},"messages":[{"from":"593999111222","id":"wamid.link","timestamp":"1730000000","type":"text","text":{"body":"hola"}}]}}]}]}`)
+	if err := svc.ProcessEvent(context.Background(), buildSignature("secret-1", body), body); err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	if len(fo.requests) != 2 {
+		t.Fatalf("expected 2 outbound sends, got %d", len(fo.requests))
+	}
+	if fo.requests[0].kind != "link_preview" || fo.requests[0].body != "Revisa los detalles aquí\nhttps://tinkubot.com/servicios" {
+		t.Fatalf("expected link preview with appended url, got %+v", fo.requests[0])
+	}
+	if fo.requests[1].body != "Mira https://tinkubot.com/ayuda" {
+		t.Fatalf("expected url not to be duplicated, got %q", fo.requests[1].body)
+	}
+}
+
+func TestProcessEventSendsVideoAndGifReplies(t *testing.T) {
+	fs := &fakeSender{
+		resp: &webhook.WebhookResponse{
+			Success: true,
+			Messages: []webhook.ResponseMessage{
+				{Response: "Así se ve", MediaType: "gif", MediaURL: "https://cdn.tinkubot.com/demo.mp4"},
+				{Response: "Animación", MediaType: "gif", MediaURL: "https://cdn.tinkubot.com/demo.gif?v=2"},
+			},
+		},
+	}
+	fo := &fakeOutboundSender{}
+	svc := NewService(Config{
+		Enabled:         true,
+		AppSecret:       "secret-1",
+		OutboundEnabled: true,
+		PhoneNumberToAccount: map[string]string{
+			"123456789": "bot-clientes",
+		},
+	}, fs, fo, nil)
+
+	body := []byte(`{"object":"whatsapp_business_account","entry":[{"id":"waba-1","changes":[{"field":"messages","value":{"metadata":{"phone_number_id":"123456789"},"messages":[{"from":"593999111222","id":"wamid.video","timestamp":"1730000000","type":"text","text":{"body":"hola"}}]}}]}]}`)
+	if err := svc.ProcessEvent(context.Background(), buildSignature("secret-1", body), body); err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	if len(fo.requests) != 2 {
+		t.Fatalf("expected 2 outbound sends, got %d: %+v", len(fo.requests), fo.requests)
+	}
+	if fo.requests[0].kind != "video" || fo.requests[0].imageURL != "https://cdn.tinkubot.com/demo.mp4" || fo.requests[0].imageCaption != "Así se ve" {
+		t.Fatalf("expected mp4 video with caption, got %+v", fo.requests[0])
+	}
+	if fo.requests[1].kind != "document" || fo.requests[1].imageURL != "https://cdn.tinkubot.com/demo.gif?v=2" || fo.requests[1].body != "demo.gif" || fo.requests[1].imageCaption != "Animación" {
+		t.Fatalf("expected gif url to be sent as a document, got %+v", fo.requests[1])
+	}
+}
+
+func TestProcessEventForwardsPaymentStatuses(t *testing.T) {
+	fs := &fakePaymentSender{}
+	svc := NewService(Config{
+		Enabled:   true,
+		AppSecret: "secret-1",
+		PhoneNumberToAccount: map[string]string{
+			"123456789": "bot-clientes",
+		},
+	}, fs, nil, nil)
+
+	body := []byte(`{
+		"object":"whatsapp_business_account",
+		"entry":[{"id":"waba-1","changes":[{"field":"messages","value":{
+			"metadata":{"phone_number_id":"123456789"},
+			"statuses":[
+				{"id":"wamid.delivered.1","recipient_id":"593999111222","status":"delivered","timestamp":"1730000000"},
+				{
+					"id":"wamid.pay.1",
+					"recipient_id":"593999111222",
+					"type":"payment",
+					"status":"captured",
+					"timestamp":"1730000001",
+					"payment":{
+						"reference_id":"order-77",
+						"amount":{"value":21050,"offset":100},
+						"currency":"INR",
+						"transaction":{"id":"tx-1","type":"upi","status":"success"}
+					}
+				}
+			]
+		}}]}]
+	}`)
+	sig := buildSignature("secret-1", body)
+
+	for i := 0; i < 2; i++ {
+		if err := svc.ProcessEvent(context.Background(), sig, body); err != nil {
+			t.Fatalf("expected nil error, got %v", err)
+		}
+	}
+	if len(fs.payloads) != 0 {
+		t.Fatalf("expected no message payloads, got %d", len(fs.payloads))
+	}
+	if len(fs.payments) != 1 {
+		t.Fatalf("expected 1 deduplicated payment event, got %d", len(fs.payments))
+	}
+	got := fs.payments[0]
+	if got.Event != webhook.PaymentEventReceived || got.AccountID != "bot-clientes" || got.Phone != "593999111222" {
+		t.Fatalf("unexpected payment routing %+v", got)
+	}
+	if got.Amount != 210.5 || got.Currency != "INR" || got.Status != "captured" {
+		t.Fatalf("expected 210.50 INR captured, got %.2f %s %s", got.Amount, got.Currency, got.Status)
+	}
+	if got.TransactionID != "tx-1" || got.TransactionStatus != "success" || got.ReferenceID != "order-77" {
+		t.Fatalf("unexpected transaction fields %+v", got)
+	}
+}
+
+type deadlineSender struct {
+	fakeSender
+	timeout  time.Duration
+	deadline time.Duration
+}
+
+func (f *deadlineSender) SendTimeout(*webhook.WebhookPayload) time.Duration { return f.timeout }
+
+func (f *deadlineSender) Send(ctx context.Context, payload *webhook.WebhookPayload) (*webhook.WebhookResponse, error) {
+	if deadline, ok := ctx.Deadline(); ok {
+		f.deadline = time.Until(deadline)
+	}
+	return f.fakeSender.Send(ctx, payload)
+}
+
+func TestForwardUsesSenderTimeout(t *testing.T) {
+	fs := &deadlineSender{timeout: 93 * time.Second}
+	svc := NewService(Config{
+		Enabled:   true,
+		AppSecret: "secret-1",
+		PhoneNumberToAccount: map[string]string{
+			"123456789": "bot-clientes",
+		},
+	}, fs, nil, nil)
+
+	body := []byte(`{"object":"whatsapp_business_account","entry":[{"id":"waba-1","changes":[{"field":"messages","value":{"metadata":{"phone_number_id":"123456789"},"messages":[{"from":"593999111222","id":"wamid.deadline","timestamp":"1730000000","type":"text","text":{"body":"hola"}}]}}]}]}`)
+	if err := svc.ProcessEvent(context.Background(), buildSignature("secret-1", body), body); err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	if fs.deadline <= 90*time.Second || fs.deadline > 93*time.Second {
+		t.Fatalf("expected forward deadline from sender timeout, got %s", fs.deadline)
+	}
+}
@@ -0,0 +1,23 @@
+package metawebhook
+
+import (
+	"crypto/sha1"
+	"fmt"
+	"time"
+)
+
+// buildSessionID derives a deterministic UUID (version 5 layout) for the
+// conversation of userID with accountID during the rotation window that
+// contains now. Windows are aligned to the Unix epoch in UTC, so with the
+// default 24h rotation a session ends at 00:00 UTC.
+func buildSessionID(accountID, userID string, now time.Time, rotation time.Duration) string {
+	if userID == "" {
+		return ""
+	}
+	seconds := max(int64(rotation/time.Second), 1)
+	window := now.Unix() / seconds * seconds
+	sum := sha1.Sum([]byte(fmt.Sprintf("%s|%s|%d", accountID, userID, window)))
+	sum[6] = (sum[6] & 0x0f) | 0x50
+	sum[8] = (sum[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", sum[0:4], sum[4:6], sum[6:8], sum[8:10], sum[10:16])
+}
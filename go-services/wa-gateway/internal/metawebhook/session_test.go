@@ -0,0 +1,61 @@
+package metawebhook
+
+import (
+	"regexp"
+	"testing"
+	"time"
+)
+
+var uuidV5Pattern = regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-5[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+
+func TestBuildSessionIDRotatesPerWindow(t *testing.T) {
+	morning := time.Date(2025, 3, 10, 8, 0, 0, 0, time.UTC)
+	evening := time.Date(2025, 3, 10, 22, 30, 0, 0, time.UTC)
+	nextDay := time.Date(2025, 3, 11, 0, 0, 1, 0, time.UTC)
+
+	id := buildSessionID("bot-clientes", "593999111222", morning, 24*time.Hour)
+	if !uuidV5Pattern.MatchString(id) {
+		t.Fatalf("expected UUID v5 layout, got %q", id)
+	}
+	if got := buildSessionID("bot-clientes", "593999111222", evening, 24*time.Hour); got != id {
+		t.Fatalf("expected same session within the day, got %q and %q", id, got)
+	}
+	if got := buildSessionID("bot-clientes", "593999111222", nextDay, 24*time.Hour); got == id {
+		t.Fatal("expected a new session after the window rotates")
+	}
+	if got := buildSessionID("bot-clientes", "593999111222", evening, 6*time.Hour); got == buildSessionID("bot-clientes", "593999111222", morning, 6*time.Hour) {
+		t.Fatal("expected 6h rotation to split the day")
+	}
+}
+
+func TestBuildSessionIDAlignsWindowsToUnixEpoch(t *testing.T) {
+	// 7h does not divide the span between Go's zero time and the Unix
+	// epoch, so windows aligned to the former would split elsewhere.
+	const rotation = 7 * time.Hour
+	boundary := time.Unix(int64(rotation/time.Second)*100000, 0)
+
+	start := buildSessionID("bot-clientes", "593999111222", boundary, rotation)
+	if got := buildSessionID("bot-clientes", "593999111222", boundary.Add(-time.Second), rotation); got == start {
+		t.Fatal("expected a new session at the epoch-aligned boundary")
+	}
+	if got := buildSessionID("bot-clientes", "593999111222", boundary.Add(rotation-time.Second), rotation); got != start {
+		t.Fatalf("expected the same session until the window ends, got %q and %q", start, got)
+	}
+	if got := buildSessionID("bot-clientes", "593999111222", boundary.In(time.FixedZone("ECT", -5*3600)), rotation); got != start {
+		t.Fatalf("expected the window to ignore the time zone, got %q and %q", start, got)
+	}
+}
+
+func TestBuildSessionIDIsScopedPerAccountAndSender(t *testing.T) {
+	now := time.Date(2025, 3, 10, 8, 0, 0, 0, time.UTC)
+	id := buildSessionID("bot-clientes", "593999111222", now, 24*time.Hour)
+	if buildSessionID("bot-proveedores", "593999111222", now, 24*time.Hour) == id {
+		t.Fatal("expected different accounts to get different sessions")
+	}
+	if buildSessionID("bot-clientes", "593999111333", now, 24*time.Hour) == id {
+		t.Fatal("expected different senders to get different sessions")
+	}
+	if got := buildSessionID("bot-clientes", "", now, 24*time.Hour); got != "" {
+		t.Fatalf("expected empty session without a sender, got %q", got)
+	}
+}
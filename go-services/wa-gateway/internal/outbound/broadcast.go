@@ -0,0 +1,53 @@
+package outbound
+
+import (
+	"context"
+	"sync"
+)
+
+// broadcastWorkers bounds concurrent sends within one broadcast. Sends are
+// additionally paced per account by the router's SendThrottle.
+const broadcastWorkers = 8
+
+// BroadcastResult is the outcome of one account/recipient pair of a broadcast.
+type BroadcastResult struct {
+	AccountID string `json:"account_id"`
+	To        string `json:"to"`
+	Success   bool   `json:"success"`
+	Error     string `json:"error,omitempty"`
+}
+
+// BroadcastToAll sends message from every account to every recipient
+// concurrently. Results are returned in account-major order, matching the
+// order of accountIDs and recipients.
+func (r *Router) BroadcastToAll(ctx context.Context, message string, accountIDs, recipients []string) []BroadcastResult {
+	results := make([]BroadcastResult, 0, len(accountIDs)*len(recipients))
+	for _, accountID := range accountIDs {
+		for _, to := range recipients {
+			results = append(results, BroadcastResult{AccountID: accountID, To: to})
+		}
+	}
+
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < min(broadcastWorkers, len(results)); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				result := &results[i]
+				if _, err := r.SendText(ctx, result.AccountID, result.To, message); err != nil {
+					result.Error = err.Error()
+					continue
+				}
+				result.Success = true
+			}
+		}()
+	}
+	for i := range results {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+	return results
+}
@@ -0,0 +1,72 @@
+package outbound
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/tinkubot/wa-gateway/internal/webhook"
+)
+
+type recordingMetaSender struct {
+	fakeMetaSender
+	mu    sync.Mutex
+	sends map[string]int
+	fail  string
+}
+
+func (f *recordingMetaSender) SendText(ctx context.Context, phoneNumberID, to, body string) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if to == f.fail {
+		return "", errors.New("meta send status=400")
+	}
+	f.sends[phoneNumberID+":"+to]++
+	return "", nil
+}
+
+func (f *recordingMetaSender) SendButtons(ctx context.Context, phoneNumberID, to, body string, ui webhook.UIConfig) (string, error) {
+	return "", nil
+}
+
+func TestBroadcastToAllSendsEveryPair(t *testing.T) {
+	sender := &recordingMetaSender{sends: map[string]int{}, fail: "593999111444"}
+	router := NewRouter(sender, RouterConfig{
+		MetaOutboundEnabled: true,
+		AccountPhoneNumber: map[string]string{
+			"bot-clientes":    "111",
+			"bot-proveedores": "222",
+		},
+	})
+
+	results := router.BroadcastToAll(
+		context.Background(),
+		"Mantenimiento programado",
+		[]string{"bot-clientes", "bot-proveedores", "bot-desconocido"},
+		[]string{"593999111222", "593999111333", "593999111444"},
+	)
+
+	if len(results) != 9 {
+		t.Fatalf("expected 9 results, got %d", len(results))
+	}
+	if results[0].AccountID != "bot-clientes" || results[0].To != "593999111222" || !results[0].Success {
+		t.Fatalf("expected results in request order, got %+v", results[0])
+	}
+	failures := 0
+	for _, result := range results {
+		if !result.Success {
+			failures++
+			if result.Error == "" {
+				t.Fatalf("expected error message for failed pair %+v", result)
+			}
+		}
+	}
+	// 3 for the unknown account plus the failing recipient on both real accounts.
+	if failures != 5 {
+		t.Fatalf("expected 5 failures, got %d: %+v", failures, results)
+	}
+	if sender.sends["111:593999111222"] != 1 || sender.sends["222:593999111333"] != 1 {
+		t.Fatalf("expected one send per pair, got %v", sender.sends)
+	}
+}
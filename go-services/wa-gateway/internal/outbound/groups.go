@@ -0,0 +1,143 @@
+package outbound
+
+import (
+	"errors"
+	"sort"
+	"strings"
+	"sync"
+)
+
+var (
+	ErrGroupNotFound = errors.New("account group not found")
+	ErrGroupExists   = errors.New("account group already exists")
+	ErrInvalidGroup  = errors.New("group id and account ids must not be empty")
+)
+
+// AccountGroup is a named set of accounts that receive identical messages,
+// e.g. "production" or "staging" bots.
+type AccountGroup struct {
+	GroupID  string   `json:"group_id"`
+	Accounts []string `json:"accounts"`
+}
+
+// AccountGroups is an in-memory registry of account groups. Groups seeded
+// from configuration are recreated on restart; changes made through the API
+// are not persisted.
+type AccountGroups struct {
+	mu     sync.RWMutex
+	groups map[string]map[string]bool
+}
+
+// NewAccountGroups creates a registry seeded with groups (group ID → account IDs).
+func NewAccountGroups(groups map[string][]string) *AccountGroups {
+	g := &AccountGroups{groups: make(map[string]map[string]bool)}
+	for groupID, accounts := range groups {
+		groupID = strings.TrimSpace(groupID)
+		if groupID == "" {
+			continue
+		}
+		members := make(map[string]bool)
+		for _, accountID := range accounts {
+			if accountID = strings.TrimSpace(accountID); accountID != "" {
+				members[accountID] = true
+			}
+		}
+		g.groups[groupID] = members
+	}
+	return g
+}
+
+// Create adds a new group with the given members.
+func (g *AccountGroups) Create(groupID string, accounts []string) (AccountGroup, error) {
+	groupID = strings.TrimSpace(groupID)
+	if groupID == "" {
+		return AccountGroup{}, ErrInvalidGroup
+	}
+	members := make(map[string]bool)
+	for _, accountID := range accounts {
+		accountID = strings.TrimSpace(accountID)
+		if accountID == "" {
+			return AccountGroup{}, ErrInvalidGroup
+		}
+		members[accountID] = true
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if _, exists := g.groups[groupID]; exists {
+		return AccountGroup{}, ErrGroupExists
+	}
+	g.groups[groupID] = members
+	return groupOf(groupID, members), nil
+}
+
+// Get returns a group and its members.
+func (g *AccountGroups) Get(groupID string) (AccountGroup, error) {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	members, exists := g.groups[groupID]
+	if !exists {
+		return AccountGroup{}, ErrGroupNotFound
+	}
+	return groupOf(groupID, members), nil
+}
+
+// List returns all groups sorted by ID.
+func (g *AccountGroups) List() []AccountGroup {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	out := make([]AccountGroup, 0, len(g.groups))
+	for groupID, members := range g.groups {
+		out = append(out, groupOf(groupID, members))
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].GroupID < out[j].GroupID })
+	return out
+}
+
+// Delete removes a group.
+func (g *AccountGroups) Delete(groupID string) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if _, exists := g.groups[groupID]; !exists {
+		return ErrGroupNotFound
+	}
+	delete(g.groups, groupID)
+	return nil
+}
+
+// AddMember adds accountID to a group. Adding an existing member is a no-op.
+func (g *AccountGroups) AddMember(groupID, accountID string) (AccountGroup, error) {
+	accountID = strings.TrimSpace(accountID)
+	if accountID == "" {
+		return AccountGroup{}, ErrInvalidGroup
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	members, exists := g.groups[groupID]
+	if !exists {
+		return AccountGroup{}, ErrGroupNotFound
+	}
+	members[accountID] = true
+	return groupOf(groupID, members), nil
+}
+
+// RemoveMember removes accountID from a group. Removing a non-member is a no-op.
+func (g *AccountGroups) RemoveMember(groupID, accountID string) (AccountGroup, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	members, exists := g.groups[groupID]
+	if !exists {
+		return AccountGroup{}, ErrGroupNotFound
+	}
+	delete(members, accountID)
+	return groupOf(groupID, members), nil
+}
+
+func groupOf(groupID string, members map[string]bool) AccountGroup {
+	accounts := make([]string, 0, len(members))
+	for accountID := range members {
+		accounts = append(accounts, accountID)
+	}
+	sort.Strings(accounts)
+	return AccountGroup{GroupID: groupID, Accounts: accounts}
+}
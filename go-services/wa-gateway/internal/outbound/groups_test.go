@@ -0,0 +1,60 @@
+package outbound
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestAccountGroupsLifecycle(t *testing.T) {
+	groups := NewAccountGroups(map[string][]string{
+		"production": {"bot-proveedores", "bot-clientes"},
+	})
+
+	group, err := groups.Get("production")
+	if err != nil {
+		t.Fatalf("expected seeded group, got %v", err)
+	}
+	if want := []string{"bot-clientes", "bot-proveedores"}; !reflect.DeepEqual(group.Accounts, want) {
+		t.Fatalf("expected members %v, got %v", want, group.Accounts)
+	}
+
+	if _, err := groups.Create("production", nil); !errors.Is(err, ErrGroupExists) {
+		t.Fatalf("expected ErrGroupExists, got %v", err)
+	}
+	if _, err := groups.Create(" ", nil); !errors.Is(err, ErrInvalidGroup) {
+		t.Fatalf("expected ErrInvalidGroup, got %v", err)
+	}
+	if _, err := groups.Create("staging", []string{"bot-staging"}); err != nil {
+		t.Fatalf("create staging: %v", err)
+	}
+
+	group, err = groups.AddMember("staging", "bot-qa")
+	if err != nil {
+		t.Fatalf("add member: %v", err)
+	}
+	if want := []string{"bot-qa", "bot-staging"}; !reflect.DeepEqual(group.Accounts, want) {
+		t.Fatalf("expected members %v, got %v", want, group.Accounts)
+	}
+	group, err = groups.RemoveMember("staging", "bot-staging")
+	if err != nil {
+		t.Fatalf("remove member: %v", err)
+	}
+	if want := []string{"bot-qa"}; !reflect.DeepEqual(group.Accounts, want) {
+		t.Fatalf("expected members %v, got %v", want, group.Accounts)
+	}
+
+	if list := groups.List(); len(list) != 2 || list[0].GroupID != "production" || list[1].GroupID != "staging" {
+		t.Fatalf("expected production and staging sorted, got %+v", list)
+	}
+
+	if err := groups.Delete("staging"); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if _, err := groups.AddMember("staging", "bot-qa"); !errors.Is(err, ErrGroupNotFound) {
+		t.Fatalf("expected ErrGroupNotFound after delete, got %v", err)
+	}
+	if err := groups.Delete("staging"); !errors.Is(err, ErrGroupNotFound) {
+		t.Fatalf("expected ErrGroupNotFound on second delete, got %v", err)
+	}
+}
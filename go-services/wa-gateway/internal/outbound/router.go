@@ -5,8 +5,11 @@ import (
 	"errors"
 	"fmt"
 	"log"
+	"sort"
 	"strings"
+	"time"
 
+	"github.com/tinkubot/wa-gateway/internal/metaoutbound"
 	"github.com/tinkubot/wa-gateway/internal/webhook"
 )
 
@@ -14,16 +17,60 @@ var (
 	// ErrMetaNotConfigured indicates the account is configured to send via Meta
 	// but required runtime dependencies/config are missing.
 	ErrMetaNotConfigured = errors.New("meta outbound not configured")
+	// ErrInvalidDestination indicates the destination is not a valid E.164
+	// phone number or WhatsApp JID.
+	ErrInvalidDestination = errors.New("invalid meta destination")
 )
 
-// MetaSender sends messages through Meta Cloud API.
+// MetaSender sends messages through Meta Cloud API. Sends return the
+// message ID assigned by the Graph API.
 type MetaSender interface {
-	SendText(ctx context.Context, phoneNumberID, to, body string) error
-	SendButtons(ctx context.Context, phoneNumberID, to, body string, ui webhook.UIConfig) error
-	SendList(ctx context.Context, phoneNumberID, to, body string, ui webhook.UIConfig) error
-	SendLocationRequest(ctx context.Context, phoneNumberID, to, body string) error
-	SendFlow(ctx context.Context, phoneNumberID, to, body string, ui webhook.UIConfig) error
-	SendTemplate(ctx context.Context, phoneNumberID, to string, ui webhook.UIConfig) error
+	SendText(ctx context.Context, phoneNumberID, to, body string) (string, error)
+	SendButtons(ctx context.Context, phoneNumberID, to, body string, ui webhook.UIConfig) (string, error)
+	SendList(ctx context.Context, phoneNumberID, to, body string, ui webhook.UIConfig) (string, error)
+	SendLocationRequest(ctx context.Context, phoneNumberID, to, body string) (string, error)
+	SendFlow(ctx context.Context, phoneNumberID, to, body string, ui webhook.UIConfig) (string, error)
+	SendTemplate(ctx context.Context, phoneNumberID, to string, ui webhook.UIConfig) (string, error)
+}
+
+// StatusReporter is implemented by senders that track per phone number
+// reachability of the Graph API.
+type StatusReporter interface {
+	PhoneNumberStatus(phoneNumberID string) metaoutbound.PhoneNumberStatus
+}
+
+// PhoneNumberChecker is implemented by senders that can actively verify
+// Graph API access for a phone number.
+type PhoneNumberChecker interface {
+	CheckPhoneNumber(ctx context.Context, phoneNumberID string) error
+}
+
+// MediaSender is implemented by senders that can upload and send media files.
+type MediaSender interface {
+	SendImageFromBytes(ctx context.Context, phoneNumberID, to string, data []byte, mimeType, caption string) (string, error)
+	SendDocumentFromBytes(ctx context.Context, phoneNumberID, to string, data []byte, mimeType, filename, caption string) (string, error)
+}
+
+// ProfileUpdater is implemented by senders that can edit the WhatsApp
+// business profile.
+type ProfileUpdater interface {
+	SetAbout(ctx context.Context, phoneNumberID, about string) error
+	SetProfilePicture(ctx context.Context, phoneNumberID string, data []byte) error
+}
+
+// SendThrottle paces sends per account; see ratelimit.SendThrottle.
+type SendThrottle interface {
+	Wait(ctx context.Context, accountID string) error
+}
+
+type noThrottle struct{}
+
+func (noThrottle) Wait(context.Context, string) error { return nil }
+
+// AccountStatus is the WhatsApp reachability of an account routed via Meta.
+type AccountStatus struct {
+	AccountID string
+	metaoutbound.PhoneNumberStatus
 }
 
 // RouterConfig controls outbound routing strategy.
@@ -32,6 +79,7 @@ type RouterConfig struct {
 	MetaEnabledAccounts         map[string]bool
 	AccountPhoneNumber          map[string]string
 	MetaPreserveLIDForProviders bool
+	SendThrottle                SendThrottle
 }
 
 // Router routes outbound sends per account to the proper transport.
@@ -41,6 +89,8 @@ type Router struct {
 	metaEnabledAccount      map[string]bool
 	accountPhoneNumber      map[string]string
 	preserveLIDForProviders bool
+	throttle                SendThrottle
+	telemetry               *sendTelemetry
 }
 
 // NewRouter builds a transport router.
@@ -53,36 +103,75 @@ func NewRouter(metaSender MetaSender, cfg RouterConfig) *Router {
 	if accountPhoneNumber == nil {
 		accountPhoneNumber = map[string]string{}
 	}
+	var throttle SendThrottle = noThrottle{}
+	if cfg.SendThrottle != nil {
+		throttle = cfg.SendThrottle
+	}
 
-	return &Router{
+	router := &Router{
 		metaSender:              metaSender,
 		metaOutboundOn:          cfg.MetaOutboundEnabled,
 		metaEnabledAccount:      metaEnabled,
 		accountPhoneNumber:      accountPhoneNumber,
 		preserveLIDForProviders: cfg.MetaPreserveLIDForProviders,
+		throttle:                throttle,
+		telemetry:               newSendTelemetry(),
+	}
+	go router.telemetry.resetHourly()
+	return router
+}
+
+// dispatch waits for the account's send slot, runs send and records the
+// outcome in the send telemetry. It returns the message ID send reports.
+func (r *Router) dispatch(ctx context.Context, accountID string, send func() (string, error)) (string, error) {
+	if err := r.throttle.Wait(ctx, accountID); err != nil {
+		r.telemetry.record(accountID, 0, err)
+		return "", err
 	}
+	start := time.Now()
+	messageID, err := send()
+	r.telemetry.record(accountID, time.Since(start), err)
+	return messageID, err
 }
 
-// SendText sends a text message through the configured transport for accountID.
-func (r *Router) SendText(ctx context.Context, accountID, to, message string) error {
+// reject records a send that failed validation before reaching Meta.
+func (r *Router) reject(accountID string, err error) error {
+	r.telemetry.record(accountID, 0, err)
+	return err
+}
+
+// SendStats returns the send telemetry of accountID for the current hour.
+// ok is false when the account is not routed via Meta.
+func (r *Router) SendStats(accountID string) (SendStats, bool) {
+	if r == nil || !r.shouldUseMeta(accountID) {
+		return SendStats{}, false
+	}
+	return r.telemetry.snapshot(accountID), true
+}
+
+// SendText sends a text message through the configured transport for
+// accountID and returns the Meta message ID.
+func (r *Router) SendText(ctx context.Context, accountID, to, message string) (string, error) {
 	if r == nil {
-		return fmt.Errorf("outbound router is nil")
+		return "", fmt.Errorf("outbound router is nil")
 	}
 	if !r.shouldUseMeta(accountID) {
-		return fmt.Errorf("%w: account=%s", ErrMetaNotConfigured, accountID)
+		return "", fmt.Errorf("%w: account=%s", ErrMetaNotConfigured, accountID)
 	}
 	if r.metaSender == nil {
-		return fmt.Errorf("%w: sender unavailable for account=%s", ErrMetaNotConfigured, accountID)
+		return "", fmt.Errorf("%w: sender unavailable for account=%s", ErrMetaNotConfigured, accountID)
 	}
 	phoneNumberID := strings.TrimSpace(r.accountPhoneNumber[accountID])
 	if phoneNumberID == "" {
-		return fmt.Errorf("%w: missing phone_number_id for account=%s", ErrMetaNotConfigured, accountID)
+		return "", fmt.Errorf("%w: missing phone_number_id for account=%s", ErrMetaNotConfigured, accountID)
 	}
 	metaTo := r.resolveMetaDestination(accountID, to)
 	if metaTo == "" {
-		return fmt.Errorf("invalid meta destination for account=%s", accountID)
+		return "", r.reject(accountID, fmt.Errorf("%w: account=%s", ErrInvalidDestination, accountID))
 	}
-	return r.metaSender.SendText(ctx, phoneNumberID, metaTo, message)
+	return r.dispatch(ctx, accountID, func() (string, error) {
+		return r.metaSender.SendText(ctx, phoneNumberID, metaTo, message)
+	})
 }
 
 // SendButtons sends buttons through Meta when available, or falls back to text.
@@ -90,23 +179,25 @@ func (r *Router) SendButtons(
 	ctx context.Context,
 	accountID, to, message string,
 	ui webhook.UIConfig,
-) error {
+) (string, error) {
 	if r == nil {
-		return fmt.Errorf("outbound router is nil")
+		return "", fmt.Errorf("outbound router is nil")
 	}
 	if r.shouldUseMeta(accountID) {
 		if r.metaSender == nil {
-			return fmt.Errorf("%w: sender unavailable for account=%s", ErrMetaNotConfigured, accountID)
+			return "", fmt.Errorf("%w: sender unavailable for account=%s", ErrMetaNotConfigured, accountID)
 		}
 		phoneNumberID := strings.TrimSpace(r.accountPhoneNumber[accountID])
 		if phoneNumberID == "" {
-			return fmt.Errorf("%w: missing phone_number_id for account=%s", ErrMetaNotConfigured, accountID)
+			return "", fmt.Errorf("%w: missing phone_number_id for account=%s", ErrMetaNotConfigured, accountID)
 		}
 		metaTo := r.resolveMetaDestination(accountID, to)
 		if metaTo == "" {
-			return fmt.Errorf("invalid meta destination for account=%s", accountID)
+			return "", r.reject(accountID, fmt.Errorf("%w: account=%s", ErrInvalidDestination, accountID))
 		}
-		return r.metaSender.SendButtons(ctx, phoneNumberID, metaTo, message, ui)
+		return r.dispatch(ctx, accountID, func() (string, error) {
+			return r.metaSender.SendButtons(ctx, phoneNumberID, metaTo, message, ui)
+		})
 	}
 
 	return r.SendText(ctx, accountID, to, message)
@@ -117,23 +208,25 @@ func (r *Router) SendList(
 	ctx context.Context,
 	accountID, to, message string,
 	ui webhook.UIConfig,
-) error {
+) (string, error) {
 	if r == nil {
-		return fmt.Errorf("outbound router is nil")
+		return "", fmt.Errorf("outbound router is nil")
 	}
 	if r.shouldUseMeta(accountID) {
 		if r.metaSender == nil {
-			return fmt.Errorf("%w: sender unavailable for account=%s", ErrMetaNotConfigured, accountID)
+			return "", fmt.Errorf("%w: sender unavailable for account=%s", ErrMetaNotConfigured, accountID)
 		}
 		phoneNumberID := strings.TrimSpace(r.accountPhoneNumber[accountID])
 		if phoneNumberID == "" {
-			return fmt.Errorf("%w: missing phone_number_id for account=%s", ErrMetaNotConfigured, accountID)
+			return "", fmt.Errorf("%w: missing phone_number_id for account=%s", ErrMetaNotConfigured, accountID)
 		}
 		metaTo := r.resolveMetaDestination(accountID, to)
 		if metaTo == "" {
-			return fmt.Errorf("invalid meta destination for account=%s", accountID)
+			return "", r.reject(accountID, fmt.Errorf("%w: account=%s", ErrInvalidDestination, accountID))
 		}
-		return r.metaSender.SendList(ctx, phoneNumberID, metaTo, message, ui)
+		return r.dispatch(ctx, accountID, func() (string, error) {
+			return r.metaSender.SendList(ctx, phoneNumberID, metaTo, message, ui)
+		})
 	}
 
 	return r.SendText(ctx, accountID, to, message)
@@ -143,23 +236,25 @@ func (r *Router) SendList(
 func (r *Router) SendLocationRequest(
 	ctx context.Context,
 	accountID, to, message string,
-) error {
+) (string, error) {
 	if r == nil {
-		return fmt.Errorf("outbound router is nil")
+		return "", fmt.Errorf("outbound router is nil")
 	}
 	if r.shouldUseMeta(accountID) {
 		if r.metaSender == nil {
-			return fmt.Errorf("%w: sender unavailable for account=%s", ErrMetaNotConfigured, accountID)
+			return "", fmt.Errorf("%w: sender unavailable for account=%s", ErrMetaNotConfigured, accountID)
 		}
 		phoneNumberID := strings.TrimSpace(r.accountPhoneNumber[accountID])
 		if phoneNumberID == "" {
-			return fmt.Errorf("%w: missing phone_number_id for account=%s", ErrMetaNotConfigured, accountID)
+			return "", fmt.Errorf("%w: missing phone_number_id for account=%s", ErrMetaNotConfigured, accountID)
 		}
 		metaTo := r.resolveMetaDestination(accountID, to)
 		if metaTo == "" {
-			return fmt.Errorf("invalid meta destination for account=%s", accountID)
+			return "", r.reject(accountID, fmt.Errorf("%w: account=%s", ErrInvalidDestination, accountID))
 		}
-		return r.metaSender.SendLocationRequest(ctx, phoneNumberID, metaTo, message)
+		return r.dispatch(ctx, accountID, func() (string, error) {
+			return r.metaSender.SendLocationRequest(ctx, phoneNumberID, metaTo, message)
+		})
 	}
 
 	return r.SendText(ctx, accountID, to, message)
@@ -170,23 +265,25 @@ func (r *Router) SendFlow(
 	ctx context.Context,
 	accountID, to, message string,
 	ui webhook.UIConfig,
-) error {
+) (string, error) {
 	if r == nil {
-		return fmt.Errorf("outbound router is nil")
+		return "", fmt.Errorf("outbound router is nil")
 	}
 	if r.shouldUseMeta(accountID) {
 		if r.metaSender == nil {
-			return fmt.Errorf("%w: sender unavailable for account=%s", ErrMetaNotConfigured, accountID)
+			return "", fmt.Errorf("%w: sender unavailable for account=%s", ErrMetaNotConfigured, accountID)
 		}
 		phoneNumberID := strings.TrimSpace(r.accountPhoneNumber[accountID])
 		if phoneNumberID == "" {
-			return fmt.Errorf("%w: missing phone_number_id for account=%s", ErrMetaNotConfigured, accountID)
+			return "", fmt.Errorf("%w: missing phone_number_id for account=%s", ErrMetaNotConfigured, accountID)
 		}
 		metaTo := r.resolveMetaDestination(accountID, to)
 		if metaTo == "" {
-			return fmt.Errorf("invalid meta destination for account=%s", accountID)
+			return "", r.reject(accountID, fmt.Errorf("%w: account=%s", ErrInvalidDestination, accountID))
 		}
-		return r.metaSender.SendFlow(ctx, phoneNumberID, metaTo, message, ui)
+		return r.dispatch(ctx, accountID, func() (string, error) {
+			return r.metaSender.SendFlow(ctx, phoneNumberID, metaTo, message, ui)
+		})
 	}
 
 	return r.SendText(ctx, accountID, to, message)
@@ -197,28 +294,149 @@ func (r *Router) SendTemplate(
 	ctx context.Context,
 	accountID, to, message string,
 	ui webhook.UIConfig,
-) error {
+) (string, error) {
 	if r == nil {
-		return fmt.Errorf("outbound router is nil")
+		return "", fmt.Errorf("outbound router is nil")
 	}
 	if r.shouldUseMeta(accountID) {
 		if r.metaSender == nil {
-			return fmt.Errorf("%w: sender unavailable for account=%s", ErrMetaNotConfigured, accountID)
+			return "", fmt.Errorf("%w: sender unavailable for account=%s", ErrMetaNotConfigured, accountID)
 		}
 		phoneNumberID := strings.TrimSpace(r.accountPhoneNumber[accountID])
 		if phoneNumberID == "" {
-			return fmt.Errorf("%w: missing phone_number_id for account=%s", ErrMetaNotConfigured, accountID)
+			return "", fmt.Errorf("%w: missing phone_number_id for account=%s", ErrMetaNotConfigured, accountID)
 		}
 		metaTo := r.resolveMetaDestination(accountID, to)
 		if metaTo == "" {
-			return fmt.Errorf("invalid meta destination for account=%s", accountID)
+			return "", r.reject(accountID, fmt.Errorf("%w: account=%s", ErrInvalidDestination, accountID))
 		}
-		return r.metaSender.SendTemplate(ctx, phoneNumberID, metaTo, ui)
+		return r.dispatch(ctx, accountID, func() (string, error) {
+			return r.metaSender.SendTemplate(ctx, phoneNumberID, metaTo, ui)
+		})
 	}
 
 	return r.SendText(ctx, accountID, to, message)
 }
 
+// SendMedia uploads data and sends it with an optional caption. JPEG and PNG
+// files are sent as images, anything else as a document.
+func (r *Router) SendMedia(ctx context.Context, accountID, to string, data []byte, mimeType, filename, caption string) (string, error) {
+	if r == nil {
+		return "", fmt.Errorf("outbound router is nil")
+	}
+	if !r.shouldUseMeta(accountID) {
+		return "", fmt.Errorf("%w: account=%s", ErrMetaNotConfigured, accountID)
+	}
+	mediaSender, ok := r.metaSender.(MediaSender)
+	if !ok {
+		return "", fmt.Errorf("%w: media sender unavailable for account=%s", ErrMetaNotConfigured, accountID)
+	}
+	phoneNumberID := strings.TrimSpace(r.accountPhoneNumber[accountID])
+	if phoneNumberID == "" {
+		return "", fmt.Errorf("%w: missing phone_number_id for account=%s", ErrMetaNotConfigured, accountID)
+	}
+	metaTo := r.resolveMetaDestination(accountID, to)
+	if metaTo == "" {
+		return "", r.reject(accountID, fmt.Errorf("%w: account=%s", ErrInvalidDestination, accountID))
+	}
+	return r.dispatch(ctx, accountID, func() (string, error) {
+		switch mimeType {
+		case "image/jpeg", "image/png":
+			return mediaSender.SendImageFromBytes(ctx, phoneNumberID, metaTo, data, mimeType, caption)
+		default:
+			return mediaSender.SendDocumentFromBytes(ctx, phoneNumberID, metaTo, data, mimeType, filename, caption)
+		}
+	})
+}
+
+// SetStatusMessage sets the account's profile status message ("about").
+func (r *Router) SetStatusMessage(ctx context.Context, accountID, message string) error {
+	if r == nil {
+		return fmt.Errorf("outbound router is nil")
+	}
+	profileUpdater, phoneNumberID, err := r.profileUpdater(accountID)
+	if err != nil {
+		return err
+	}
+	return profileUpdater.SetAbout(ctx, phoneNumberID, message)
+}
+
+// SetProfilePicture replaces the account's profile picture with a JPEG image.
+func (r *Router) SetProfilePicture(ctx context.Context, accountID string, data []byte) error {
+	if r == nil {
+		return fmt.Errorf("outbound router is nil")
+	}
+	profileUpdater, phoneNumberID, err := r.profileUpdater(accountID)
+	if err != nil {
+		return err
+	}
+	return profileUpdater.SetProfilePicture(ctx, phoneNumberID, data)
+}
+
+func (r *Router) profileUpdater(accountID string) (ProfileUpdater, string, error) {
+	if !r.shouldUseMeta(accountID) {
+		return nil, "", fmt.Errorf("%w: account=%s", ErrMetaNotConfigured, accountID)
+	}
+	profileUpdater, ok := r.metaSender.(ProfileUpdater)
+	if !ok {
+		return nil, "", fmt.Errorf("%w: profile updates unavailable for account=%s", ErrMetaNotConfigured, accountID)
+	}
+	phoneNumberID := strings.TrimSpace(r.accountPhoneNumber[accountID])
+	if phoneNumberID == "" {
+		return nil, "", fmt.Errorf("%w: missing phone_number_id for account=%s", ErrMetaNotConfigured, accountID)
+	}
+	return profileUpdater, phoneNumberID, nil
+}
+
+// AccountStatuses returns the reachability of every account that sends via
+// Meta, sorted by account ID. Senders that do not implement StatusReporter
+// report every account as unknown.
+func (r *Router) AccountStatuses() []AccountStatus {
+	if r == nil {
+		return nil
+	}
+	reporter, _ := r.metaSender.(StatusReporter)
+
+	statuses := make([]AccountStatus, 0, len(r.accountPhoneNumber))
+	for accountID, phoneNumberID := range r.accountPhoneNumber {
+		if !r.shouldUseMeta(accountID) {
+			continue
+		}
+		phoneNumberID = strings.TrimSpace(phoneNumberID)
+		status := metaoutbound.PhoneNumberStatus{
+			PhoneNumberID: phoneNumberID,
+			Status:        metaoutbound.PhoneNumberUnknown,
+		}
+		if reporter != nil {
+			status = reporter.PhoneNumberStatus(phoneNumberID)
+		}
+		statuses = append(statuses, AccountStatus{AccountID: accountID, PhoneNumberStatus: status})
+	}
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].AccountID < statuses[j].AccountID
+	})
+	return statuses
+}
+
+// CheckAccount actively verifies that accountID can reach the Graph API.
+func (r *Router) CheckAccount(ctx context.Context, accountID string) error {
+	if r == nil {
+		return fmt.Errorf("outbound router is nil")
+	}
+	if !r.shouldUseMeta(accountID) {
+		return fmt.Errorf("%w: account=%s", ErrMetaNotConfigured, accountID)
+	}
+	checker, ok := r.metaSender.(PhoneNumberChecker)
+	if !ok {
+		return fmt.Errorf("%w: sender cannot check account=%s", ErrMetaNotConfigured, accountID)
+	}
+	phoneNumberID := strings.TrimSpace(r.accountPhoneNumber[accountID])
+	if phoneNumberID == "" {
+		return fmt.Errorf("%w: missing phone_number_id for account=%s", ErrMetaNotConfigured, accountID)
+	}
+	return checker.CheckPhoneNumber(ctx, phoneNumberID)
+}
+
 func (r *Router) shouldUseMeta(accountID string) bool {
 	if !r.metaOutboundOn {
 		return false
@@ -246,14 +464,29 @@ func (r *Router) resolveMetaDestination(accountID, to string) string {
 	return metaTo
 }
 
+// E.164 numbers carry at most 15 digits including the country code; anything
+// shorter than 8 cannot be a reachable mobile number.
+const (
+	minDestinationDigits = 8
+	maxDestinationDigits = 15
+)
+
+// ValidDestination reports whether to normalizes to a sendable phone number.
+func ValidDestination(to string) bool {
+	metaTo, _ := normalizeMetaDestination(to, false)
+	return metaTo != ""
+}
+
 // normalizeMetaDestination converts JID or formatted phone into the Meta outbound destination.
 func normalizeMetaDestination(to string, preserveJID bool) (string, string) {
 	to = strings.TrimSpace(to)
 	if to == "" {
 		return "", "empty"
 	}
-	if preserveJID && strings.Contains(to, "@") {
-		return to, "preserve_full_jid"
+	if preserveJID {
+		if user, server, ok := strings.Cut(to, "@"); ok && user != "" && server != "" {
+			return to, "preserve_full_jid"
+		}
 	}
 	if idx := strings.Index(to, "@"); idx > 0 {
 		to = to[:idx]
@@ -266,7 +499,7 @@ func normalizeMetaDestination(to string, preserveJID bool) (string, string) {
 		}
 	}
 	metaTo := b.String()
-	if metaTo == "" {
+	if len(metaTo) < minDestinationDigits || len(metaTo) > maxDestinationDigits {
 		return "", "invalid"
 	}
 	return metaTo, "digits_only"
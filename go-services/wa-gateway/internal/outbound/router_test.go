@@ -2,6 +2,8 @@ package outbound
 
 import (
 	"context"
+	"errors"
+	"strings"
 	"testing"
 
 	"github.com/tinkubot/wa-gateway/internal/webhook"
@@ -11,59 +13,59 @@ type fakeMetaSender struct {
 	lastTo string
 }
 
-func (f *fakeMetaSender) SendText(ctx context.Context, phoneNumberID, to, body string) error {
+func (f *fakeMetaSender) SendText(ctx context.Context, phoneNumberID, to, body string) (string, error) {
 	_, _, _ = ctx, phoneNumberID, body
 	f.lastTo = to
-	return nil
+	return "", nil
 }
 
 func (f *fakeMetaSender) SendButtons(
 	ctx context.Context,
 	phoneNumberID, to, body string,
 	ui webhook.UIConfig,
-) error {
+) (string, error) {
 	_, _, _, _ = ctx, phoneNumberID, body, ui
 	f.lastTo = to
-	return nil
+	return "", nil
 }
 
 func (f *fakeMetaSender) SendList(
 	ctx context.Context,
 	phoneNumberID, to, body string,
 	ui webhook.UIConfig,
-) error {
+) (string, error) {
 	_, _, _, _ = ctx, phoneNumberID, body, ui
 	f.lastTo = to
-	return nil
+	return "", nil
 }
 
 func (f *fakeMetaSender) SendLocationRequest(
 	ctx context.Context,
 	phoneNumberID, to, body string,
-) error {
+) (string, error) {
 	_, _, _ = ctx, phoneNumberID, body
 	f.lastTo = to
-	return nil
+	return "", nil
 }
 
 func (f *fakeMetaSender) SendFlow(
 	ctx context.Context,
 	phoneNumberID, to, body string,
 	ui webhook.UIConfig,
-) error {
+) (string, error) {
 	_, _, _, _ = ctx, phoneNumberID, body, ui
 	f.lastTo = to
-	return nil
+	return "", nil
 }
 
 func (f *fakeMetaSender) SendTemplate(
 	ctx context.Context,
 	phoneNumberID, to string,
 	ui webhook.UIConfig,
-) error {
+) (string, error) {
 	_, _, _ = ctx, phoneNumberID, ui
 	f.lastTo = to
-	return nil
+	return "", nil
 }
 
 func TestNormalizeMetaDestinationDigitsOnly(t *testing.T) {
@@ -96,6 +98,79 @@ func TestNormalizeMetaDestinationPreservesWhatsAppJIDWhenEnabled(t *testing.T) {
 	}
 }
 
+func FuzzNormalizeMetaDestination(f *testing.F) {
+	seeds := []string{
+		"593999111222",
+		"+593 99 911 1222",
+		"39101516509235@lid",
+		"593995971989@s.whatsapp.net",
+		"@lid",
+		"593999111222@",
+		"",
+		"   ",
+		"abc",
+		"@@@",
+		"١٢٣@lid",
+	}
+	for _, seed := range seeds {
+		f.Add(seed, false)
+		f.Add(seed, true)
+	}
+
+	f.Fuzz(func(t *testing.T, to string, preserveJID bool) {
+		got, strategy := normalizeMetaDestination(to, preserveJID)
+		switch strategy {
+		case "preserve_full_jid":
+			user, server, ok := strings.Cut(got, "@")
+			if !preserveJID || !ok || user == "" || server == "" {
+				t.Fatalf("invalid preserved jid %q for input %q", got, to)
+			}
+		case "digits_only":
+			if len(got) < minDestinationDigits || len(got) > maxDestinationDigits {
+				t.Fatalf("digits_only destination %q out of E.164 range for input %q", got, to)
+			}
+			for _, r := range got {
+				if r < '0' || r > '9' {
+					t.Fatalf("non-digit %q in destination %q for input %q", r, got, to)
+				}
+			}
+		case "empty", "invalid":
+			if got != "" {
+				t.Fatalf("expected empty destination for strategy %s, got %q", strategy, got)
+			}
+		default:
+			t.Fatalf("unexpected strategy %q for input %q", strategy, to)
+		}
+	})
+}
+
+func TestNormalizeMetaDestinationRejectsInvalidLengths(t *testing.T) {
+	cases := map[string]string{
+		"+52 1 55 1234 5678":          "5215512345678",
+		"593999111222@s.whatsapp.net": "593999111222",
+		"1234567":                     "",
+		"1234567890123456":            "",
+		"52@s.whatsapp.net":           "",
+	}
+	for input, want := range cases {
+		got, _ := normalizeMetaDestination(input, false)
+		if got != want {
+			t.Fatalf("normalizeMetaDestination(%q) = %q, want %q", input, got, want)
+		}
+	}
+}
+
+func TestRouterSendTextRejectsInvalidDestination(t *testing.T) {
+	router := NewRouter(&fakeMetaSender{}, RouterConfig{
+		MetaOutboundEnabled: true,
+		AccountPhoneNumber:  map[string]string{"bot-clientes": "12345"},
+	})
+	_, err := router.SendText(context.Background(), "bot-clientes", "52@s.whatsapp.net", "hola")
+	if !errors.Is(err, ErrInvalidDestination) {
+		t.Fatalf("expected ErrInvalidDestination, got %v", err)
+	}
+}
+
 func TestRouterSendButtonsPreservesLIDOnlyForBotProveedores(t *testing.T) {
 	meta := &fakeMetaSender{}
 	router := NewRouter(
@@ -112,7 +187,7 @@ func TestRouterSendButtonsPreservesLIDOnlyForBotProveedores(t *testing.T) {
 		},
 	)
 
-	err := router.SendButtons(
+	_, err := router.SendButtons(
 		context.Background(),
 		"bot-proveedores",
 		"39101516509235@lid",
@@ -143,7 +218,7 @@ func TestRouterSendButtonsPreservesWhatsAppJIDForBotProveedores(t *testing.T) {
 		},
 	)
 
-	err := router.SendButtons(
+	_, err := router.SendButtons(
 		context.Background(),
 		"bot-proveedores",
 		"593995971989@s.whatsapp.net",
@@ -174,7 +249,7 @@ func TestRouterSendButtonsKeepsDigitsForOtherAccounts(t *testing.T) {
 		},
 	)
 
-	err := router.SendButtons(
+	_, err := router.SendButtons(
 		context.Background(),
 		"bot-clientes",
 		"39101516509235@lid",
@@ -188,3 +263,40 @@ func TestRouterSendButtonsKeepsDigitsForOtherAccounts(t *testing.T) {
 		t.Fatalf("expected digits-only destination for non-provider account, got %q", meta.lastTo)
 	}
 }
+
+type fakeThrottle struct {
+	accounts []string
+	err      error
+}
+
+func (f *fakeThrottle) Wait(ctx context.Context, accountID string) error {
+	f.accounts = append(f.accounts, accountID)
+	return f.err
+}
+
+func TestRouterSendTextWaitsOnThrottle(t *testing.T) {
+	sender := &fakeMetaSender{}
+	throttle := &fakeThrottle{}
+	router := NewRouter(sender, RouterConfig{
+		MetaOutboundEnabled: true,
+		AccountPhoneNumber:  map[string]string{"bot-clientes": "12345"},
+		SendThrottle:        throttle,
+	})
+
+	if _, err := router.SendText(context.Background(), "bot-clientes", "593999111222", "hola"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(throttle.accounts) != 1 || throttle.accounts[0] != "bot-clientes" {
+		t.Fatalf("expected throttle wait for bot-clientes, got %v", throttle.accounts)
+	}
+
+	sender.lastTo = ""
+	throttle.err = context.DeadlineExceeded
+	_, err := router.SendText(context.Background(), "bot-clientes", "593999111222", "hola")
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected throttle error, got %v", err)
+	}
+	if sender.lastTo != "" {
+		t.Fatalf("expected send to be skipped while throttled, got lastTo=%q", sender.lastTo)
+	}
+}
@@ -0,0 +1,181 @@
+package outbound
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/tinkubot/wa-gateway/internal/metaoutbound"
+)
+
+// Send failure types reported by SendStats.
+const (
+	SendErrorInvalidDestination = "invalid_destination"
+	SendErrorMessageTooLong     = "message_too_long"
+	SendErrorUnauthorized       = "unauthorized"
+	SendErrorRejected           = "rejected"
+	SendErrorServer             = "server_error"
+	SendErrorTimeout            = "timeout"
+	SendErrorCanceled           = "canceled"
+	SendErrorTransport          = "transport"
+)
+
+// sendStatsWindow is how long counters accumulate before being reset.
+const sendStatsWindow = time.Hour
+
+// LatencyPercentiles summarizes send latency in milliseconds.
+type LatencyPercentiles struct {
+	P50 int64 `json:"p50"`
+	P90 int64 `json:"p90"`
+	P99 int64 `json:"p99"`
+	Max int64 `json:"max"`
+}
+
+// SendStats reports send outcomes for one account in the current window.
+type SendStats struct {
+	AccountID      string             `json:"account_id"`
+	WindowStart    time.Time          `json:"window_start"`
+	Attempts       int                `json:"attempts"`
+	Successes      int                `json:"successes"`
+	Failures       int                `json:"failures"`
+	SuccessRate    float64            `json:"success_rate"`
+	FailuresByType map[string]int     `json:"failures_by_type"`
+	LatencyMS      LatencyPercentiles `json:"latency_ms"`
+}
+
+type accountSendStats struct {
+	attempts       int
+	successes      int
+	failuresByType map[string]int
+	latencies      []time.Duration
+}
+
+// sendTelemetry keeps per-account send counters for the current hour.
+type sendTelemetry struct {
+	mu          sync.Mutex
+	windowStart time.Time
+	accounts    map[string]*accountSendStats
+}
+
+func newSendTelemetry() *sendTelemetry {
+	t := &sendTelemetry{}
+	t.reset()
+	return t
+}
+
+// resetHourly clears the counters at the start of every window.
+func (t *sendTelemetry) resetHourly() {
+	ticker := time.NewTicker(sendStatsWindow)
+	defer ticker.Stop()
+	for range ticker.C {
+		t.reset()
+	}
+}
+
+func (t *sendTelemetry) reset() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.windowStart = time.Now().UTC()
+	t.accounts = make(map[string]*accountSendStats)
+}
+
+// record counts one send attempt. Latency is only sampled for attempts that
+// reached the transport.
+func (t *sendTelemetry) record(accountID string, latency time.Duration, err error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	stats, ok := t.accounts[accountID]
+	if !ok {
+		stats = &accountSendStats{failuresByType: map[string]int{}}
+		t.accounts[accountID] = stats
+	}
+	stats.attempts++
+	if latency > 0 {
+		stats.latencies = append(stats.latencies, latency)
+	}
+	if err == nil {
+		stats.successes++
+		return
+	}
+	stats.failuresByType[classifySendError(err)]++
+}
+
+func (t *sendTelemetry) snapshot(accountID string) SendStats {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	out := SendStats{
+		AccountID:      accountID,
+		WindowStart:    t.windowStart,
+		FailuresByType: map[string]int{},
+	}
+	stats, ok := t.accounts[accountID]
+	if !ok {
+		return out
+	}
+	out.Attempts = stats.attempts
+	out.Successes = stats.successes
+	out.Failures = stats.attempts - stats.successes
+	if stats.attempts > 0 {
+		out.SuccessRate = float64(stats.successes) / float64(stats.attempts)
+	}
+	for errorType, count := range stats.failuresByType {
+		out.FailuresByType[errorType] = count
+	}
+	out.LatencyMS = latencyPercentiles(stats.latencies)
+	return out
+}
+
+func latencyPercentiles(samples []time.Duration) LatencyPercentiles {
+	if len(samples) == 0 {
+		return LatencyPercentiles{}
+	}
+	sorted := append([]time.Duration(nil), samples...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	at := func(p int) int64 {
+		idx := (len(sorted)*p+99)/100 - 1
+		if idx < 0 {
+			idx = 0
+		}
+		return sorted[idx].Milliseconds()
+	}
+	return LatencyPercentiles{
+		P50: at(50),
+		P90: at(90),
+		P99: at(99),
+		Max: sorted[len(sorted)-1].Milliseconds(),
+	}
+}
+
+// classifySendError maps a send error to one of the SendError* types.
+func classifySendError(err error) string {
+	var sendErr *metaoutbound.SendError
+	var netErr net.Error
+	switch {
+	case errors.Is(err, ErrInvalidDestination):
+		return SendErrorInvalidDestination
+	case errors.Is(err, metaoutbound.ErrMessageTooLong):
+		return SendErrorMessageTooLong
+	case errors.As(err, &sendErr):
+		if sendErr.IsAuthFailure() {
+			return SendErrorUnauthorized
+		}
+		if sendErr.StatusCode >= 500 {
+			return SendErrorServer
+		}
+		return SendErrorRejected
+	case errors.Is(err, context.DeadlineExceeded):
+		return SendErrorTimeout
+	case errors.Is(err, context.Canceled):
+		return SendErrorCanceled
+	case errors.As(err, &netErr) && netErr.Timeout():
+		return SendErrorTimeout
+	default:
+		return SendErrorTransport
+	}
+}
@@ -0,0 +1,82 @@
+package outbound
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/tinkubot/wa-gateway/internal/metaoutbound"
+)
+
+func TestClassifySendError(t *testing.T) {
+	tests := []struct {
+		err  error
+		want string
+	}{
+		{fmt.Errorf("%w: account=bot-clientes", ErrInvalidDestination), SendErrorInvalidDestination},
+		{fmt.Errorf("%w: caption has 2000 characters", metaoutbound.ErrMessageTooLong), SendErrorMessageTooLong},
+		{&metaoutbound.SendError{StatusCode: 401}, SendErrorUnauthorized},
+		{&metaoutbound.SendError{StatusCode: 400}, SendErrorRejected},
+		{&metaoutbound.SendError{StatusCode: 503}, SendErrorServer},
+		{fmt.Errorf("request failed: %w", context.DeadlineExceeded), SendErrorTimeout},
+		{context.Canceled, SendErrorCanceled},
+		{errors.New("connection refused"), SendErrorTransport},
+	}
+	for _, tt := range tests {
+		if got := classifySendError(tt.err); got != tt.want {
+			t.Fatalf("classifySendError(%v) = %q, want %q", tt.err, got, tt.want)
+		}
+	}
+}
+
+func TestSendTelemetrySnapshot(t *testing.T) {
+	telemetry := newSendTelemetry()
+	for i := 1; i <= 10; i++ {
+		telemetry.record("bot-clientes", time.Duration(i)*10*time.Millisecond, nil)
+	}
+	telemetry.record("bot-clientes", 0, fmt.Errorf("%w: account=bot-clientes", ErrInvalidDestination))
+	telemetry.record("bot-clientes", 500*time.Millisecond, &metaoutbound.SendError{StatusCode: 500})
+
+	stats := telemetry.snapshot("bot-clientes")
+	if stats.Attempts != 12 || stats.Successes != 10 || stats.Failures != 2 {
+		t.Fatalf("unexpected counters: %+v", stats)
+	}
+	if stats.FailuresByType[SendErrorInvalidDestination] != 1 || stats.FailuresByType[SendErrorServer] != 1 {
+		t.Fatalf("unexpected failures by type: %+v", stats.FailuresByType)
+	}
+	if stats.LatencyMS.P50 != 60 || stats.LatencyMS.P90 != 100 || stats.LatencyMS.Max != 500 {
+		t.Fatalf("unexpected latency percentiles: %+v", stats.LatencyMS)
+	}
+
+	if other := telemetry.snapshot("bot-proveedores"); other.Attempts != 0 {
+		t.Fatalf("expected other account to be empty, got %+v", other)
+	}
+
+	telemetry.reset()
+	if stats := telemetry.snapshot("bot-clientes"); stats.Attempts != 0 || len(stats.FailuresByType) != 0 {
+		t.Fatalf("expected reset to clear counters, got %+v", stats)
+	}
+}
+
+func TestRouterRecordsSendStats(t *testing.T) {
+	router := NewRouter(&fakeMetaSender{}, RouterConfig{
+		MetaOutboundEnabled: true,
+		AccountPhoneNumber:  map[string]string{"bot-clientes": "12345"},
+	})
+
+	_, _ = router.SendText(context.Background(), "bot-clientes", "593999111222", "hola")
+	_, _ = router.SendText(context.Background(), "bot-clientes", "52", "hola")
+
+	stats, ok := router.SendStats("bot-clientes")
+	if !ok {
+		t.Fatal("expected stats for bot-clientes")
+	}
+	if stats.Attempts != 2 || stats.Successes != 1 || stats.FailuresByType[SendErrorInvalidDestination] != 1 {
+		t.Fatalf("unexpected stats: %+v", stats)
+	}
+	if _, ok := router.SendStats("bot-desconocido"); ok {
+		t.Fatal("expected no stats for unknown account")
+	}
+}
@@ -0,0 +1,152 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestCheckBlocksExactlyAtHourlyLimit(t *testing.T) {
+	ctx := context.Background()
+	rl := NewLimiter(Config{MaxPerHour: 3, MaxPer24h: 100})
+
+	for i := 0; i < 2; i++ {
+		if err := rl.Increment(ctx, "bot-clientes", "593999111222"); err != nil {
+			t.Fatalf("increment: %v", err)
+		}
+	}
+	allowed, _, decision, err := rl.Check(ctx, "bot-clientes", "593999111222")
+	if !allowed || err != nil {
+		t.Fatalf("expected send below limit to be allowed, got allowed=%t err=%v", allowed, err)
+	}
+	if decision.MessagesLastHour != 2 {
+		t.Fatalf("expected 2 messages in the last hour, got %d", decision.MessagesLastHour)
+	}
+
+	if err := rl.Increment(ctx, "bot-clientes", "593999111222"); err != nil {
+		t.Fatalf("increment: %v", err)
+	}
+	allowed, retryAfter, decision, err := rl.Check(ctx, "bot-clientes", "593999111222")
+	if allowed || err == nil {
+		t.Fatalf("expected send at limit to be blocked, got allowed=%t err=%v", allowed, err)
+	}
+	if decision.Window != "hourly" {
+		t.Fatalf("expected hourly window, got %q", decision.Window)
+	}
+	if retryAfter <= 0 || retryAfter > time.Hour {
+		t.Fatalf("expected retryAfter within the hour, got %s", retryAfter)
+	}
+}
+
+func TestCheckBlocksAtDailyLimit(t *testing.T) {
+	ctx := context.Background()
+	rl := NewLimiter(Config{MaxPerHour: 100, MaxPer24h: 2})
+
+	for i := 0; i < 2; i++ {
+		if err := rl.Increment(ctx, "bot-clientes", "593999111222"); err != nil {
+			t.Fatalf("increment: %v", err)
+		}
+	}
+	allowed, _, decision, _ := rl.Check(ctx, "bot-clientes", "593999111222")
+	if allowed {
+		t.Fatal("expected send at daily limit to be blocked")
+	}
+	if decision.Window != "daily" {
+		t.Fatalf("expected daily window, got %q", decision.Window)
+	}
+}
+
+func TestCheckResetsCountersAfterWindowExpires(t *testing.T) {
+	ctx := context.Background()
+	rl := NewLimiter(Config{MaxPerHour: 1, MaxPer24h: 2})
+
+	if err := rl.Increment(ctx, "bot-clientes", "593999111222"); err != nil {
+		t.Fatalf("increment: %v", err)
+	}
+	if allowed, _, _, _ := rl.Check(ctx, "bot-clientes", "593999111222"); allowed {
+		t.Fatal("expected send to be blocked within the hour")
+	}
+
+	entry := rl.store[getKey("bot-clientes", "593999111222")]
+	entry.HourWindowStart = entry.HourWindowStart.Add(-time.Hour)
+	allowed, _, decision, err := rl.Check(ctx, "bot-clientes", "593999111222")
+	if !allowed || err != nil {
+		t.Fatalf("expected send to be allowed after hour window, got allowed=%t err=%v", allowed, err)
+	}
+	if decision.MessagesLastHour != 0 || decision.MessagesLast24H != 1 {
+		t.Fatalf("expected only the hourly counter to reset, got %+v", decision)
+	}
+
+	if err := rl.Increment(ctx, "bot-clientes", "593999111222"); err != nil {
+		t.Fatalf("increment: %v", err)
+	}
+	entry.HourWindowStart = entry.HourWindowStart.Add(-time.Hour)
+	if allowed, _, decision, _ := rl.Check(ctx, "bot-clientes", "593999111222"); allowed || decision.Window != "daily" {
+		t.Fatalf("expected daily limit to block, got allowed=%t window=%q", allowed, decision.Window)
+	}
+
+	entry.DayWindowStart = entry.DayWindowStart.Add(-24 * time.Hour)
+	if allowed, _, _, err := rl.Check(ctx, "bot-clientes", "593999111222"); !allowed || err != nil {
+		t.Fatalf("expected send to be allowed after day window, got allowed=%t err=%v", allowed, err)
+	}
+}
+
+func TestResetAllowsBlockedDestination(t *testing.T) {
+	ctx := context.Background()
+	rl := NewLimiter(Config{MaxPerHour: 1, MaxPer24h: 100})
+
+	if err := rl.Increment(ctx, "bot-clientes", "593999111222"); err != nil {
+		t.Fatalf("increment: %v", err)
+	}
+	if allowed, _, _, _ := rl.Check(ctx, "bot-clientes", "593999111222"); allowed {
+		t.Fatal("expected destination to be blocked before reset")
+	}
+
+	if err := rl.Reset(ctx, "bot-clientes", "593999111222"); err != nil {
+		t.Fatalf("reset: %v", err)
+	}
+	if allowed, _, _, err := rl.Check(ctx, "bot-clientes", "593999111222"); !allowed || err != nil {
+		t.Fatalf("expected destination to be allowed after reset, got allowed=%t err=%v", allowed, err)
+	}
+}
+
+func TestLimitsAreScopedPerAccountAndDestination(t *testing.T) {
+	ctx := context.Background()
+	rl := NewLimiter(Config{MaxPerHour: 1, MaxPer24h: 100})
+
+	if err := rl.Increment(ctx, "bot-clientes", "593999111222"); err != nil {
+		t.Fatalf("increment: %v", err)
+	}
+	if allowed, _, _, _ := rl.Check(ctx, "bot-proveedores", "593999111222"); !allowed {
+		t.Fatal("expected other account to be unaffected")
+	}
+	if allowed, _, _, _ := rl.Check(ctx, "bot-clientes", "593999111333"); !allowed {
+		t.Fatal("expected other destination to be unaffected")
+	}
+}
+
+func TestLimiterConcurrentIncrements(t *testing.T) {
+	ctx := context.Background()
+	rl := NewLimiter(Config{MaxPerHour: 1000, MaxPer24h: 1000})
+
+	const workers = 50
+	const perWorker = 10
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < perWorker; j++ {
+				_, _, _, _ = rl.Check(ctx, "bot-clientes", "593999111222")
+				_ = rl.Increment(ctx, "bot-clientes", "593999111222")
+			}
+		}()
+	}
+	wg.Wait()
+
+	_, _, decision, _ := rl.Check(ctx, "bot-clientes", "593999111222")
+	if decision.MessagesLastHour != workers*perWorker {
+		t.Fatalf("expected %d messages, got %d", workers*perWorker, decision.MessagesLastHour)
+	}
+}
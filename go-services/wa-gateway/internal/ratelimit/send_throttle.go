@@ -0,0 +1,117 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// SendThrottleConfig holds per-account send rate configuration.
+type SendThrottleConfig struct {
+	DefaultPerSecond int
+	PerSecond        map[string]int
+}
+
+// sendBucket is a token bucket holding up to one second worth of sends.
+type sendBucket struct {
+	rate   float64
+	tokens float64
+	last   time.Time
+}
+
+// SendThrottle paces outbound sends per account with a token bucket so
+// bursts do not trip WhatsApp's anti-spam throttling. Unlike Limiter it does
+// not reject sends, it delays them.
+type SendThrottle struct {
+	mu      sync.Mutex
+	buckets map[string]*sendBucket
+	config  SendThrottleConfig
+	now     func() time.Time
+}
+
+// NewSendThrottle creates a send throttle, defaulting to 10 sends/second.
+func NewSendThrottle(config SendThrottleConfig) *SendThrottle {
+	if config.DefaultPerSecond <= 0 {
+		config.DefaultPerSecond = 10
+	}
+	if config.PerSecond == nil {
+		config.PerSecond = map[string]int{}
+	}
+
+	return &SendThrottle{
+		buckets: make(map[string]*sendBucket),
+		config:  config,
+		now:     time.Now,
+	}
+}
+
+func (t *SendThrottle) rateFor(accountID string) float64 {
+	if perSecond := t.config.PerSecond[accountID]; perSecond > 0 {
+		return float64(perSecond)
+	}
+	return float64(t.config.DefaultPerSecond)
+}
+
+// reserve takes a token for accountID and returns how long the caller must
+// wait before sending. The token balance may go negative; callers that give
+// up must hand the token back with cancel.
+func (t *SendThrottle) reserve(accountID string) time.Duration {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := t.now()
+	bucket, exists := t.buckets[accountID]
+	if !exists {
+		rate := t.rateFor(accountID)
+		bucket = &sendBucket{rate: rate, tokens: rate, last: now}
+		t.buckets[accountID] = bucket
+	}
+
+	elapsed := now.Sub(bucket.last).Seconds()
+	if elapsed > 0 {
+		bucket.tokens += elapsed * bucket.rate
+		if bucket.tokens > bucket.rate {
+			bucket.tokens = bucket.rate
+		}
+		bucket.last = now
+	}
+
+	bucket.tokens--
+	if bucket.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-bucket.tokens / bucket.rate * float64(time.Second))
+}
+
+// cancel returns a token taken by reserve. The balance stays capped at one
+// second of sends, since the bucket may have refilled while the caller
+// waited.
+func (t *SendThrottle) cancel(accountID string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if bucket, exists := t.buckets[accountID]; exists {
+		bucket.tokens = min(bucket.tokens+1, bucket.rate)
+	}
+}
+
+// Wait blocks until accountID may send another message or ctx is done.
+func (t *SendThrottle) Wait(ctx context.Context, accountID string) error {
+	if t == nil {
+		return nil
+	}
+	delay := t.reserve(accountID)
+	if delay <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		t.cancel(accountID)
+		return ctx.Err()
+	}
+}
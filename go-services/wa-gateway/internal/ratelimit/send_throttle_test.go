@@ -0,0 +1,110 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func newTestThrottle(config SendThrottleConfig) (*SendThrottle, *time.Time) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	throttle := NewSendThrottle(config)
+	throttle.now = func() time.Time { return now }
+	return throttle, &now
+}
+
+func TestSendThrottleAllowsBurstThenDelays(t *testing.T) {
+	throttle, _ := newTestThrottle(SendThrottleConfig{DefaultPerSecond: 5})
+
+	for i := 0; i < 5; i++ {
+		if delay := throttle.reserve("bot-clientes"); delay != 0 {
+			t.Fatalf("expected send %d within burst to pass, got delay %s", i, delay)
+		}
+	}
+	if delay := throttle.reserve("bot-clientes"); delay != 200*time.Millisecond {
+		t.Fatalf("expected 200ms delay after burst, got %s", delay)
+	}
+	if delay := throttle.reserve("bot-clientes"); delay != 400*time.Millisecond {
+		t.Fatalf("expected queued sends to wait longer, got %s", delay)
+	}
+}
+
+func TestSendThrottleRefillsOverTime(t *testing.T) {
+	throttle, now := newTestThrottle(SendThrottleConfig{DefaultPerSecond: 2})
+
+	throttle.reserve("bot-clientes")
+	throttle.reserve("bot-clientes")
+	*now = now.Add(500 * time.Millisecond)
+	if delay := throttle.reserve("bot-clientes"); delay != 0 {
+		t.Fatalf("expected one token after 500ms at 2/s, got delay %s", delay)
+	}
+
+	*now = now.Add(time.Hour)
+	for i := 0; i < 2; i++ {
+		if delay := throttle.reserve("bot-clientes"); delay != 0 {
+			t.Fatalf("expected refilled burst send %d to pass, got %s", i, delay)
+		}
+	}
+	if delay := throttle.reserve("bot-clientes"); delay == 0 {
+		t.Fatal("expected burst to be capped at one second of sends")
+	}
+}
+
+func TestSendThrottleIsScopedPerAccount(t *testing.T) {
+	throttle, _ := newTestThrottle(SendThrottleConfig{
+		DefaultPerSecond: 1,
+		PerSecond:        map[string]int{"bot-proveedores": 3},
+	})
+
+	throttle.reserve("bot-clientes")
+	if delay := throttle.reserve("bot-clientes"); delay != time.Second {
+		t.Fatalf("expected default rate for bot-clientes, got delay %s", delay)
+	}
+	for i := 0; i < 3; i++ {
+		if delay := throttle.reserve("bot-proveedores"); delay != 0 {
+			t.Fatalf("expected override rate for bot-proveedores, send %d delayed %s", i, delay)
+		}
+	}
+}
+
+func TestSendThrottleWaitHonorsContext(t *testing.T) {
+	throttle := NewSendThrottle(SendThrottleConfig{DefaultPerSecond: 1})
+	if err := throttle.Wait(context.Background(), "bot-clientes"); err != nil {
+		t.Fatalf("expected first send to pass, got %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := throttle.Wait(ctx, "bot-clientes"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected deadline exceeded while throttled, got %v", err)
+	}
+	if tokens := throttle.buckets["bot-clientes"].tokens; tokens < -0.01 {
+		t.Fatalf("expected cancelled wait to return its token, got balance %f", tokens)
+	}
+}
+
+func TestSendThrottleCancelDoesNotExceedBurst(t *testing.T) {
+	throttle, now := newTestThrottle(SendThrottleConfig{DefaultPerSecond: 2})
+
+	throttle.reserve("bot-clientes")
+	throttle.reserve("bot-clientes")
+	throttle.reserve("bot-clientes")
+	*now = now.Add(time.Hour)
+	throttle.reserve("bot-clientes")
+	// Both outstanding reservations give up after the bucket refilled.
+	throttle.cancel("bot-clientes")
+	throttle.cancel("bot-clientes")
+
+	if tokens := throttle.buckets["bot-clientes"].tokens; tokens != 2 {
+		t.Fatalf("expected balance capped at one second of sends, got %f", tokens)
+	}
+	for i := 0; i < 2; i++ {
+		if delay := throttle.reserve("bot-clientes"); delay != 0 {
+			t.Fatalf("expected burst send %d to pass, got %s", i, delay)
+		}
+	}
+	if delay := throttle.reserve("bot-clientes"); delay == 0 {
+		t.Fatal("expected cancelled tokens not to extend the burst")
+	}
+}
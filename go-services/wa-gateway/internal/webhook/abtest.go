@@ -0,0 +1,132 @@
+package webhook
+
+import (
+	"errors"
+	"hash/fnv"
+	"strings"
+)
+
+// A/B variant names sent in the X-Ab-Variant header.
+const (
+	ABVariantControl = "control"
+	ABVariantVariant = "variant"
+)
+
+var (
+	ErrABTestNotFound = errors.New("A/B test not found")
+	ErrInvalidABTest  = errors.New("account id and variant url must not be empty and percentage must be positive")
+)
+
+// ABTest routes Percentage (0-100) of an account's senders to VariantURL
+// instead of the account's default AI service URL.
+type ABTest struct {
+	VariantURL string  `json:"variant_url"`
+	Percentage float64 `json:"percentage"`
+}
+
+func normalizeABTests(raw map[string]ABTest) map[string]ABTest {
+	normalized := make(map[string]ABTest, len(raw))
+	for accountID, test := range raw {
+		accountID = strings.TrimSpace(accountID)
+		test.VariantURL = strings.TrimRight(strings.TrimSpace(test.VariantURL), "/")
+		if accountID == "" || test.VariantURL == "" || test.Percentage <= 0 {
+			continue
+		}
+		test.Percentage = min(test.Percentage, 100)
+		normalized[accountID] = test
+	}
+	return normalized
+}
+
+// abBucket maps a sender to [0, 100). Buckets are derived from the sender so
+// a conversation stays on the same variant across messages.
+func abBucket(accountID, sender string) float64 {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(accountID + "|" + sender))
+	return float64(h.Sum32()%10000) / 100
+}
+
+// ABTests returns the active A/B tests by account ID.
+func (wc *WebhookClient) ABTests() map[string]ABTest {
+	wc.routingMu.RLock()
+	defer wc.routingMu.RUnlock()
+	tests := make(map[string]ABTest, len(wc.abTests))
+	for accountID, test := range wc.abTests {
+		tests[accountID] = test
+	}
+	return tests
+}
+
+// SetABTest starts or replaces the A/B test of an account. Tests are kept in
+// memory: changes made at runtime are lost on restart, when WEBHOOK_AB_TESTS
+// is loaded again.
+func (wc *WebhookClient) SetABTest(accountID string, test ABTest) (ABTest, error) {
+	accountID = strings.TrimSpace(accountID)
+	normalized, ok := normalizeABTests(map[string]ABTest{accountID: test})[accountID]
+	if !ok {
+		return ABTest{}, ErrInvalidABTest
+	}
+
+	wc.breakersMu.Lock()
+	wc.registerBreaker(accountID+":"+ABVariantVariant, normalized.VariantURL)
+	wc.breakersMu.Unlock()
+
+	wc.routingMu.Lock()
+	wc.abTests[accountID] = normalized
+	wc.routingMu.Unlock()
+	return normalized, nil
+}
+
+// DeleteABTest stops the A/B test of an account; all its senders go back to
+// the default URL.
+func (wc *WebhookClient) DeleteABTest(accountID string) error {
+	wc.routingMu.Lock()
+	defer wc.routingMu.Unlock()
+	if _, ok := wc.abTests[accountID]; !ok {
+		return ErrABTestNotFound
+	}
+	delete(wc.abTests, accountID)
+	return nil
+}
+
+func (wc *WebhookClient) abTest(accountID string) (ABTest, bool) {
+	wc.routingMu.RLock()
+	defer wc.routingMu.RUnlock()
+	test, ok := wc.abTests[accountID]
+	return test, ok
+}
+
+// abVariantURL returns the variant URL when payload's sender falls inside
+// the account's A/B test percentage.
+func (wc *WebhookClient) abVariantURL(payload *WebhookPayload) (string, bool) {
+	test, ok := wc.abTest(payload.AccountID)
+	if !ok {
+		return "", false
+	}
+	sender := normalizePhoneNumber(payload.FromNumber)
+	if sender == "" {
+		sender = strings.TrimSpace(payload.Phone)
+	}
+	if abBucket(payload.AccountID, sender) >= test.Percentage {
+		return "", false
+	}
+	return test.VariantURL + wc.endpoint, true
+}
+
+// abVariantFor names the A/B arm that url belongs to for payload's account.
+// It returns "" when the account has no active test or when url is neither
+// the variant nor the account's default URL, e.g. a regional instance or Rust
+// onboarding, since that traffic never took part in the test.
+func (wc *WebhookClient) abVariantFor(payload *WebhookPayload, url string) string {
+	test, ok := wc.abTest(payload.AccountID)
+	if !ok {
+		return ""
+	}
+	switch url {
+	case test.VariantURL + wc.endpoint:
+		return ABVariantVariant
+	case wc.defaultURL(payload.AccountID):
+		return ABVariantControl
+	}
+	return ""
+}
@@ -0,0 +1,149 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestABTestSplitsSendersAndTagsVariant(t *testing.T) {
+	hits := map[string]int{}
+	newServer := func(name string) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			hits[name+":"+r.Header.Get("X-Ab-Variant")]++
+			_ = json.NewEncoder(w).Encode(WebhookResponse{Success: true})
+		}))
+	}
+	control := newServer("control")
+	defer control.Close()
+	variant := newServer("variant")
+	defer variant.Close()
+
+	wc, err := NewWebhookClient(control.URL, control.URL, "", "", "", "/handle-whatsapp-message", 1000, 0, ClientConfig{
+		ABTests: map[string]ABTest{"bot-clientes": {VariantURL: variant.URL, Percentage: 30}},
+	})
+	if err != nil {
+		t.Fatalf("expected client, got %v", err)
+	}
+
+	const senders = 500
+	for i := 0; i < senders; i++ {
+		payload := &WebhookPayload{AccountID: "bot-clientes", FromNumber: fmt.Sprintf("5939991%05d", i), Message: "hola"}
+		if _, err := wc.Send(context.Background(), payload); err != nil {
+			t.Fatalf("send: %v", err)
+		}
+	}
+	if hits["control:control"]+hits["variant:variant"] != senders {
+		t.Fatalf("expected every request tagged with its arm, got %v", hits)
+	}
+	if share := hits["variant:variant"]; share < 100 || share > 200 {
+		t.Fatalf("expected roughly 30%% of senders on the variant, got %d/%d", share, senders)
+	}
+
+	if _, err := wc.Send(context.Background(), &WebhookPayload{AccountID: "bot-proveedores", FromNumber: "593999111222"}); err != nil {
+		t.Fatalf("send: %v", err)
+	}
+	if hits["control:"] != 1 {
+		t.Fatalf("expected account without a test to skip the header, got %v", hits)
+	}
+}
+
+func TestABBucketIsStablePerSender(t *testing.T) {
+	first := abBucket("bot-clientes", "593999111222")
+	for i := 0; i < 10; i++ {
+		if got := abBucket("bot-clientes", "593999111222"); got != first {
+			t.Fatalf("expected stable bucket %v, got %v", first, got)
+		}
+	}
+	if first < 0 || first >= 100 {
+		t.Fatalf("expected bucket in [0, 100), got %v", first)
+	}
+}
+
+func TestNormalizeABTestsDropsInactiveEntries(t *testing.T) {
+	tests := normalizeABTests(map[string]ABTest{
+		"bot-clientes":    {VariantURL: "http://v2/", Percentage: 150},
+		"bot-proveedores": {VariantURL: "http://v2", Percentage: 0},
+		"bot-otro":        {Percentage: 50},
+	})
+	if len(tests) != 1 {
+		t.Fatalf("expected only the active test, got %+v", tests)
+	}
+	if got := tests["bot-clientes"]; got.VariantURL != "http://v2" || got.Percentage != 100 {
+		t.Fatalf("expected trimmed URL and clamped percentage, got %+v", got)
+	}
+}
+
+func TestABVariantSkipsTrafficOutsideTheTest(t *testing.T) {
+	wc, err := NewWebhookClient("http://clientes", "http://proveedores", "http://rust", "593999000111", "", "/handle-whatsapp-message", 1000, 0, ClientConfig{
+		RegionURLs: map[string]map[string]string{"bot-proveedores": {"US": "http://us"}},
+		ABTests: map[string]ABTest{
+			"bot-clientes":    {VariantURL: "http://v2", Percentage: 100},
+			"bot-proveedores": {VariantURL: "http://v2", Percentage: 100},
+		},
+	})
+	if err != nil {
+		t.Fatalf("expected client, got %v", err)
+	}
+
+	cases := []struct {
+		name    string
+		payload *WebhookPayload
+		want    string
+	}{
+		{"variant", &WebhookPayload{AccountID: "bot-clientes", FromNumber: "593999111222"}, ABVariantVariant},
+		{"region", &WebhookPayload{AccountID: "bot-proveedores", FromNumber: "14155550123", CountryCode: "US"}, ""},
+		{"rust onboarding", &WebhookPayload{AccountID: "bot-proveedores", FromNumber: "593999000111"}, ""},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			url := wc.getURL(tc.payload)
+			if got := wc.abVariantFor(tc.payload, url); got != tc.want {
+				t.Fatalf("expected variant %q for %s, got %q", tc.want, url, got)
+			}
+		})
+	}
+
+	if got := wc.abVariantFor(&WebhookPayload{AccountID: "bot-clientes"}, "http://clientes/handle-whatsapp-message"); got != ABVariantControl {
+		t.Fatalf("expected the default URL to be the control arm, got %q", got)
+	}
+}
+
+func TestSetABTestRoutesAtRuntime(t *testing.T) {
+	wc, err := NewWebhookClient("http://clientes", "http://proveedores", "", "", "", "/handle-whatsapp-message", 1000, 0, ClientConfig{})
+	if err != nil {
+		t.Fatalf("expected client, got %v", err)
+	}
+	payload := &WebhookPayload{AccountID: "bot-clientes", FromNumber: "593999111222"}
+
+	if _, err := wc.SetABTest("bot-clientes", ABTest{VariantURL: "http://v2"}); !errors.Is(err, ErrInvalidABTest) {
+		t.Fatalf("expected ErrInvalidABTest, got %v", err)
+	}
+	test, err := wc.SetABTest(" bot-clientes ", ABTest{VariantURL: "http://v2/", Percentage: 100})
+	if err != nil {
+		t.Fatalf("expected test to start, got %v", err)
+	}
+	if test.VariantURL != "http://v2" {
+		t.Fatalf("expected normalized variant URL, got %q", test.VariantURL)
+	}
+	if got := wc.getURL(payload); got != "http://v2/handle-whatsapp-message" {
+		t.Fatalf("expected variant URL, got %s", got)
+	}
+	if len(wc.ABTests()) != 1 {
+		t.Fatalf("expected one active test, got %+v", wc.ABTests())
+	}
+
+	if err := wc.DeleteABTest("bot-clientes"); err != nil {
+		t.Fatalf("expected test to stop, got %v", err)
+	}
+	if got := wc.getURL(payload); got != "http://clientes/handle-whatsapp-message" {
+		t.Fatalf("expected default URL after stopping the test, got %s", got)
+	}
+	if err := wc.DeleteABTest("bot-clientes"); !errors.Is(err, ErrABTestNotFound) {
+		t.Fatalf("expected ErrABTestNotFound, got %v", err)
+	}
+}
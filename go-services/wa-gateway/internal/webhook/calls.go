@@ -0,0 +1,46 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+)
+
+// CallEvent.Event values.
+const (
+	CallEventOffer     = "call_offer"
+	CallEventTerminate = "call_terminate"
+)
+
+// CallEvent is a WhatsApp voice call started or ended by a user. Like
+// PaymentEvent it is sent separately from WebhookPayload and expects no
+// reply.
+type CallEvent struct {
+	Event           string `json:"event"`
+	AccountID       string `json:"account_id"`
+	Phone           string `json:"phone"`
+	CallID          string `json:"call_id"`
+	CallerJID       string `json:"caller_jid"`
+	CallType        string `json:"call_type"`
+	Direction       string `json:"direction,omitempty"`
+	Status          string `json:"status,omitempty"`
+	DurationSeconds int    `json:"duration_seconds,omitempty"`
+	Rejected        bool   `json:"rejected,omitempty"`
+	Timestamp       string `json:"timestamp"`
+}
+
+// SendCall delivers a call event to the account's AI service webhook with
+// X-Event-Type: call.
+func (wc *WebhookClient) SendCall(ctx context.Context, event *CallEvent) error {
+	jsonData, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("error marshaling call event: %w", err)
+	}
+	url := wc.getURL(&WebhookPayload{AccountID: event.AccountID, Phone: event.Phone})
+	if err := wc.postEvent(ctx, url, event.AccountID, "call", jsonData); err != nil {
+		return err
+	}
+	log.Printf("[Webhook] call_sent account=%s url=%s call_id=%s event=%s", event.AccountID, url, event.CallID, event.Event)
+	return nil
+}
@@ -0,0 +1,101 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	defaultCapabilitiesPath = "/webhook-capabilities"
+	capabilitiesTTL         = 5 * time.Minute
+	capabilitiesTimeout     = 2 * time.Second
+)
+
+// WebhookCapabilities describes optional protocol features an AI service
+// advertises at its capabilities path. Services that do not expose the path
+// get plain JSON requests.
+type WebhookCapabilities struct {
+	Gzip      bool `json:"gzip"`
+	Streaming bool `json:"streaming"`
+}
+
+type capabilitiesEntry struct {
+	caps      WebhookCapabilities
+	fetchedAt time.Time
+}
+
+type capabilitiesCache struct {
+	mu      sync.Mutex
+	path    string
+	entries map[string]capabilitiesEntry
+}
+
+func newCapabilitiesCache(path string) *capabilitiesCache {
+	path = strings.TrimSpace(path)
+	if path == "" {
+		path = defaultCapabilitiesPath
+	}
+	return &capabilitiesCache{
+		path:    path,
+		entries: make(map[string]capabilitiesEntry),
+	}
+}
+
+// capabilitiesFor returns the cached capabilities of the service behind url,
+// refreshing them from the service when the cache entry is stale.
+func (wc *WebhookClient) capabilitiesFor(ctx context.Context, url string) WebhookCapabilities {
+	baseURL := strings.TrimSuffix(url, wc.endpoint)
+	cache := wc.capabilities
+
+	cache.mu.Lock()
+	entry, ok := cache.entries[baseURL]
+	cache.mu.Unlock()
+	if ok && time.Since(entry.fetchedAt) < capabilitiesTTL {
+		return entry.caps
+	}
+
+	caps, err := wc.fetchCapabilities(ctx, baseURL+cache.path)
+	if err != nil {
+		log.Printf("[Webhook] capabilities_unavailable url=%s err=%v", baseURL, err)
+	}
+
+	cache.mu.Lock()
+	cache.entries[baseURL] = capabilitiesEntry{caps: caps, fetchedAt: time.Now()}
+	cache.mu.Unlock()
+	return caps
+}
+
+func (wc *WebhookClient) fetchCapabilities(ctx context.Context, url string) (WebhookCapabilities, error) {
+	ctx, cancel := context.WithTimeout(ctx, capabilitiesTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return WebhookCapabilities{}, fmt.Errorf("create capabilities request: %w", err)
+	}
+	req.Header.Set("User-Agent", "wa-gateway/1.0")
+
+	resp, err := wc.httpClient.Do(req)
+	if err != nil {
+		return WebhookCapabilities{}, fmt.Errorf("capabilities request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 1024))
+		return WebhookCapabilities{}, fmt.Errorf("capabilities status=%d", resp.StatusCode)
+	}
+
+	var caps WebhookCapabilities
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 4096)).Decode(&caps); err != nil {
+		return WebhookCapabilities{}, fmt.Errorf("decode capabilities: %w", err)
+	}
+	return caps, nil
+}
@@ -0,0 +1,115 @@
+package webhook
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen is returned when the circuit for an AI service is open and
+// the request is rejected without reaching the network.
+var ErrCircuitOpen = errors.New("webhook circuit open")
+
+// CircuitState describes the state of a circuit breaker.
+type CircuitState string
+
+const (
+	CircuitClosed   CircuitState = "closed"
+	CircuitOpen     CircuitState = "open"
+	CircuitHalfOpen CircuitState = "half_open"
+)
+
+const (
+	defaultCircuitFailureThreshold = 5
+	defaultCircuitOpenTimeout      = 30 * time.Second
+)
+
+// CircuitStatus is a point-in-time snapshot of a circuit breaker.
+type CircuitStatus struct {
+	Service             string       `json:"service"`
+	URL                 string       `json:"url"`
+	State               CircuitState `json:"state"`
+	ConsecutiveFailures int          `json:"consecutive_failures"`
+	OpenedAt            string       `json:"opened_at,omitempty"`
+}
+
+// circuitBreaker trips after failureThreshold consecutive failures and lets a
+// single probe through once openTimeout has elapsed.
+type circuitBreaker struct {
+	mu                  sync.Mutex
+	failureThreshold    int
+	openTimeout         time.Duration
+	state               CircuitState
+	consecutiveFailures int
+	openedAt            time.Time
+	probeInFlight       bool
+	now                 func() time.Time
+}
+
+func newCircuitBreaker(failureThreshold int, openTimeout time.Duration) *circuitBreaker {
+	if failureThreshold <= 0 {
+		failureThreshold = defaultCircuitFailureThreshold
+	}
+	if openTimeout <= 0 {
+		openTimeout = defaultCircuitOpenTimeout
+	}
+	return &circuitBreaker{
+		failureThreshold: failureThreshold,
+		openTimeout:      openTimeout,
+		state:            CircuitClosed,
+		now:              time.Now,
+	}
+}
+
+// allow reports whether a request may proceed. In half-open state only one
+// probe is admitted until its outcome is recorded.
+func (cb *circuitBreaker) allow() error {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	switch cb.state {
+	case CircuitOpen:
+		if cb.now().Sub(cb.openedAt) < cb.openTimeout {
+			return ErrCircuitOpen
+		}
+		cb.state = CircuitHalfOpen
+		cb.probeInFlight = true
+		return nil
+	case CircuitHalfOpen:
+		if cb.probeInFlight {
+			return ErrCircuitOpen
+		}
+		cb.probeInFlight = true
+		return nil
+	default:
+		return nil
+	}
+}
+
+func (cb *circuitBreaker) recordSuccess() {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	cb.state = CircuitClosed
+	cb.consecutiveFailures = 0
+	cb.openedAt = time.Time{}
+	cb.probeInFlight = false
+}
+
+func (cb *circuitBreaker) recordFailure() {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	cb.consecutiveFailures++
+	cb.probeInFlight = false
+	if cb.state == CircuitHalfOpen || cb.consecutiveFailures >= cb.failureThreshold {
+		cb.state = CircuitOpen
+		cb.openedAt = cb.now()
+	}
+}
+
+func (cb *circuitBreaker) status() (CircuitState, int, time.Time) {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	return cb.state, cb.consecutiveFailures, cb.openedAt
+}
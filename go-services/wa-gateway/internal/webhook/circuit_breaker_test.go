@@ -0,0 +1,210 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time { return c.now }
+
+func (c *fakeClock) Advance(d time.Duration) { c.now = c.now.Add(d) }
+
+func newTestBreaker(threshold int, openTimeout time.Duration) (*circuitBreaker, *fakeClock) {
+	clock := &fakeClock{now: time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)}
+	cb := newCircuitBreaker(threshold, openTimeout)
+	cb.now = clock.Now
+	return cb, clock
+}
+
+func TestCircuitBreakerClosedAllowsRequests(t *testing.T) {
+	cb, _ := newTestBreaker(3, time.Minute)
+
+	for i := 0; i < 10; i++ {
+		if err := cb.allow(); err != nil {
+			t.Fatalf("expected closed circuit to allow request %d, got %v", i, err)
+		}
+		cb.recordSuccess()
+	}
+	if state, failures, _ := cb.status(); state != CircuitClosed || failures != 0 {
+		t.Fatalf("expected closed circuit with no failures, got %s/%d", state, failures)
+	}
+}
+
+func TestCircuitBreakerOpensAfterConsecutiveFailures(t *testing.T) {
+	cb, _ := newTestBreaker(3, time.Minute)
+
+	for i := 0; i < 2; i++ {
+		_ = cb.allow()
+		cb.recordFailure()
+	}
+	if state, _, _ := cb.status(); state != CircuitClosed {
+		t.Fatalf("expected closed circuit below threshold, got %s", state)
+	}
+
+	_ = cb.allow()
+	cb.recordSuccess()
+	for i := 0; i < 2; i++ {
+		_ = cb.allow()
+		cb.recordFailure()
+	}
+	if state, failures, _ := cb.status(); state != CircuitClosed || failures != 2 {
+		t.Fatalf("expected success to reset the failure count, got %s/%d", state, failures)
+	}
+
+	_ = cb.allow()
+	cb.recordFailure()
+	if state, _, openedAt := cb.status(); state != CircuitOpen || openedAt.IsZero() {
+		t.Fatalf("expected open circuit at threshold, got %s opened_at=%s", state, openedAt)
+	}
+	if err := cb.allow(); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("expected ErrCircuitOpen, got %v", err)
+	}
+}
+
+func TestCircuitBreakerHalfOpenProbe(t *testing.T) {
+	cb, clock := newTestBreaker(1, 30*time.Second)
+
+	_ = cb.allow()
+	cb.recordFailure()
+
+	clock.Advance(29 * time.Second)
+	if err := cb.allow(); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("expected circuit to stay open before timeout, got %v", err)
+	}
+
+	clock.Advance(time.Second)
+	if err := cb.allow(); err != nil {
+		t.Fatalf("expected probe after timeout, got %v", err)
+	}
+	if state, _, _ := cb.status(); state != CircuitHalfOpen {
+		t.Fatalf("expected half-open circuit, got %s", state)
+	}
+	if err := cb.allow(); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("expected second request during probe to be rejected, got %v", err)
+	}
+
+	cb.recordSuccess()
+	if state, failures, _ := cb.status(); state != CircuitClosed || failures != 0 {
+		t.Fatalf("expected successful probe to close circuit, got %s/%d", state, failures)
+	}
+}
+
+func TestCircuitBreakerHalfOpenFailureReopens(t *testing.T) {
+	cb, clock := newTestBreaker(3, 30*time.Second)
+
+	for i := 0; i < 3; i++ {
+		_ = cb.allow()
+		cb.recordFailure()
+	}
+	clock.Advance(30 * time.Second)
+	if err := cb.allow(); err != nil {
+		t.Fatalf("expected probe after timeout, got %v", err)
+	}
+
+	cb.recordFailure()
+	state, _, openedAt := cb.status()
+	if state != CircuitOpen {
+		t.Fatalf("expected failed probe to reopen circuit, got %s", state)
+	}
+	if !openedAt.Equal(clock.Now()) {
+		t.Fatalf("expected open timer to restart at %s, got %s", clock.Now(), openedAt)
+	}
+	if err := cb.allow(); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("expected ErrCircuitOpen after failed probe, got %v", err)
+	}
+}
+
+func TestSendShortCircuitsWhenCircuitOpen(t *testing.T) {
+	var hits atomic.Int32
+	var healthy atomic.Bool
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/handle-whatsapp-message" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		hits.Add(1)
+		if !healthy.Load() {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(WebhookResponse{Success: true})
+	}))
+	defer srv.Close()
+
+	wc, err := NewWebhookClient(srv.URL, srv.URL, "", "", "", "/handle-whatsapp-message", 1000, 0, ClientConfig{
+		CircuitFailureThreshold: 2,
+		CircuitOpenTimeout:      30 * time.Second,
+	})
+	if err != nil {
+		t.Fatalf("expected client, got %v", err)
+	}
+	url := srv.URL + "/handle-whatsapp-message"
+	clock := &fakeClock{now: time.Now()}
+	wc.breakerFor(url).now = clock.Now
+
+	payload := &WebhookPayload{AccountID: "bot-clientes", FromNumber: "593999111222", MessageType: "text", Message: "hola"}
+	for i := 0; i < 2; i++ {
+		if _, err := wc.Send(context.Background(), payload); err == nil {
+			t.Fatalf("expected error from failing service on attempt %d", i)
+		}
+	}
+
+	_, err = wc.Send(context.Background(), payload)
+	if !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("expected ErrCircuitOpen, got %v", err)
+	}
+	if got := hits.Load(); got != 2 {
+		t.Fatalf("expected open circuit to skip the HTTP call, got %d hits", got)
+	}
+
+	healthy.Store(true)
+	clock.Advance(30 * time.Second)
+	resp, err := wc.Send(context.Background(), payload)
+	if err != nil || !resp.Success {
+		t.Fatalf("expected probe to succeed, got resp=%+v err=%v", resp, err)
+	}
+	if got := hits.Load(); got != 3 {
+		t.Fatalf("expected probe to reach the service, got %d hits", got)
+	}
+
+	for _, status := range wc.CircuitStates() {
+		if status.URL == url && status.State != CircuitClosed {
+			t.Fatalf("expected circuit to close after probe, got %+v", status)
+		}
+	}
+}
+
+func TestSendReleasesHalfOpenProbeWhenRequestCannotBeBuilt(t *testing.T) {
+	badURL := "http://bad host"
+	wc, err := NewWebhookClient(badURL, badURL, "", "", "", "/handle-whatsapp-message", 1000, 0, ClientConfig{
+		CircuitFailureThreshold: 1,
+		CircuitOpenTimeout:      time.Minute,
+	})
+	if err != nil {
+		t.Fatalf("expected client, got %v", err)
+	}
+	cb := wc.breakerFor(badURL + "/handle-whatsapp-message")
+	clock := &fakeClock{now: time.Now()}
+	cb.now = clock.Now
+	_ = cb.allow()
+	cb.recordFailure()
+	clock.Advance(2 * time.Minute)
+
+	payload := &WebhookPayload{AccountID: "bot-clientes", FromNumber: "593999111222", MessageType: "text", Message: "hola"}
+	if _, err := wc.Send(context.Background(), payload); err == nil || errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("expected request build error, got %v", err)
+	}
+	if err := cb.allow(); err != nil {
+		t.Fatalf("expected half-open probe to still be available, got %v", err)
+	}
+}
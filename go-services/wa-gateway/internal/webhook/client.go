@@ -2,6 +2,7 @@ package webhook
 
 import (
 	"bytes"
+	"compress/gzip"
 	"context"
 	"encoding/json"
 	"fmt"
@@ -16,7 +17,12 @@ import (
 func (wc *WebhookClient) Send(ctx context.Context, payload *WebhookPayload) (*WebhookResponse, error) {
 	var lastErr error
 	url := wc.getURL(payload)
+	payload = wc.withWebhookRegion(payload, url)
 	isRustOnboarding := wc.isRustOnboardingURL(url)
+	breaker := wc.breakerFor(url)
+	if isRustOnboarding {
+		payload = withNormalizedFromNumber(payload)
+	}
 
 	for attempt := 0; attempt <= wc.retryAttempts; attempt++ {
 		if attempt > 0 {
@@ -34,15 +40,28 @@ func (wc *WebhookClient) Send(ctx context.Context, payload *WebhookPayload) (*We
 			return nil, fmt.Errorf("error marshaling payload: %w", err)
 		}
 
-		req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(jsonData))
+		body, compressed, err := wc.encodeBody(ctx, url, jsonData)
+		if err != nil {
+			return nil, err
+		}
+
+		req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewReader(body))
 		if err != nil {
 			lastErr = fmt.Errorf("error creating request: %w", err)
 			continue
 		}
 
 		req.Header.Set("Content-Type", "application/json")
+		if compressed {
+			req.Header.Set("Content-Encoding", "gzip")
+			log.Printf("[Webhook] gzip_body account=%s url=%s raw_bytes=%d compressed_bytes=%d", payload.AccountID, url, len(jsonData), len(body))
+		}
 		req.Header.Set("User-Agent", "wa-gateway/1.0")
 		req.Header.Set("X-Account-ID", payload.AccountID)
+		req.Header.Set("X-Message-Type", messageKind(payload))
+		if variant := wc.abVariantFor(payload, url); variant != "" {
+			req.Header.Set("X-Ab-Variant", variant)
+		}
 		if isRustOnboarding {
 			if wc.internalToken != "" {
 				req.Header.Set("x-internal-token", wc.internalToken)
@@ -52,7 +71,7 @@ func (wc *WebhookClient) Send(ctx context.Context, payload *WebhookPayload) (*We
 			}
 		}
 		log.Printf(
-			"[Webhook] dispatch account=%s url=%s from=%s phone=%s context_from=%s context_id=%s message_type=%s selected_option=%q",
+			"[Webhook] dispatch account=%s url=%s from=%s phone=%s context_from=%s context_id=%s message_type=%s selected_option=%q request_bytes=%d ab_variant=%s",
 			payload.AccountID,
 			url,
 			payload.FromNumber,
@@ -61,34 +80,66 @@ func (wc *WebhookClient) Send(ctx context.Context, payload *WebhookPayload) (*We
 			payload.ContextID,
 			payload.MessageType,
 			payload.SelectedOption,
+			len(jsonData),
+			wc.abVariantFor(payload, url),
 		)
 
-		resp, err := wc.httpClient.Do(req)
+		// Checked right before the call so every admitted half-open probe
+		// records an outcome.
+		if err := breaker.allow(); err != nil {
+			log.Printf("[Webhook] circuit_open account=%s url=%s", payload.AccountID, url)
+			return nil, fmt.Errorf("%s: %w", url, err)
+		}
+		resp, err := wc.clientFor(payload).Do(req)
 		if err != nil {
+			breaker.recordFailure()
 			lastErr = fmt.Errorf("request failed: %w", err)
 			continue
 		}
 
+		// Only transport errors and 5xx count against the circuit: a 4xx means
+		// the service is reachable and rejected this particular payload.
+		if resp.StatusCode >= http.StatusInternalServerError {
+			breaker.recordFailure()
+		} else {
+			breaker.recordSuccess()
+		}
+
+		limit := int64(maxResponseBodyBytes)
+		if resp.StatusCode != http.StatusOK {
+			limit = maxErrorBodyBytes
+		}
+		respBody, readErr := io.ReadAll(io.LimitReader(resp.Body, limit+1))
+		resp.Body.Close()
+		tooLarge := int64(len(respBody)) > limit
+		if tooLarge {
+			respBody = respBody[:limit]
+		}
+		log.Printf("[Webhook] response account=%s url=%s status=%d response_bytes=%d truncated=%t", payload.AccountID, url, resp.StatusCode, len(respBody), tooLarge)
+
 		if resp.StatusCode != http.StatusOK {
-			bodyBytes, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
 			lastErr = fmt.Errorf("unexpected status code: %d", resp.StatusCode)
 			log.Printf(
-				"[Webhook] dispatch_failed account=%s url=%s status=%d body=%q",
+				"[Webhook] dispatch_failed account=%s url=%s status=%d%s",
 				payload.AccountID,
 				url,
 				resp.StatusCode,
-				string(bodyBytes),
+				wc.debugBody(respBody),
 			)
-			resp.Body.Close()
 			continue
 		}
+		if readErr != nil {
+			return nil, fmt.Errorf("error reading response: %w", readErr)
+		}
+		if tooLarge {
+			return nil, fmt.Errorf("response exceeds %d bytes", maxResponseBodyBytes)
+		}
 
 		var webhookResp WebhookResponse
-		if err := json.NewDecoder(resp.Body).Decode(&webhookResp); err != nil {
-			resp.Body.Close()
+		if err := json.Unmarshal(respBody, &webhookResp); err != nil {
+			log.Printf("[Webhook] decode_failed account=%s url=%s err=%v%s", payload.AccountID, url, err, wc.debugBody(respBody))
 			return nil, fmt.Errorf("error decoding response: %w", err)
 		}
-		resp.Body.Close()
 
 		log.Printf(
 			"[Webhook] Message sent successfully to %s (account: %s, from: %s, context_from: %s, context_id: %s, message_type: %s, selected_option: %q)",
@@ -106,6 +157,70 @@ func (wc *WebhookClient) Send(ctx context.Context, payload *WebhookPayload) (*We
 	return nil, fmt.Errorf("failed after %d attempts for %s: %w", wc.retryAttempts+1, payload.AccountID, lastErr)
 }
 
+// SendTimeout is the longest Send may take for payload: the text or media
+// timeout for every attempt plus the backoff between retries.
+func (wc *WebhookClient) SendTimeout(payload *WebhookPayload) time.Duration {
+	total := wc.clientFor(payload).Timeout * time.Duration(wc.retryAttempts+1)
+	for attempt := 1; attempt <= wc.retryAttempts; attempt++ {
+		total += time.Duration(attempt) * time.Second
+	}
+	return total
+}
+
+// Response body limits. AI replies are JSON with media referenced by URL, so
+// maxResponseBodyBytes leaves ample room; larger responses are rejected.
+// Error bodies are only kept for debug logging.
+const (
+	maxResponseBodyBytes = 4 << 20
+	maxErrorBodyBytes    = 1024
+)
+
+// debugBodyLimit caps how much of a response body debug logging prints.
+const debugBodyLimit = 500
+
+// debugBody formats the head of body as a log field when debug logging is
+// enabled, and returns "" otherwise.
+func (wc *WebhookClient) debugBody(body []byte) string {
+	if !wc.debugLogging {
+		return ""
+	}
+	if len(body) > debugBodyLimit {
+		body = body[:debugBodyLimit]
+	}
+	return fmt.Sprintf(" body=%q", body)
+}
+
+// encodeBody gzips jsonData when it exceeds the configured threshold and the
+// destination service advertises gzip support.
+func (wc *WebhookClient) encodeBody(ctx context.Context, url string, jsonData []byte) ([]byte, bool, error) {
+	if wc.compressThreshold <= 0 || len(jsonData) <= wc.compressThreshold {
+		return jsonData, false, nil
+	}
+	if !wc.capabilitiesFor(ctx, url).Gzip {
+		return jsonData, false, nil
+	}
+
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write(jsonData); err != nil {
+		return nil, false, fmt.Errorf("error compressing payload: %w", err)
+	}
+	if err := zw.Close(); err != nil {
+		return nil, false, fmt.Errorf("error compressing payload: %w", err)
+	}
+	return buf.Bytes(), true, nil
+}
+
+// withNormalizedFromNumber returns a copy of payload whose FromNumber is in
+// +E.164 form, as expected by Rust onboarding.
+func withNormalizedFromNumber(payload *WebhookPayload) *WebhookPayload {
+	normalized := *payload
+	if from := normalizePhoneNumber(payload.FromNumber); from != "" {
+		normalized.FromNumber = from
+	}
+	return &normalized
+}
+
 func (wc *WebhookClient) isRustOnboardingURL(url string) bool {
 	return wc.onboardingRustURL != "" && url == wc.onboardingRustURL+wc.endpoint
 }
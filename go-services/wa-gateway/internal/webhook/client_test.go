@@ -1,11 +1,19 @@
 package webhook
 
 import (
+	"bytes"
+	"compress/gzip"
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestSendRoutesBotProveedoresToProvidersURL(t *testing.T) {
@@ -32,7 +40,10 @@ func TestSendRoutesBotProveedoresToProvidersURL(t *testing.T) {
 	}))
 	defer clientesServer.Close()
 
-	wc := NewWebhookClient(clientesServer.URL, providersServer.URL, "", "", "", "/handle-whatsapp-message", 1000, 0)
+	wc, err := NewWebhookClient(clientesServer.URL, providersServer.URL, "", "", "", "/handle-whatsapp-message", 1000, 0, ClientConfig{})
+	if err != nil {
+		t.Fatalf("expected client, got %v", err)
+	}
 
 	resp, err := wc.Send(context.Background(), &WebhookPayload{
 		AccountID:      "bot-proveedores",
@@ -85,7 +96,10 @@ func TestSendRoutesRustOnboardingForTestNumbers(t *testing.T) {
 	}))
 	defer providersServer.Close()
 
-	wc := NewWebhookClient(clientesServer.URL, providersServer.URL, rustServer.URL, "+593959091325, +593999999999", "secret-token", "/handle-whatsapp-message", 1000, 0)
+	wc, err := NewWebhookClient(clientesServer.URL, providersServer.URL, rustServer.URL, "+593959091325, +593999999999", "secret-token", "/handle-whatsapp-message", 1000, 0, ClientConfig{})
+	if err != nil {
+		t.Fatalf("expected client, got %v", err)
+	}
 
 	resp, err := wc.Send(context.Background(), &WebhookPayload{
 		AccountID:      "bot-proveedores",
@@ -109,12 +123,64 @@ func TestSendRoutesRustOnboardingForTestNumbers(t *testing.T) {
 	}
 }
 
+func TestGetURLRoutesByAccount(t *testing.T) {
+	wc, err := NewWebhookClient(
+		"http://clientes:8001",
+		"http://proveedores:8002",
+		"http://onboarding:8003",
+		"+593959091325",
+		"",
+		"/handle-whatsapp-message",
+		1000,
+		0,
+		ClientConfig{},
+	)
+	if err != nil {
+		t.Fatalf("expected client, got %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		accountID  string
+		fromNumber string
+		want       string
+	}{
+		{"clientes", "bot-clientes", "593999111222@s.whatsapp.net", "http://clientes:8001/handle-whatsapp-message"},
+		{"proveedores", "bot-proveedores", "593999111222@s.whatsapp.net", "http://proveedores:8002/handle-whatsapp-message"},
+		{"unknown account defaults to proveedores", "bot-desconocido", "593999111222", "http://proveedores:8002/handle-whatsapp-message"},
+		{"empty account defaults to proveedores", "", "593999111222", "http://proveedores:8002/handle-whatsapp-message"},
+		{"proveedores test number goes to rust", "bot-proveedores", "593959091325@s.whatsapp.net", "http://onboarding:8003/handle-whatsapp-message"},
+		{"clientes test number stays on clientes", "bot-clientes", "593959091325@s.whatsapp.net", "http://clientes:8001/handle-whatsapp-message"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := wc.getURL(&WebhookPayload{AccountID: tt.accountID, FromNumber: tt.fromNumber})
+			if got != tt.want {
+				t.Fatalf("getURL(%q, %q) = %q, want %q", tt.accountID, tt.fromNumber, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetURLFallsBackToProveedoresWithoutRustURL(t *testing.T) {
+	wc, err := NewWebhookClient("http://clientes:8001", "http://proveedores:8002", "", "+593959091325", "", "/handle-whatsapp-message", 1000, 0, ClientConfig{})
+	if err != nil {
+		t.Fatalf("expected client, got %v", err)
+	}
+
+	got := wc.getURL(&WebhookPayload{AccountID: "bot-proveedores", FromNumber: "593959091325"})
+	if got != "http://proveedores:8002/handle-whatsapp-message" {
+		t.Fatalf("expected proveedores fallback, got %q", got)
+	}
+}
+
 func TestNormalizePhoneNumber(t *testing.T) {
 	cases := map[string]string{
-		"+593959091325":            "+593959091325",
-		"593959091325":             "+593959091325",
+		"+593959091325":               "+593959091325",
+		"593959091325":                "+593959091325",
 		"593959091325@s.whatsapp.net": "+593959091325",
-		"  +593959091325  ":        "+593959091325",
+		"  +593959091325  ":           "+593959091325",
 	}
 
 	for input, expected := range cases {
@@ -123,3 +189,362 @@ func TestNormalizePhoneNumber(t *testing.T) {
 		}
 	}
 }
+
+func TestNewTransportAppliesPoolSettings(t *testing.T) {
+	transport, err := newTransport(ClientConfig{
+		MaxIdleConnsPerHost:   8,
+		IdleConnTimeout:       45 * time.Second,
+		DisableKeepAlives:     true,
+		ResponseHeaderTimeout: 2 * time.Second,
+	})
+	if err != nil {
+		t.Fatalf("expected transport, got %v", err)
+	}
+	if transport.MaxIdleConnsPerHost != 8 {
+		t.Fatalf("expected MaxIdleConnsPerHost 8, got %d", transport.MaxIdleConnsPerHost)
+	}
+	if transport.IdleConnTimeout != 45*time.Second {
+		t.Fatalf("expected IdleConnTimeout 45s, got %s", transport.IdleConnTimeout)
+	}
+	if !transport.DisableKeepAlives {
+		t.Fatalf("expected keep-alives disabled")
+	}
+	if transport.ResponseHeaderTimeout != 2*time.Second {
+		t.Fatalf("expected ResponseHeaderTimeout 2s, got %s", transport.ResponseHeaderTimeout)
+	}
+
+	defaults, _ := newTransport(ClientConfig{})
+	if defaults.MaxIdleConnsPerHost != 32 || defaults.IdleConnTimeout != 90*time.Second {
+		t.Fatalf("unexpected defaults: idle_per_host=%d idle_timeout=%s", defaults.MaxIdleConnsPerHost, defaults.IdleConnTimeout)
+	}
+}
+
+func TestSendGzipsLargePayloadWhenServiceAdvertisesSupport(t *testing.T) {
+	var gotEncoding string
+	var gotPayload WebhookPayload
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/webhook-capabilities" {
+			_ = json.NewEncoder(w).Encode(WebhookCapabilities{Gzip: true})
+			return
+		}
+		gotEncoding = r.Header.Get("Content-Encoding")
+		zr, err := gzip.NewReader(r.Body)
+		if err != nil {
+			t.Fatalf("expected gzip body: %v", err)
+		}
+		if err := json.NewDecoder(zr).Decode(&gotPayload); err != nil {
+			t.Fatalf("decode payload: %v", err)
+		}
+		_ = json.NewEncoder(w).Encode(WebhookResponse{Success: true})
+	}))
+	defer server.Close()
+
+	wc, err := NewWebhookClient(server.URL, server.URL, "", "", "", "/handle-whatsapp-message", 1000, 0, ClientConfig{
+		CompressThresholdBytes: 64,
+	})
+	if err != nil {
+		t.Fatalf("expected client, got %v", err)
+	}
+
+	media := strings.Repeat("A", 512)
+	if _, err := wc.Send(context.Background(), &WebhookPayload{
+		AccountID:   "bot-clientes",
+		Phone:       "593999111222",
+		MessageType: "image",
+		MediaBase64: media,
+	}); err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	if gotEncoding != "gzip" {
+		t.Fatalf("expected Content-Encoding gzip, got %q", gotEncoding)
+	}
+	if gotPayload.MediaBase64 != media {
+		t.Fatalf("media payload was not preserved through compression")
+	}
+}
+
+func TestSendSkipsGzipWhenCapabilitiesUnavailable(t *testing.T) {
+	var gotEncoding string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/webhook-capabilities" {
+			http.NotFound(w, r)
+			return
+		}
+		gotEncoding = r.Header.Get("Content-Encoding")
+		_ = json.NewEncoder(w).Encode(WebhookResponse{Success: true})
+	}))
+	defer server.Close()
+
+	wc, err := NewWebhookClient(server.URL, server.URL, "", "", "", "/handle-whatsapp-message", 1000, 0, ClientConfig{
+		CompressThresholdBytes: 64,
+	})
+	if err != nil {
+		t.Fatalf("expected client, got %v", err)
+	}
+
+	if _, err := wc.Send(context.Background(), &WebhookPayload{
+		AccountID:   "bot-clientes",
+		Phone:       "593999111222",
+		MediaBase64: strings.Repeat("A", 512),
+	}); err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	if gotEncoding != "" {
+		t.Fatalf("expected uncompressed body, got Content-Encoding %q", gotEncoding)
+	}
+}
+
+func TestSendStreamingDecodesNDJSONReplies(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/webhook-capabilities" {
+			_ = json.NewEncoder(w).Encode(WebhookCapabilities{Streaming: true})
+			return
+		}
+		if got := r.Header.Get("Accept"); got != "application/x-ndjson" {
+			t.Fatalf("unexpected Accept header: %q", got)
+		}
+		w.Header().Set("Content-Type", "application/x-ndjson")
+		flusher := w.(http.Flusher)
+		for _, text := range []string{"uno", "dos"} {
+			_ = json.NewEncoder(w).Encode(ResponseMessage{Response: text})
+			flusher.Flush()
+		}
+		_, _ = w.Write([]byte(`{"actions":[{"type":"mark_read"}]}` + "\n"))
+	}))
+	defer server.Close()
+
+	wc, err := NewWebhookClient(server.URL, server.URL, "", "", "", "/handle-whatsapp-message", 1000, 0, ClientConfig{
+		Streaming: true,
+	})
+	if err != nil {
+		t.Fatalf("expected client, got %v", err)
+	}
+
+	replies, err := wc.SendStreaming(context.Background(), &WebhookPayload{AccountID: "bot-clientes", Phone: "593999111222"})
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	var got []string
+	var actions []GatewayAction
+	for reply := range replies {
+		if reply.HasMessage() {
+			got = append(got, reply.Response)
+		}
+		actions = append(actions, reply.Actions...)
+	}
+	if strings.Join(got, ",") != "uno,dos" {
+		t.Fatalf("unexpected streamed replies: %v", got)
+	}
+	if len(actions) != 1 || actions[0].Type != "mark_read" {
+		t.Fatalf("expected streamed mark_read action, got %+v", actions)
+	}
+}
+
+func TestSendStreamingUnsupportedWhenDisabled(t *testing.T) {
+	wc, err := NewWebhookClient("http://clientes", "http://proveedores", "", "", "", "/handle-whatsapp-message", 1000, 0, ClientConfig{})
+	if err != nil {
+		t.Fatalf("expected client, got %v", err)
+	}
+	if _, err := wc.SendStreaming(context.Background(), &WebhookPayload{AccountID: "bot-clientes"}); !errors.Is(err, ErrStreamingUnsupported) {
+		t.Fatalf("expected ErrStreamingUnsupported, got %v", err)
+	}
+}
+
+func TestSendUsesMediaTimeoutForMediaPayloads(t *testing.T) {
+	var gotKinds []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		kind := r.Header.Get("X-Message-Type")
+		gotKinds = append(gotKinds, kind)
+		if kind == "media" {
+			time.Sleep(150 * time.Millisecond)
+		}
+		_ = json.NewEncoder(w).Encode(WebhookResponse{Success: true})
+	}))
+	defer server.Close()
+
+	wc, err := NewWebhookClient(server.URL, server.URL, "", "", "", "/handle-whatsapp-message", 1000, 0, ClientConfig{
+		TextTimeout:  50 * time.Millisecond,
+		MediaTimeout: time.Second,
+	})
+	if err != nil {
+		t.Fatalf("expected client, got %v", err)
+	}
+
+	if _, err := wc.Send(context.Background(), &WebhookPayload{AccountID: "bot-clientes", Message: "hola"}); err != nil {
+		t.Fatalf("text send: expected nil error, got %v", err)
+	}
+	if _, err := wc.Send(context.Background(), &WebhookPayload{AccountID: "bot-clientes", MediaBase64: "aGVsbG8="}); err != nil {
+		t.Fatalf("media send: expected media timeout to allow slow response, got %v", err)
+	}
+	if strings.Join(gotKinds, ",") != "text,media" {
+		t.Fatalf("unexpected X-Message-Type headers: %v", gotKinds)
+	}
+}
+
+func TestSendTimeoutCoversEveryAttempt(t *testing.T) {
+	wc, err := NewWebhookClient("http://clientes:8001", "", "", "", "", "/handle-whatsapp-message", 1000, 2, ClientConfig{
+		TextTimeout:  5 * time.Second,
+		MediaTimeout: 30 * time.Second,
+	})
+	if err != nil {
+		t.Fatalf("expected client, got %v", err)
+	}
+	// Three attempts plus 1s and 2s of backoff.
+	if got := wc.SendTimeout(&WebhookPayload{Message: "hola"}); got != 18*time.Second {
+		t.Fatalf("expected text budget 18s, got %s", got)
+	}
+	if got := wc.SendTimeout(&WebhookPayload{MediaBase64: "aGVsbG8="}); got != 93*time.Second {
+		t.Fatalf("expected media budget 93s, got %s", got)
+	}
+}
+
+func TestSendLogsResponseBodyOnlyWithDebugLogging(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+		_, _ = w.Write([]byte(strings.Repeat("x", 800)))
+	}))
+	defer server.Close()
+
+	var logs bytes.Buffer
+	log.SetOutput(&logs)
+	defer log.SetOutput(os.Stderr)
+
+	payload := &WebhookPayload{AccountID: "bot-clientes", FromNumber: "593999111222", Message: "hola"}
+	for _, debug := range []bool{false, true} {
+		logs.Reset()
+		wc, err := NewWebhookClient(server.URL, server.URL, "", "", "", "/handle-whatsapp-message", 1000, 0, ClientConfig{
+			DebugLogging: debug,
+		})
+		if err != nil {
+			t.Fatalf("expected client, got %v", err)
+		}
+		if _, err := wc.Send(context.Background(), payload); err == nil {
+			t.Fatal("expected error for 502 response")
+		}
+
+		out := logs.String()
+		if !strings.Contains(out, "request_bytes=") || !strings.Contains(out, "response_bytes=800") {
+			t.Fatalf("expected request/response sizes to be logged, got %s", out)
+		}
+		hasBody := strings.Contains(out, `body="`+strings.Repeat("x", debugBodyLimit)+`"`)
+		if hasBody != debug {
+			t.Fatalf("debug=%t: expected body logged=%t, got logs %s", debug, debug, out)
+		}
+	}
+}
+
+func TestSendCapsResponseBodies(t *testing.T) {
+	status := http.StatusOK
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(`{"success":true,"messages":[{"response":"` + strings.Repeat("x", maxResponseBodyBytes) + `"}]}`))
+	}))
+	defer server.Close()
+
+	var logs bytes.Buffer
+	log.SetOutput(&logs)
+	defer log.SetOutput(os.Stderr)
+
+	wc, err := NewWebhookClient(server.URL, server.URL, "", "", "", "/handle-whatsapp-message", 1000, 0, ClientConfig{})
+	if err != nil {
+		t.Fatalf("expected client, got %v", err)
+	}
+	payload := &WebhookPayload{AccountID: "bot-clientes", FromNumber: "593999111222", Message: "hola"}
+	if _, err := wc.Send(context.Background(), payload); err == nil || !strings.Contains(err.Error(), "exceeds") {
+		t.Fatalf("expected oversized response error, got %v", err)
+	}
+
+	status = http.StatusBadGateway
+	logs.Reset()
+	if _, err := wc.Send(context.Background(), payload); err == nil {
+		t.Fatal("expected error for 502 response")
+	}
+	if !strings.Contains(logs.String(), fmt.Sprintf("response_bytes=%d truncated=true", maxErrorBodyBytes)) {
+		t.Fatalf("expected error body capped at %d bytes, got %s", maxErrorBodyBytes, logs.String())
+	}
+}
+
+func TestSendRoutesToRegionalInstance(t *testing.T) {
+	var defaultHits, regionalHits int
+	var gotRegion string
+	defaultServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defaultHits++
+		_ = json.NewEncoder(w).Encode(WebhookResponse{Success: true})
+	}))
+	defer defaultServer.Close()
+	regionalServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		regionalHits++
+		var payload WebhookPayload
+		_ = json.NewDecoder(r.Body).Decode(&payload)
+		gotRegion = payload.WebhookRegion
+		_ = json.NewEncoder(w).Encode(WebhookResponse{Success: true})
+	}))
+	defer regionalServer.Close()
+
+	wc, err := NewWebhookClient(defaultServer.URL, defaultServer.URL, "", "", "", "/handle-whatsapp-message", 1000, 0, ClientConfig{
+		RegionURLs: map[string]map[string]string{
+			"bot-clientes": {"us": regionalServer.URL + "/"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("expected client, got %v", err)
+	}
+
+	payload := &WebhookPayload{AccountID: "bot-clientes", FromNumber: "14155550123", CountryCode: "US", Message: "hi"}
+	if _, err := wc.Send(context.Background(), payload); err != nil {
+		t.Fatalf("send: %v", err)
+	}
+	if regionalHits != 1 || gotRegion != "US" {
+		t.Fatalf("expected regional instance tagged US, got hits=%d region=%q", regionalHits, gotRegion)
+	}
+	if payload.WebhookRegion != "" {
+		t.Fatal("expected caller payload to be left untouched")
+	}
+
+	for _, other := range []*WebhookPayload{
+		{AccountID: "bot-clientes", CountryCode: "EC", Message: "hola"},
+		{AccountID: "bot-proveedores", CountryCode: "US", Message: "hi"},
+	} {
+		if _, err := wc.Send(context.Background(), other); err != nil {
+			t.Fatalf("send: %v", err)
+		}
+	}
+	if defaultHits != 2 || regionalHits != 1 {
+		t.Fatalf("expected other countries/accounts to use the default URL, got default=%d regional=%d", defaultHits, regionalHits)
+	}
+}
+
+func TestSetWebhookRegionRoutesAtRuntime(t *testing.T) {
+	wc, err := NewWebhookClient("http://clientes", "http://proveedores", "", "", "", "/handle-whatsapp-message", 1000, 0, ClientConfig{})
+	if err != nil {
+		t.Fatalf("expected client, got %v", err)
+	}
+	payload := &WebhookPayload{AccountID: "bot-clientes", CountryCode: "US"}
+
+	if _, err := wc.SetWebhookRegion(WebhookRegion{AccountID: "bot-clientes", CountryCode: "us"}); !errors.Is(err, ErrInvalidRegion) {
+		t.Fatalf("expected ErrInvalidRegion, got %v", err)
+	}
+	region, err := wc.SetWebhookRegion(WebhookRegion{AccountID: "bot-clientes", CountryCode: " us ", WebhookURL: "http://us/"})
+	if err != nil {
+		t.Fatalf("expected region to be added, got %v", err)
+	}
+	if region != (WebhookRegion{AccountID: "bot-clientes", CountryCode: "US", WebhookURL: "http://us"}) {
+		t.Fatalf("expected normalized region, got %+v", region)
+	}
+	if got := wc.getURL(payload); got != "http://us/handle-whatsapp-message" {
+		t.Fatalf("expected regional URL, got %s", got)
+	}
+	if regions := wc.WebhookRegions(); len(regions) != 1 || regions[0] != region {
+		t.Fatalf("expected the US region listed, got %+v", regions)
+	}
+
+	if err := wc.DeleteWebhookRegion("bot-clientes", "us"); err != nil {
+		t.Fatalf("expected region to be removed, got %v", err)
+	}
+	if got := wc.getURL(payload); got != "http://clientes/handle-whatsapp-message" {
+		t.Fatalf("expected default URL after removing the region, got %s", got)
+	}
+	if err := wc.DeleteWebhookRegion("bot-clientes", "US"); !errors.Is(err, ErrRegionNotFound) {
+		t.Fatalf("expected ErrRegionNotFound, got %v", err)
+	}
+}
@@ -0,0 +1,75 @@
+package webhook
+
+import (
+	"encoding/base64"
+	"log"
+	"net/http"
+	"net/textproto"
+	"strings"
+)
+
+// reservedHeaders are set by the client itself and cannot be overridden by
+// WEBHOOK_CUSTOM_HEADERS.
+var reservedHeaders = map[string]bool{
+	"Content-Type":     true,
+	"Content-Encoding": true,
+	"Accept":           true,
+	"X-Account-Id":     true,
+	"X-Message-Type":   true,
+	"X-Event-Type":     true,
+	"X-Ab-Variant":     true,
+	"X-Internal-Token": true,
+	"X-Webhook-Test":   true,
+}
+
+// parseCustomHeaders parses WEBHOOK_CUSTOM_HEADERS, a comma separated list
+// of name:value pairs. Values prefixed with b64: are base64 decoded so they
+// can contain commas. Invalid and reserved entries are logged and skipped.
+func parseCustomHeaders(raw string) http.Header {
+	headers := http.Header{}
+	for _, entry := range strings.Split(raw, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		name, value, ok := strings.Cut(entry, ":")
+		name = textproto.CanonicalMIMEHeaderKey(strings.TrimSpace(name))
+		value = strings.TrimSpace(value)
+		if !ok || name == "" || strings.ContainsAny(name, " \t\r\n") {
+			log.Printf("[Webhook] invalid_custom_header entry=%q", entry)
+			continue
+		}
+		if reservedHeaders[name] {
+			log.Printf("[Webhook] custom_header_conflict header=%s reason=reserved", name)
+			continue
+		}
+		if encoded, isB64 := strings.CutPrefix(value, "b64:"); isB64 {
+			decoded, err := base64.StdEncoding.DecodeString(encoded)
+			if err != nil {
+				log.Printf("[Webhook] invalid_custom_header header=%s err=%v", name, err)
+				continue
+			}
+			value = string(decoded)
+		}
+		if strings.ContainsAny(value, "\r\n") {
+			log.Printf("[Webhook] invalid_custom_header header=%s reason=line_break", name)
+			continue
+		}
+		headers.Set(name, value)
+	}
+	return headers
+}
+
+// headerTransport adds static headers to every request sent through base.
+type headerTransport struct {
+	base    http.RoundTripper
+	headers http.Header
+}
+
+func (t *headerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	for name, values := range t.headers {
+		req.Header[name] = values
+	}
+	return t.base.RoundTrip(req)
+}
@@ -0,0 +1,78 @@
+package webhook
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestParseCustomHeaders(t *testing.T) {
+	headers := parseCustomHeaders(" x-tenant : tinkubot ,Authorization:b64:QmVhcmVyIGEsYg==,Content-Type:text/plain,X-Account-ID:other,broken,X-Bad:b64:!!")
+
+	if got := headers.Get("X-Tenant"); got != "tinkubot" {
+		t.Fatalf("expected X-Tenant tinkubot, got %q", got)
+	}
+	if got := headers.Get("Authorization"); got != "Bearer a,b" {
+		t.Fatalf("expected decoded Authorization, got %q", got)
+	}
+	if len(headers) != 2 {
+		t.Fatalf("expected 2 headers, got %v", headers)
+	}
+}
+
+func TestSendAddsCustomHeaders(t *testing.T) {
+	var got http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.Header.Clone()
+		w.Write([]byte(`{"success":true}`))
+	}))
+	defer server.Close()
+
+	client, err := NewWebhookClient(server.URL, "", "", "", "", "/handle-whatsapp-message", 1000, 0, ClientConfig{
+		CustomHeaders: "X-Tenant:tinkubot,X-Account-ID:spoofed",
+	})
+	if err != nil {
+		t.Fatalf("expected client, got %v", err)
+	}
+	if _, err := client.Send(context.Background(), &WebhookPayload{AccountID: "bot-clientes", Phone: "593999111222", Message: "hola"}); err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	if got.Get("X-Tenant") != "tinkubot" {
+		t.Fatalf("expected X-Tenant header, got %v", got)
+	}
+	if got.Get("X-Account-ID") != "bot-clientes" {
+		t.Fatalf("expected X-Account-ID bot-clientes, got %q", got.Get("X-Account-ID"))
+	}
+}
+
+func TestSendStreamingAddsCustomHeaders(t *testing.T) {
+	var got http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/webhook-capabilities" {
+			w.Write([]byte(`{"streaming":true}`))
+			return
+		}
+		got = r.Header.Clone()
+		w.Header().Set("Content-Type", "application/x-ndjson")
+		w.Write([]byte(`{"response":"hola"}` + "\n"))
+	}))
+	defer server.Close()
+
+	client, err := NewWebhookClient(server.URL, "", "", "", "", "/handle-whatsapp-message", 1000, 0, ClientConfig{
+		CustomHeaders: "X-Tenant:tinkubot",
+		Streaming:     true,
+	})
+	if err != nil {
+		t.Fatalf("expected client, got %v", err)
+	}
+	replies, err := client.SendStreaming(context.Background(), &WebhookPayload{AccountID: "bot-clientes", Phone: "593999111222", Message: "hola"})
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	for range replies {
+	}
+	if got.Get("X-Tenant") != "tinkubot" {
+		t.Fatalf("expected X-Tenant header on the streaming request, got %v", got)
+	}
+}
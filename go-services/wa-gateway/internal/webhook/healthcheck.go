@@ -0,0 +1,68 @@
+package webhook
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+const healthcheckTimeout = 2 * time.Second
+
+// Healthcheck probes GET <url>/health on every configured AI service and
+// returns the outcome keyed by service name; a nil error means healthy.
+func (wc *WebhookClient) Healthcheck(ctx context.Context) map[string]error {
+	services := map[string]string{
+		"ai-clientes":     wc.clientesURL,
+		"ai-proveedores":  wc.proveedoresURL,
+		"onboarding-rust": wc.onboardingRustURL,
+	}
+
+	var (
+		mu      sync.Mutex
+		wg      sync.WaitGroup
+		results = make(map[string]error, len(services))
+	)
+	for service, baseURL := range services {
+		baseURL = strings.TrimRight(strings.TrimSpace(baseURL), "/")
+		if baseURL == "" {
+			continue
+		}
+		wg.Add(1)
+		go func(service, baseURL string) {
+			defer wg.Done()
+			err := wc.checkHealth(ctx, baseURL+"/health")
+			mu.Lock()
+			results[service] = err
+			mu.Unlock()
+		}(service, baseURL)
+	}
+	wg.Wait()
+	return results
+}
+
+func (wc *WebhookClient) checkHealth(ctx context.Context, url string) error {
+	ctx, cancel := context.WithTimeout(ctx, healthcheckTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return fmt.Errorf("create health request: %w", err)
+	}
+	req.Header.Set("User-Agent", "wa-gateway/1.0")
+
+	resp, err := wc.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("health request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 1024))
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("health status=%d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,39 @@
+package webhook
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestHealthcheckProbesConfiguredServices(t *testing.T) {
+	healthy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/health" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer healthy.Close()
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer failing.Close()
+
+	wc, err := NewWebhookClient(healthy.URL, failing.URL, "", "", "", "/handle-whatsapp-message", 1000, 0, ClientConfig{})
+	if err != nil {
+		t.Fatalf("expected client, got %v", err)
+	}
+	results := wc.Healthcheck(context.Background())
+
+	if len(results) != 2 {
+		t.Fatalf("expected only configured services to be probed, got %v", results)
+	}
+	if err := results["ai-clientes"]; err != nil {
+		t.Fatalf("expected ai-clientes healthy, got %v", err)
+	}
+	if err := results["ai-proveedores"]; err == nil || !strings.Contains(err.Error(), "status=503") {
+		t.Fatalf("expected ai-proveedores status error, got %v", err)
+	}
+}
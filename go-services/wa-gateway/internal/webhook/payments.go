@@ -0,0 +1,105 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+)
+
+// PaymentEventReceived is the PaymentEvent.Event value for payment updates.
+const PaymentEventReceived = "payment_received"
+
+// PaymentEvent is a WhatsApp Pay status update. It is sent separately from
+// WebhookPayload because it is not a user message and expects no reply.
+type PaymentEvent struct {
+	Event             string  `json:"event"`
+	AccountID         string  `json:"account_id"`
+	Phone             string  `json:"phone"`
+	MessageID         string  `json:"id,omitempty"`
+	ReferenceID       string  `json:"reference_id,omitempty"`
+	TransactionID     string  `json:"transaction_id,omitempty"`
+	Amount            float64 `json:"amount"`
+	Currency          string  `json:"currency,omitempty"`
+	Status            string  `json:"status"`
+	TransactionStatus string  `json:"transaction_status,omitempty"`
+	Timestamp         string  `json:"timestamp"`
+}
+
+// paymentsURL returns WEBHOOK_PAYMENTS_URL when configured, or the
+// account's regular AI service webhook.
+func (wc *WebhookClient) paymentsURL(event *PaymentEvent) string {
+	if wc.paymentsEndpoint != "" {
+		return wc.paymentsEndpoint
+	}
+	return wc.getURL(&WebhookPayload{AccountID: event.AccountID, Phone: event.Phone})
+}
+
+// SendPayment delivers a payment event with the same retry and circuit
+// breaker policy as Send. Requests carry X-Event-Type: payment so services
+// sharing the message webhook can tell them apart.
+func (wc *WebhookClient) SendPayment(ctx context.Context, event *PaymentEvent) error {
+	jsonData, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("error marshaling payment event: %w", err)
+	}
+	url := wc.paymentsURL(event)
+	if err := wc.postEvent(ctx, url, event.AccountID, "payment", jsonData); err != nil {
+		return err
+	}
+	log.Printf("[Webhook] payment_sent account=%s url=%s transaction_id=%s status=%s", event.AccountID, url, event.TransactionID, event.Status)
+	return nil
+}
+
+// postEvent posts a non-message event to url with X-Event-Type: eventType,
+// retrying like Send and tripping the url's circuit breaker on failures.
+func (wc *WebhookClient) postEvent(ctx context.Context, url, accountID, eventType string, jsonData []byte) error {
+	breaker := wc.breakerFor(url)
+	var lastErr error
+	for attempt := 0; attempt <= wc.retryAttempts; attempt++ {
+		if attempt > 0 {
+			log.Printf("[Webhook] %s_retry %d/%d account=%s", eventType, attempt, wc.retryAttempts, accountID)
+			select {
+			case <-time.After(time.Duration(attempt) * time.Second):
+			case <-ctx.Done():
+				return fmt.Errorf("request canceled while retrying %s webhook: %w", eventType, ctx.Err())
+			}
+		}
+		if err := breaker.allow(); err != nil {
+			log.Printf("[Webhook] circuit_open account=%s url=%s", accountID, url)
+			return fmt.Errorf("%s: %w", url, err)
+		}
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(jsonData))
+		if err != nil {
+			return fmt.Errorf("error creating request: %w", err)
+		}
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("User-Agent", "wa-gateway/1.0")
+		req.Header.Set("X-Account-ID", accountID)
+		req.Header.Set("X-Event-Type", eventType)
+
+		resp, err := wc.textClient.Do(req)
+		if err != nil {
+			breaker.recordFailure()
+			lastErr = fmt.Errorf("request failed: %w", err)
+			continue
+		}
+		resp.Body.Close()
+		if resp.StatusCode >= http.StatusInternalServerError {
+			breaker.recordFailure()
+		} else {
+			breaker.recordSuccess()
+		}
+		if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+			lastErr = fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+			log.Printf("[Webhook] %s_dispatch_failed account=%s url=%s status=%d", eventType, accountID, url, resp.StatusCode)
+			continue
+		}
+		return nil
+	}
+	return fmt.Errorf("failed after %d attempts for %s: %w", wc.retryAttempts+1, accountID, lastErr)
+}
@@ -0,0 +1,81 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestSendPaymentRoutesToPaymentsURL(t *testing.T) {
+	type hit struct {
+		path      string
+		eventType string
+		event     PaymentEvent
+	}
+	var hits []hit
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var event PaymentEvent
+		if err := json.NewDecoder(r.Body).Decode(&event); err != nil {
+			t.Errorf("decode body: %v", err)
+		}
+		hits = append(hits, hit{path: r.URL.Path, eventType: r.Header.Get("X-Event-Type"), event: event})
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	event := &PaymentEvent{
+		Event:         PaymentEventReceived,
+		AccountID:     "bot-clientes",
+		Phone:         "593999111222",
+		TransactionID: "tx-1",
+		Amount:        210,
+		Currency:      "INR",
+		Status:        "captured",
+	}
+
+	withPaymentsURL, err := NewWebhookClient(server.URL, "", "", "", "", "/handle-whatsapp-message", 1000, 0, ClientConfig{
+		PaymentsURL: server.URL + "/payments",
+	})
+	if err != nil {
+		t.Fatalf("expected client, got %v", err)
+	}
+	if err := withPaymentsURL.SendPayment(context.Background(), event); err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	fallback, err := NewWebhookClient(server.URL, "", "", "", "", "/handle-whatsapp-message", 1000, 0, ClientConfig{})
+	if err != nil {
+		t.Fatalf("expected client, got %v", err)
+	}
+	if err := fallback.SendPayment(context.Background(), event); err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	if len(hits) != 2 {
+		t.Fatalf("expected 2 requests, got %d", len(hits))
+	}
+	if hits[0].path != "/payments" || hits[1].path != "/handle-whatsapp-message" {
+		t.Fatalf("expected payments URL then account webhook, got %q and %q", hits[0].path, hits[1].path)
+	}
+	for _, h := range hits {
+		if h.eventType != "payment" || h.event.TransactionID != "tx-1" || h.event.Event != PaymentEventReceived {
+			t.Fatalf("unexpected payment request %+v", h)
+		}
+	}
+}
+
+func TestSendPaymentReportsRejectedEvents(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	wc, err := NewWebhookClient(server.URL, "", "", "", "", "/handle-whatsapp-message", 1000, 0, ClientConfig{})
+	if err != nil {
+		t.Fatalf("expected client, got %v", err)
+	}
+	if err := wc.SendPayment(context.Background(), &PaymentEvent{AccountID: "bot-clientes"}); err == nil {
+		t.Fatal("expected error for rejected payment event")
+	}
+}
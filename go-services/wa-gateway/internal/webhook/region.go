@@ -0,0 +1,125 @@
+package webhook
+
+import (
+	"errors"
+	"sort"
+	"strings"
+)
+
+var (
+	ErrRegionNotFound = errors.New("webhook region not found")
+	ErrInvalidRegion  = errors.New("account id, country code and webhook url must not be empty")
+)
+
+// normalizeRegionURLs trims the account → country → base URL map and
+// upper-cases country codes so lookups match WebhookPayload.CountryCode.
+func normalizeRegionURLs(raw map[string]map[string]string) map[string]map[string]string {
+	normalized := make(map[string]map[string]string, len(raw))
+	for accountID, byCountry := range raw {
+		accountID = strings.TrimSpace(accountID)
+		for country, baseURL := range byCountry {
+			country = strings.ToUpper(strings.TrimSpace(country))
+			baseURL = strings.TrimRight(strings.TrimSpace(baseURL), "/")
+			if accountID == "" || country == "" || baseURL == "" {
+				continue
+			}
+			if normalized[accountID] == nil {
+				normalized[accountID] = map[string]string{}
+			}
+			normalized[accountID][country] = baseURL
+		}
+	}
+	return normalized
+}
+
+// WebhookRegion routes the senders of an account from CountryCode to the AI
+// service instance at WebhookURL.
+type WebhookRegion struct {
+	AccountID   string `json:"account_id"`
+	CountryCode string `json:"country_code"`
+	WebhookURL  string `json:"webhook_url"`
+}
+
+// WebhookRegions lists the regional routes sorted by account and country.
+func (wc *WebhookClient) WebhookRegions() []WebhookRegion {
+	wc.routingMu.RLock()
+	regions := make([]WebhookRegion, 0, len(wc.regionURLs))
+	for accountID, byCountry := range wc.regionURLs {
+		for country, baseURL := range byCountry {
+			regions = append(regions, WebhookRegion{AccountID: accountID, CountryCode: country, WebhookURL: baseURL})
+		}
+	}
+	wc.routingMu.RUnlock()
+	sort.Slice(regions, func(i, j int) bool {
+		if regions[i].AccountID != regions[j].AccountID {
+			return regions[i].AccountID < regions[j].AccountID
+		}
+		return regions[i].CountryCode < regions[j].CountryCode
+	})
+	return regions
+}
+
+// SetWebhookRegion adds or replaces a regional route. Routes are kept in
+// memory: changes made at runtime are lost on restart, when
+// WEBHOOK_REGION_URLS is loaded again.
+func (wc *WebhookClient) SetWebhookRegion(region WebhookRegion) (WebhookRegion, error) {
+	region.AccountID = strings.TrimSpace(region.AccountID)
+	region.CountryCode = strings.ToUpper(strings.TrimSpace(region.CountryCode))
+	region.WebhookURL = strings.TrimRight(strings.TrimSpace(region.WebhookURL), "/")
+	if region.AccountID == "" || region.CountryCode == "" || region.WebhookURL == "" {
+		return WebhookRegion{}, ErrInvalidRegion
+	}
+
+	wc.breakersMu.Lock()
+	wc.registerBreaker(region.AccountID+":"+region.CountryCode, region.WebhookURL)
+	wc.breakersMu.Unlock()
+
+	wc.routingMu.Lock()
+	defer wc.routingMu.Unlock()
+	if wc.regionURLs[region.AccountID] == nil {
+		wc.regionURLs[region.AccountID] = map[string]string{}
+	}
+	wc.regionURLs[region.AccountID][region.CountryCode] = region.WebhookURL
+	return region, nil
+}
+
+// DeleteWebhookRegion sends an account's senders from country back to the
+// default URL.
+func (wc *WebhookClient) DeleteWebhookRegion(accountID, country string) error {
+	country = strings.ToUpper(strings.TrimSpace(country))
+	wc.routingMu.Lock()
+	defer wc.routingMu.Unlock()
+	if _, ok := wc.regionURLs[accountID][country]; !ok {
+		return ErrRegionNotFound
+	}
+	delete(wc.regionURLs[accountID], country)
+	if len(wc.regionURLs[accountID]) == 0 {
+		delete(wc.regionURLs, accountID)
+	}
+	return nil
+}
+
+// regionFor returns the sender country and base URL of the regional AI
+// service instance for payload, if one is configured for its account.
+func (wc *WebhookClient) regionFor(payload *WebhookPayload) (string, string, bool) {
+	country := strings.ToUpper(strings.TrimSpace(payload.CountryCode))
+	if country == "" {
+		return "", "", false
+	}
+	wc.routingMu.RLock()
+	defer wc.routingMu.RUnlock()
+	baseURL, ok := wc.regionURLs[payload.AccountID][country]
+	return country, baseURL, ok
+}
+
+// withWebhookRegion returns a copy of payload tagged with the region that
+// serves it when url is a regional instance, or payload itself otherwise.
+func (wc *WebhookClient) withWebhookRegion(payload *WebhookPayload, url string) *WebhookPayload {
+	region, baseURL, ok := wc.regionFor(payload)
+	if !ok || url != baseURL+wc.endpoint {
+		return payload
+	}
+	tagged := *payload
+	tagged.WebhookRegion = region
+	return &tagged
+}
@@ -0,0 +1,135 @@
+package webhook
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+)
+
+// ErrStreamingUnsupported is returned by SendStreaming when streaming is
+// disabled or the destination service does not advertise it. Callers should
+// fall back to Send.
+var ErrStreamingUnsupported = errors.New("webhook streaming unsupported")
+
+const maxStreamLineBytes = 1 << 20
+
+// StreamChunk is one line of a streamed response. Lines usually carry a reply
+// message; actions and metadata may come on any line (typically the last) and
+// are applied like WebhookResponse.Actions and WebhookResponse.Metadata.
+type StreamChunk struct {
+	ResponseMessage
+	Actions  []GatewayAction   `json:"actions,omitempty"`
+	Metadata map[string]string `json:"metadata,omitempty"`
+}
+
+// HasMessage reports whether the chunk carries a reply to send.
+func (c StreamChunk) HasMessage() bool {
+	return strings.TrimSpace(c.Response) != "" || strings.TrimSpace(c.MediaURL) != "" || len(c.Contacts) > 0 || c.UI != nil
+}
+
+// SendStreaming forwards payload and returns a channel that yields each
+// StreamChunk as soon as the AI service writes it to its newline-delimited
+// JSON response. The channel is closed when the stream ends or ctx is done.
+// Streaming requests are not retried since partial replies may already have
+// been delivered.
+func (wc *WebhookClient) SendStreaming(ctx context.Context, payload *WebhookPayload) (<-chan StreamChunk, error) {
+	if !wc.streaming {
+		return nil, ErrStreamingUnsupported
+	}
+	url := wc.getURL(payload)
+	payload = wc.withWebhookRegion(payload, url)
+	if !wc.capabilitiesFor(ctx, url).Streaming {
+		return nil, ErrStreamingUnsupported
+	}
+
+	isRustOnboarding := wc.isRustOnboardingURL(url)
+	if isRustOnboarding {
+		payload = withNormalizedFromNumber(payload)
+	}
+
+	jsonData, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("error marshaling payload: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewReader(jsonData))
+	if err != nil {
+		return nil, fmt.Errorf("error creating request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/x-ndjson")
+	req.Header.Set("User-Agent", "wa-gateway/1.0")
+	req.Header.Set("X-Account-ID", payload.AccountID)
+	req.Header.Set("X-Message-Type", messageKind(payload))
+	if variant := wc.abVariantFor(payload, url); variant != "" {
+		req.Header.Set("X-Ab-Variant", variant)
+	}
+	if isRustOnboarding && wc.internalToken != "" {
+		req.Header.Set("x-internal-token", wc.internalToken)
+	}
+
+	log.Printf("[Webhook] dispatch_streaming account=%s url=%s from=%s message_type=%s", payload.AccountID, url, payload.FromNumber, payload.MessageType)
+
+	// The client's Timeout would cut long streams short; rely on ctx. Its
+	// transport carries the custom headers and client certificate.
+	streamClient := &http.Client{Transport: wc.clientFor(payload).Transport}
+	breaker := wc.breakerFor(url)
+	if err := breaker.allow(); err != nil {
+		log.Printf("[Webhook] circuit_open account=%s url=%s", payload.AccountID, url)
+		return nil, fmt.Errorf("%s: %w", url, err)
+	}
+	resp, err := streamClient.Do(req)
+	if err != nil {
+		breaker.recordFailure()
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	if resp.StatusCode >= http.StatusInternalServerError {
+		breaker.recordFailure()
+	} else {
+		breaker.recordSuccess()
+	}
+	if resp.StatusCode != http.StatusOK {
+		bodyBytes, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		resp.Body.Close()
+		return nil, fmt.Errorf("unexpected status code: %d body=%q", resp.StatusCode, string(bodyBytes))
+	}
+
+	out := make(chan StreamChunk)
+	go func() {
+		defer close(out)
+		defer resp.Body.Close()
+
+		scanner := bufio.NewScanner(resp.Body)
+		scanner.Buffer(make([]byte, 0, 64*1024), maxStreamLineBytes)
+		count := 0
+		for scanner.Scan() {
+			line := strings.TrimSpace(scanner.Text())
+			if line == "" {
+				continue
+			}
+			var msg StreamChunk
+			if err := json.Unmarshal([]byte(line), &msg); err != nil {
+				log.Printf("[Webhook] stream_decode_failed account=%s url=%s err=%v", payload.AccountID, url, err)
+				continue
+			}
+			select {
+			case out <- msg:
+				count++
+			case <-ctx.Done():
+				return
+			}
+		}
+		if err := scanner.Err(); err != nil {
+			log.Printf("[Webhook] stream_read_failed account=%s url=%s messages=%d err=%v", payload.AccountID, url, count, err)
+			return
+		}
+		log.Printf("[Webhook] stream_completed account=%s url=%s messages=%d", payload.AccountID, url, count)
+	}()
+	return out, nil
+}
@@ -0,0 +1,72 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"time"
+)
+
+// maxTestResponseBytes caps the response body returned by TestWebhook.
+const maxTestResponseBytes = 64 * 1024
+
+// TestWebhookResult is the raw outcome of a TestWebhook dry run.
+type TestWebhookResult struct {
+	URL        string
+	StatusCode int
+	Latency    time.Duration
+	Body       []byte
+}
+
+// TestWebhook posts a synthetic "ping" payload for accountID to the AI
+// service it routes to and returns the raw response. It makes a single
+// attempt and bypasses the circuit breaker so it can be used to verify
+// connectivity while the circuit is open. Requests carry X-Webhook-Test: true.
+func (wc *WebhookClient) TestWebhook(ctx context.Context, accountID string) (*TestWebhookResult, error) {
+	payload := &WebhookPayload{
+		Phone:       "test",
+		Message:     "ping",
+		MessageType: "text",
+		AccountID:   accountID,
+		Timestamp:   time.Now().UTC().Format(time.RFC3339),
+	}
+	url := wc.getURL(payload)
+
+	jsonData, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("error marshaling payload: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(jsonData))
+	if err != nil {
+		return nil, fmt.Errorf("error creating request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "wa-gateway/1.0")
+	req.Header.Set("X-Account-ID", accountID)
+	req.Header.Set("X-Webhook-Test", "true")
+
+	start := time.Now()
+	resp, err := wc.textClient.Do(req)
+	if err != nil {
+		log.Printf("[Webhook] test_failed account=%s url=%s err=%v", accountID, url, err)
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxTestResponseBytes))
+	if err != nil {
+		return nil, fmt.Errorf("error reading response: %w", err)
+	}
+	result := &TestWebhookResult{
+		URL:        url,
+		StatusCode: resp.StatusCode,
+		Latency:    time.Since(start),
+		Body:       body,
+	}
+	log.Printf("[Webhook] test account=%s url=%s status=%d latency_ms=%d", accountID, url, resp.StatusCode, result.Latency.Milliseconds())
+	return result, nil
+}
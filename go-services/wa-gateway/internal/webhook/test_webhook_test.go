@@ -0,0 +1,55 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestTestWebhookSendsSyntheticPing(t *testing.T) {
+	var got WebhookPayload
+	var gotTestHeader string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotTestHeader = r.Header.Get("X-Webhook-Test")
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Errorf("decode body: %v", err)
+		}
+		w.WriteHeader(http.StatusTeapot)
+		_, _ = w.Write([]byte(`{"success":false}`))
+	}))
+	defer server.Close()
+
+	wc, err := NewWebhookClient(server.URL, "", "", "", "", "/handle-whatsapp-message", 1000, 3, ClientConfig{})
+	if err != nil {
+		t.Fatalf("expected client, got %v", err)
+	}
+	result, err := wc.TestWebhook(context.Background(), "bot-clientes")
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	if got.Phone != "test" || got.Message != "ping" || got.AccountID != "bot-clientes" {
+		t.Fatalf("unexpected test payload: %+v", got)
+	}
+	if gotTestHeader != "true" {
+		t.Fatalf("expected X-Webhook-Test header, got %q", gotTestHeader)
+	}
+	if result.URL != server.URL+"/handle-whatsapp-message" || result.StatusCode != http.StatusTeapot {
+		t.Fatalf("unexpected result: %+v", result)
+	}
+	if string(result.Body) != `{"success":false}` {
+		t.Fatalf("expected raw response body, got %q", result.Body)
+	}
+}
+
+func TestTestWebhookReportsTransportErrors(t *testing.T) {
+	wc, err := NewWebhookClient("http://127.0.0.1:1", "", "", "", "", "/handle-whatsapp-message", 1000, 0, ClientConfig{})
+	if err != nil {
+		t.Fatalf("expected client, got %v", err)
+	}
+	if _, err := wc.TestWebhook(context.Background(), "bot-clientes"); err == nil {
+		t.Fatal("expected error for unreachable service")
+	}
+}
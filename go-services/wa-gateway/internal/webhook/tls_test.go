@@ -0,0 +1,114 @@
+package webhook
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// writeClientCert writes a self-signed client certificate and key to dir.
+func writeClientCert(t *testing.T, dir string) (certFile, keyFile string) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "wa-gateway"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("create certificate: %v", err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatalf("marshal key: %v", err)
+	}
+	certFile = filepath.Join(dir, "client.pem")
+	keyFile = filepath.Join(dir, "client-key.pem")
+	if err := os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600); err != nil {
+		t.Fatalf("write cert: %v", err)
+	}
+	if err := os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600); err != nil {
+		t.Fatalf("write key: %v", err)
+	}
+	return certFile, keyFile
+}
+
+func TestWebhookClientMutualTLS(t *testing.T) {
+	var clientCN string
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if len(r.TLS.PeerCertificates) > 0 {
+			clientCN = r.TLS.PeerCertificates[0].Subject.CommonName
+		}
+		w.Write([]byte(`{"success":true}`))
+	}))
+	server.TLS = &tls.Config{ClientAuth: tls.RequireAnyClientCert}
+	server.StartTLS()
+	defer server.Close()
+
+	dir := t.TempDir()
+	caFile := filepath.Join(dir, "ca.pem")
+	caPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	if err := os.WriteFile(caFile, caPEM, 0o600); err != nil {
+		t.Fatalf("write CA: %v", err)
+	}
+	certFile, keyFile := writeClientCert(t, dir)
+
+	client, err := NewWebhookClient(server.URL, "", "", "", "", "/handle-whatsapp-message", 1000, 0, ClientConfig{
+		ClientCertFile: certFile,
+		ClientKeyFile:  keyFile,
+		CACertFile:     caFile,
+	})
+	if err != nil {
+		t.Fatalf("expected client, got %v", err)
+	}
+	if _, err := client.Send(t.Context(), &WebhookPayload{AccountID: "bot-clientes", Phone: "593999111222", Message: "hola"}); err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	if clientCN != "wa-gateway" {
+		t.Fatalf("expected client certificate wa-gateway, got %q", clientCN)
+	}
+}
+
+func TestNewTLSConfig(t *testing.T) {
+	if tlsConfig, err := newTLSConfig(ClientConfig{}); err != nil || tlsConfig != nil {
+		t.Fatalf("expected no TLS config by default, got %v, %v", tlsConfig, err)
+	}
+	if _, err := newTLSConfig(ClientConfig{ClientCertFile: "client.pem"}); err == nil {
+		t.Fatalf("expected error for certificate without key")
+	}
+	tlsConfig, err := newTLSConfig(ClientConfig{TLSSkipVerify: true})
+	if err != nil || !tlsConfig.InsecureSkipVerify {
+		t.Fatalf("expected InsecureSkipVerify, got %v, %v", tlsConfig, err)
+	}
+}
+
+func TestNewWebhookClientFailsOnInvalidCertPath(t *testing.T) {
+	dir := t.TempDir()
+	tests := []ClientConfig{
+		{ClientCertFile: filepath.Join(dir, "missing.pem"), ClientKeyFile: filepath.Join(dir, "missing.key")},
+		{CACertFile: filepath.Join(dir, "missing-ca.pem")},
+	}
+	for _, cfg := range tests {
+		client, err := NewWebhookClient("https://clientes:8001", "", "", "", "", "/handle-whatsapp-message", 1000, 0, cfg)
+		if err == nil || client != nil {
+			t.Fatalf("expected error for %+v, got client=%v err=%v", cfg, client, err)
+		}
+	}
+}
@@ -3,7 +3,9 @@ package webhook
 import (
 	"log"
 	"net/http"
+	"sort"
 	"strings"
+	"sync"
 	"time"
 )
 
@@ -12,14 +14,17 @@ type WebhookPayload struct {
 	Phone          string           `json:"phone"`
 	FromNumber     string           `json:"from_number,omitempty"` // Full JID (user@server) - preserves original server type (lid, s.whatsapp.net, etc.)
 	UserID         string           `json:"user_id,omitempty"`     // BSUID - Business-Scoped User ID
+	PushName       string           `json:"push_name,omitempty"`   // Raw WhatsApp profile name, before formatted_name override
 	DisplayName    string           `json:"display_name,omitempty"`
 	FormattedName  string           `json:"formatted_name,omitempty"`
 	FirstName      string           `json:"first_name,omitempty"`
 	LastName       string           `json:"last_name,omitempty"`
 	Username       string           `json:"username,omitempty"`
-	CountryCode    string           `json:"country_code,omitempty"`
+	CountryCode    string           `json:"country_code,omitempty"` // ISO 3166-1 alpha-2, from Meta or the sender's calling code
+	CountryName    string           `json:"country_name,omitempty"`
 	ContextFrom    string           `json:"context_from,omitempty"`
 	ContextID      string           `json:"context_id,omitempty"`
+	IsForwarded    bool             `json:"is_forwarded,omitempty"`
 	Content        string           `json:"content,omitempty"`
 	Message        string           `json:"message"`
 	MessageType    string           `json:"message_type,omitempty"`
@@ -27,11 +32,45 @@ type WebhookPayload struct {
 	FlowPayload    map[string]any   `json:"flow_payload,omitempty"`
 	Location       *LocationPayload `json:"location,omitempty"`
 	Timestamp      string           `json:"timestamp"`
-	MessageID      string           `json:"id,omitempty"`      // Meta message ID for idempotency
-	AccountID      string           `json:"account_id"`        // "bot-clientes" or "bot-proveedores" - determines routing
+	MessageID      string           `json:"id,omitempty"`       // Meta message ID for idempotency
+	ChatJID        string           `json:"chat_jid,omitempty"` // Conversation JID; always the sender for 1:1 Cloud API chats
+	AccountID      string           `json:"account_id"`         // "bot-clientes" or "bot-proveedores" - determines routing
 	MediaBase64    string           `json:"media_base64,omitempty"`
 	MediaMimetype  string           `json:"media_mimetype,omitempty"`
 	MediaFilename  string           `json:"media_filename,omitempty"`
+	WebhookRegion  string           `json:"webhook_region,omitempty"`
+	SessionID      string           `json:"session_id,omitempty"` // Deterministic per account+sender, rotated every SESSION_ROTATION_INTERVAL_HOURS
+	// PreviousMetadata echoes the key/value pairs the AI service stored for
+	// this conversation through WebhookResponse.Metadata.
+	PreviousMetadata map[string]string `json:"previous_metadata,omitempty"`
+	// Catalog product the user is asking about, from the message context.
+	ProductID        string `json:"product_id,omitempty"`
+	CatalogID        string `json:"catalog_id,omitempty"`
+	BusinessOwnerJID string `json:"business_owner_jid,omitempty"`
+	// Order summary for message_type "order"; OrderJSON is the full cart as
+	// received from Meta.
+	OrderID     string  `json:"order_id,omitempty"`
+	ItemCount   int     `json:"item_count,omitempty"`
+	TotalAmount float64 `json:"total_amount,omitempty"`
+	Currency    string  `json:"currency,omitempty"`
+	OrderJSON   string  `json:"order_json,omitempty"`
+	// Messages lists every message of the chat received within
+	// WEBHOOK_BATCH_WINDOW_MS when batching is enabled; the other fields
+	// describe the latest one.
+	Messages []MessageItem `json:"messages,omitempty"`
+}
+
+// MessageItem is one message of a batched WebhookPayload.
+type MessageItem struct {
+	MessageID      string           `json:"id,omitempty"`
+	MessageType    string           `json:"message_type,omitempty"`
+	Content        string           `json:"content,omitempty"`
+	SelectedOption string           `json:"selected_option,omitempty"`
+	Location       *LocationPayload `json:"location,omitempty"`
+	MediaBase64    string           `json:"media_base64,omitempty"`
+	MediaMimetype  string           `json:"media_mimetype,omitempty"`
+	MediaFilename  string           `json:"media_filename,omitempty"`
+	Timestamp      string           `json:"timestamp"`
 }
 
 type LocationPayload struct {
@@ -90,7 +129,18 @@ type WebhookResponse struct {
 	Success  bool              `json:"success"`
 	Messages []ResponseMessage `json:"messages,omitempty"`
 	UI       *UIConfig         `json:"ui,omitempty"`
+	Actions  []GatewayAction   `json:"actions,omitempty"`
 	Error    string            `json:"error,omitempty"`
+	// Metadata is merged into the conversation's stored metadata; an empty
+	// value deletes the key.
+	Metadata map[string]string `json:"metadata,omitempty"`
+}
+
+// GatewayAction is a command the AI service asks the gateway to execute
+// after its reply messages have been sent.
+type GatewayAction struct {
+	Type   string         `json:"type"`
+	Params map[string]any `json:"params,omitempty"`
 }
 
 // ResponseMessage represents a response message
@@ -101,6 +151,7 @@ type ResponseMessage struct {
 	MediaCaption string    `json:"media_caption,omitempty"`
 	Contacts     []Contact `json:"contacts,omitempty"`
 	UI           *UIConfig `json:"ui,omitempty"`
+	Delay        int       `json:"delay,omitempty"` // Milliseconds to wait before sending, capped by MAX_RESPONSE_DELAY_MS
 }
 
 // WebhookClient manages HTTP webhooks to AI services
@@ -114,9 +165,70 @@ type WebhookClient struct {
 	timeout           int
 	retryAttempts     int
 	httpClient        *http.Client
+	textClient        *http.Client
+	mediaClient       *http.Client
+	breakersMu        sync.Mutex
+	breakers          map[string]*circuitBreaker
+	breakerServices   map[string]string
+	cbThreshold       int
+	cbOpenTimeout     time.Duration
+	compressThreshold int
+	capabilities      *capabilitiesCache
+	streaming         bool
+	debugLogging      bool
+	routingMu         sync.RWMutex // guards regionURLs and abTests
+	regionURLs        map[string]map[string]string
+	abTests           map[string]ABTest
+	paymentsEndpoint  string
 }
 
-// NewWebhookClient creates a new webhook client with dynamic routing
+// ClientConfig holds optional webhook client tuning. Zero values use defaults.
+type ClientConfig struct {
+	CircuitFailureThreshold int
+	CircuitOpenTimeout      time.Duration
+	MaxIdleConnsPerHost     int
+	IdleConnTimeout         time.Duration
+	DisableKeepAlives       bool
+	ResponseHeaderTimeout   time.Duration
+	// CompressThresholdBytes enables gzip request bodies above this size for
+	// services that advertise support. Zero disables compression.
+	CompressThresholdBytes int
+	CapabilitiesPath       string
+	// Streaming enables SendStreaming for services that advertise it.
+	Streaming bool
+	// TextTimeout and MediaTimeout override the request timeout depending on
+	// whether the payload carries media. Zero falls back to the base timeout.
+	TextTimeout  time.Duration
+	MediaTimeout time.Duration
+	// DebugLogging logs the first bytes of non-200 and undecodable response
+	// bodies.
+	DebugLogging bool
+	// RegionURLs maps account ID → ISO country code → AI service base URL.
+	// Messages whose sender country has an entry are routed there instead of
+	// the account's default URL.
+	RegionURLs map[string]map[string]string
+	// ABTests sends a share of each account's senders to a new AI service
+	// version, keyed by account ID.
+	ABTests map[string]ABTest
+	// PaymentsURL receives PaymentEvents. Empty sends them to the account's
+	// regular webhook.
+	PaymentsURL string
+	// CustomHeaders is WEBHOOK_CUSTOM_HEADERS: name:value pairs added to
+	// every request to the AI services; see parseCustomHeaders.
+	CustomHeaders string
+	// ClientCertFile and ClientKeyFile are the PEM client certificate and key
+	// presented to AI services that require mTLS.
+	ClientCertFile string
+	ClientKeyFile  string
+	// CACertFile is a PEM bundle trusted in addition to the system roots.
+	CACertFile string
+	// TLSSkipVerify disables server certificate verification. Only for
+	// testing against self-signed services.
+	TLSSkipVerify bool
+}
+
+// NewWebhookClient creates a new webhook client with dynamic routing. It
+// fails when the TLS files in cfg cannot be loaded.
 func NewWebhookClient(
 	clientesURL,
 	proveedoresURL,
@@ -126,7 +238,8 @@ func NewWebhookClient(
 	endpoint string,
 	timeoutMs,
 	retryAttempts int,
-) *WebhookClient {
+	cfg ClientConfig,
+) (*WebhookClient, error) {
 	timeout := timeoutMs
 	if timeout <= 0 {
 		timeout = 10000
@@ -137,7 +250,24 @@ func NewWebhookClient(
 			rustTestNumbers[normalized] = true
 		}
 	}
-	return &WebhookClient{
+	baseTransport, err := newTransport(cfg)
+	if err != nil {
+		return nil, err
+	}
+	var transport http.RoundTripper = baseTransport
+	if headers := parseCustomHeaders(cfg.CustomHeaders); len(headers) > 0 {
+		transport = &headerTransport{base: transport, headers: headers}
+	}
+	baseTimeout := time.Duration(timeout) * time.Millisecond
+	textTimeout := cfg.TextTimeout
+	if textTimeout <= 0 {
+		textTimeout = baseTimeout
+	}
+	mediaTimeout := cfg.MediaTimeout
+	if mediaTimeout <= 0 {
+		mediaTimeout = baseTimeout
+	}
+	wc := &WebhookClient{
 		clientesURL:       clientesURL,
 		proveedoresURL:    proveedoresURL,
 		onboardingRustURL: onboardingRustURL,
@@ -147,9 +277,166 @@ func NewWebhookClient(
 		timeout:           timeout,
 		retryAttempts:     retryAttempts,
 		httpClient: &http.Client{
-			Timeout: time.Duration(timeout) * time.Millisecond,
+			Timeout:   baseTimeout,
+			Transport: transport,
+		},
+		textClient: &http.Client{
+			Timeout:   textTimeout,
+			Transport: transport,
+		},
+		mediaClient: &http.Client{
+			Timeout:   mediaTimeout,
+			Transport: transport,
 		},
+		breakers:          make(map[string]*circuitBreaker),
+		breakerServices:   make(map[string]string),
+		cbThreshold:       cfg.CircuitFailureThreshold,
+		cbOpenTimeout:     cfg.CircuitOpenTimeout,
+		compressThreshold: cfg.CompressThresholdBytes,
+		capabilities:      newCapabilitiesCache(cfg.CapabilitiesPath),
+		streaming:         cfg.Streaming,
+		debugLogging:      cfg.DebugLogging,
+		regionURLs:        normalizeRegionURLs(cfg.RegionURLs),
+		abTests:           normalizeABTests(cfg.ABTests),
+		paymentsEndpoint:  strings.TrimSpace(cfg.PaymentsURL),
+	}
+	wc.registerBreaker("ai-clientes", clientesURL)
+	wc.registerBreaker("ai-proveedores", proveedoresURL)
+	wc.registerBreaker("onboarding-rust", onboardingRustURL)
+	if wc.paymentsEndpoint != "" {
+		wc.breakers[wc.paymentsEndpoint] = newCircuitBreaker(wc.cbThreshold, wc.cbOpenTimeout)
+		wc.breakerServices[wc.paymentsEndpoint] = "payments"
+	}
+	for accountID, test := range wc.abTests {
+		wc.registerBreaker(accountID+":"+ABVariantVariant, test.VariantURL)
+	}
+	for accountID, byCountry := range wc.regionURLs {
+		for country, baseURL := range byCountry {
+			wc.registerBreaker(accountID+":"+country, baseURL)
+		}
 	}
+	return wc, nil
+}
+
+// clientFor selects the HTTP client whose timeout matches the payload kind.
+func (wc *WebhookClient) clientFor(payload *WebhookPayload) *http.Client {
+	if hasMedia(payload) {
+		return wc.mediaClient
+	}
+	return wc.textClient
+}
+
+// messageKind classifies payloads for the X-Message-Type header.
+func messageKind(payload *WebhookPayload) string {
+	if hasMedia(payload) {
+		return "media"
+	}
+	return "text"
+}
+
+// hasMedia reports whether payload or any of its batched messages carries
+// media.
+func hasMedia(payload *WebhookPayload) bool {
+	if payload.MediaBase64 != "" {
+		return true
+	}
+	for _, item := range payload.Messages {
+		if item.MediaBase64 != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// newTransport builds the pooled transport shared by all AI service calls.
+func newTransport(cfg ClientConfig) (*http.Transport, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	maxIdle := cfg.MaxIdleConnsPerHost
+	if maxIdle <= 0 {
+		maxIdle = 32
+	}
+	idleTimeout := cfg.IdleConnTimeout
+	if idleTimeout <= 0 {
+		idleTimeout = 90 * time.Second
+	}
+	transport.MaxIdleConns = maxIdle * 3
+	transport.MaxIdleConnsPerHost = maxIdle
+	transport.IdleConnTimeout = idleTimeout
+	transport.DisableKeepAlives = cfg.DisableKeepAlives
+	if cfg.ResponseHeaderTimeout > 0 {
+		transport.ResponseHeaderTimeout = cfg.ResponseHeaderTimeout
+	}
+	tlsConfig, err := newTLSConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+	if tlsConfig != nil {
+		transport.TLSClientConfig = tlsConfig
+		if tlsConfig.InsecureSkipVerify {
+			log.Printf("[Webhook] tls_verification_disabled")
+		}
+	}
+	return transport, nil
+}
+
+// registerBreaker creates the circuit breaker for a configured service URL.
+func (wc *WebhookClient) registerBreaker(service, baseURL string) {
+	if strings.TrimSpace(baseURL) == "" {
+		return
+	}
+	url := baseURL + wc.endpoint
+	if _, exists := wc.breakers[url]; exists {
+		return
+	}
+	wc.breakers[url] = newCircuitBreaker(wc.cbThreshold, wc.cbOpenTimeout)
+	wc.breakerServices[url] = service
+}
+
+// breakerFor returns the circuit breaker guarding url, creating one if needed.
+func (wc *WebhookClient) breakerFor(url string) *circuitBreaker {
+	wc.breakersMu.Lock()
+	defer wc.breakersMu.Unlock()
+
+	cb, ok := wc.breakers[url]
+	if !ok {
+		cb = newCircuitBreaker(wc.cbThreshold, wc.cbOpenTimeout)
+		wc.breakers[url] = cb
+		wc.breakerServices[url] = url
+	}
+	return cb
+}
+
+// CircuitStates returns the current state of every AI service circuit breaker.
+func (wc *WebhookClient) CircuitStates() []CircuitStatus {
+	wc.breakersMu.Lock()
+	urls := make([]string, 0, len(wc.breakers))
+	for url := range wc.breakers {
+		urls = append(urls, url)
+	}
+	sort.Strings(urls)
+	breakers := make([]*circuitBreaker, len(urls))
+	services := make([]string, len(urls))
+	for i, url := range urls {
+		breakers[i] = wc.breakers[url]
+		services[i] = wc.breakerServices[url]
+	}
+	wc.breakersMu.Unlock()
+
+	out := make([]CircuitStatus, 0, len(urls))
+	for i, url := range urls {
+		state, failures, openedAt := breakers[i].status()
+		item := CircuitStatus{
+			Service:             services[i],
+			URL:                 url,
+			State:               state,
+			ConsecutiveFailures: failures,
+		}
+		if !openedAt.IsZero() {
+			item.OpenedAt = openedAt.UTC().Format(time.RFC3339)
+		}
+		out = append(out, item)
+	}
+	return out
 }
 
 // isRustTestNumber checks whether the given phone is configured for Rust onboarding.
@@ -161,7 +448,8 @@ func (wc *WebhookClient) isRustTestNumber(fromNumber string) bool {
 	return wc.rustTestNumbers[normalized]
 }
 
-// getURL determines the URL based on the account_id and onboarding routing rules.
+// getURL determines the URL based on the account_id, onboarding routing rules,
+// regional instances configured for the sender country and A/B tests.
 func (wc *WebhookClient) getURL(payload *WebhookPayload) string {
 	if payload.AccountID == "bot-proveedores" && wc.isRustTestNumber(payload.FromNumber) {
 		if wc.onboardingRustURL != "" {
@@ -171,10 +459,22 @@ func (wc *WebhookClient) getURL(payload *WebhookPayload) string {
 		log.Printf("[Webhook] WARNING: Rust URL not configured, falling back to Python: from=%s", payload.FromNumber)
 	}
 
-	if payload.AccountID == "bot-clientes" {
+	if _, baseURL, ok := wc.regionFor(payload); ok {
+		return baseURL + wc.endpoint
+	}
+	if url, ok := wc.abVariantURL(payload); ok {
+		return url
+	}
+	return wc.defaultURL(payload.AccountID)
+}
+
+// defaultURL returns the AI service URL of an account when no onboarding,
+// region or A/B variant route applies.
+func (wc *WebhookClient) defaultURL(accountID string) string {
+	if accountID == "bot-clientes" {
 		return wc.clientesURL + wc.endpoint
 	}
-	if payload.AccountID == "bot-proveedores" {
+	if accountID == "bot-proveedores" {
 		return wc.proveedoresURL + wc.endpoint
 	}
 	// Default: use providers
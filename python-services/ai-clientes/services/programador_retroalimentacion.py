@@ -184,12 +184,23 @@ class ProgramadorRetroalimentacion:
         except Exception:
             cuerpo = {}
 
-        mensaje = str(cuerpo.get("message") or "")
+        if not isinstance(cuerpo, dict):
+            cuerpo = {}
+        # wa-gateway responde con el sobre {success, data, error, code}; los
+        # detalles del límite vienen en data. Se aceptan también los campos
+        # de primer nivel del formato anterior.
+        datos = cuerpo.get("data") if isinstance(cuerpo.get("data"), dict) else {}
+        mensaje = str(cuerpo.get("error") or cuerpo.get("message") or "")
+        codigo = str(cuerpo.get("code") or "")
         contenido = f"{texto} {mensaje}".lower()
-        if "rate limit" not in contenido and "limit exceeded" not in contenido:
+        if (
+            codigo != "RATE_LIMIT_EXCEEDED"
+            and "rate limit" not in contenido
+            and "limit exceeded" not in contenido
+        ):
             return None
 
-        retry_at_raw = cuerpo.get("retry_at")
+        retry_at_raw = datos.get("retry_at", cuerpo.get("retry_at"))
         if isinstance(retry_at_raw, str):
             try:
                 return datetime.fromisoformat(
@@ -198,7 +209,7 @@ class ProgramadorRetroalimentacion:
             except ValueError:
                 pass
 
-        retry_after = cuerpo.get("retry_after")
+        retry_after = datos.get("retry_after", cuerpo.get("retry_after"))
         if isinstance(retry_after, int) and retry_after > 0:
             return datetime.now(timezone.utc) + timedelta(seconds=retry_after)
 
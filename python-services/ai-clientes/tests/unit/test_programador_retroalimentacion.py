@@ -1,7 +1,9 @@
 """Regresiones para el programador de retroalimentación."""
 
+from datetime import datetime, timezone
 from types import SimpleNamespace
 
+import httpx
 import pytest
 from services.programador_retroalimentacion import ProgramadorRetroalimentacion
 
@@ -74,3 +76,30 @@ async def test_programar_solicitud_retroalimentacion_usa_primer_nombre():
         "Por favor elige una opción de la lista."
     )
     assert payload["payload"]["ui"]["options"][-1]["id"] == "prefer_not_to_answer"
+
+
+def test_extraer_retry_at_lee_sobre_de_respuesta_del_gateway():
+    programador = ProgramadorRetroalimentacion(
+        supabase=None,
+        repositorio_flujo=_RepoFlujoFalso(),
+        whatsapp_url="http://whatsapp.local",
+        whatsapp_account_id="bot-clientes",
+        retraso_retroalimentacion_segundos=30,
+        intervalo_sondeo_tareas_segundos=5,
+        logger=SimpleNamespace(info=lambda *args, **kwargs: None),
+    )
+    respuesta = httpx.Response(
+        429,
+        json={
+            "success": False,
+            "error": "hourly limit exceeded",
+            "code": "RATE_LIMIT_EXCEEDED",
+            "data": {"retry_after": 120, "retry_at": "2026-10-15T15:30:00Z"},
+            "request_id": "abc",
+            "timestamp": "2026-10-15T15:28:00Z",
+        },
+    )
+
+    retry_at = programador._extraer_retry_at_rate_limit(respuesta)
+
+    assert retry_at == datetime(2026, 10, 15, 15, 30, tzinfo=timezone.utc)